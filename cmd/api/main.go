@@ -8,17 +8,11 @@
 package main
 
 import (
-	"encoding/base64"
-	"fmt"
+	"context"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"regexp"
-	"runtime"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -31,9 +25,15 @@ import (
 
 	"photobooth-core/internal/domain"
 	"photobooth-core/internal/middleware"
+	"photobooth-core/internal/platform/auth"
 	"photobooth-core/internal/platform/config"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/payment"
 	"photobooth-core/internal/platform/postgres"
+	"photobooth-core/internal/platform/printing"
 	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/storage"
+	"photobooth-core/internal/platform/utils"
 
 	// MODULE: Booth (Scaffolded)
 	bHandler "photobooth-core/internal/booth/handler"
@@ -51,6 +51,56 @@ import (
 	trHandler "photobooth-core/internal/transaction/handler"
 	trRepo "photobooth-core/internal/transaction/repository"
 	trUcase "photobooth-core/internal/transaction/usecase"
+
+	mHandler "photobooth-core/internal/media/handler"
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+
+	fHandler "photobooth-core/internal/frame/handler"
+	fRepo "photobooth-core/internal/frame/repository"
+	fUcase "photobooth-core/internal/frame/usecase"
+
+	aHandler "photobooth-core/internal/animation/handler"
+	aUcase "photobooth-core/internal/animation/usecase"
+
+	bgHandler "photobooth-core/internal/background/handler"
+	bgRepo "photobooth-core/internal/background/repository"
+	bgUcase "photobooth-core/internal/background/usecase"
+
+	flHandler "photobooth-core/internal/filter/handler"
+	flRepo "photobooth-core/internal/filter/repository"
+	flUcase "photobooth-core/internal/filter/usecase"
+
+	pHandler "photobooth-core/internal/print/handler"
+	pRepo "photobooth-core/internal/print/repository"
+	pUcase "photobooth-core/internal/print/usecase"
+
+	// MODULE: Printer
+	prHandler "photobooth-core/internal/printer/handler"
+	prRepo "photobooth-core/internal/printer/repository"
+	prUcase "photobooth-core/internal/printer/usecase"
+
+	// MODULE: Notification
+	nHandler "photobooth-core/internal/notification/handler"
+	nRepo "photobooth-core/internal/notification/repository"
+	nUcase "photobooth-core/internal/notification/usecase"
+
+	// MODULE: Pricing
+	pkgHandler "photobooth-core/internal/pricing/handler"
+	pkgRepo "photobooth-core/internal/pricing/repository"
+	pkgUcase "photobooth-core/internal/pricing/usecase"
+
+	// MODULE: Payment
+	payHandler "photobooth-core/internal/payment/handler"
+	payRepo "photobooth-core/internal/payment/repository"
+	payUcase "photobooth-core/internal/payment/usecase"
+
+	// MODULE: Idempotency
+	iRepo "photobooth-core/internal/idempotency/repository"
+
+	sHandler "photobooth-core/internal/shortlink/handler"
+	sRepo "photobooth-core/internal/shortlink/repository"
+	sUcase "photobooth-core/internal/shortlink/usecase"
 )
 
 func main() {
@@ -69,8 +119,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// INFRASTRUCTURE: Object Storage
+	store, err := storage.New(cfg)
+	if err != nil {
+		slog.Error("Kritikal: Gagal menyiapkan storage", "driver", cfg.StorageDriver, "error", err)
+		os.Exit(1)
+	}
+
 	// migration
-	db.AutoMigrate(&domain.Tenant{}, &domain.User{}, &domain.Booth{}, &domain.Transaction{})
+	postgres.DropGlobalReferenceUnique(db)
+	db.AutoMigrate(&domain.Tenant{}, &domain.User{}, &domain.Booth{}, &domain.Transaction{}, &domain.Photo{}, &domain.PhotoRendition{}, &domain.Frame{}, &domain.FrameVersion{}, &domain.FrameAssignment{}, &domain.Filter{}, &domain.Background{}, &domain.ShortLink{}, &domain.ShortLinkScan{}, &domain.Upload{}, &domain.UploadChunk{}, &domain.PrintJob{}, &domain.ReprintDenial{}, &domain.ReprintPINCounter{}, &domain.Printer{}, &domain.Notification{}, &domain.ReceiptTemplate{}, &domain.TransactionEvent{}, &domain.IdempotencyRecord{}, &domain.Payment{}, &domain.PricingPackage{}, &domain.PackageAssignment{})
+	postgres.MigrateTransactionStatus(db)
+	postgres.EnsureActiveSessionIndex(db)
 	postgres.SeedAdmin(db)
 
 	// WIRING: Dependency Injection (User & Tenant)
@@ -79,7 +139,7 @@ func main() {
 	userHandler := uHandler.NewUserHandler(userUsecase)
 
 	tenantRepository := tRepo.NewTenantRepository(db)
-	tenantUsecase := tUcase.NewTenantUsecase(tenantRepository, userRepository, db)
+	tenantUsecase := tUcase.NewTenantUsecase(tenantRepository, userRepository, db, store)
 	tenantHandler := tHandler.NewTenantHandler(tenantUsecase)
 
 	// WIRING: Dependency Injection (Booth Module)
@@ -87,11 +147,139 @@ func main() {
 	boothUsecase := bUcase.NewBoothUsecase(boothRepository)
 	boothHandler := bHandler.NewBoothHandler(boothUsecase)
 
+	// paket harga per tenant, dipilih tamu di booth
+	packageUsecase := pkgUcase.NewPackageUsecase(pkgRepo.NewPackageRepository(db), boothRepository)
+	packageHandler := pkgHandler.NewPackageHandler(packageUsecase)
+
 	// transaction
 	trxRepo := trRepo.NewTransactionRepository(db)
-	trxUcase := trUcase.NewTransactionUsecase(trxRepo)
+	trxUcase := trUcase.NewTransactionUsecase(trxRepo, packageUsecase)
 	trxHandler := trHandler.NewTransactionHandler(trxUcase)
 
+	// media
+	photoRepository := mRepo.NewPhotoRepository(db)
+	urlSigner := auth.NewURLSigner(cfg.SignedURLSecret)
+	renditionUsecase := mUcase.NewRenditionUsecase(photoRepository, mRepo.NewRenditionRepository(db), store, mUcase.RenditionConfig{
+		WebP:        cfg.RenditionWebP,
+		JPEGQuality: 85,
+		Workers:     cfg.RenditionWorkers,
+	})
+	mediaUsecase := mUcase.NewMediaUsecase(photoRepository, trxRepo, tenantRepository, store, urlSigner, renditionUsecase, mUcase.Config{
+		BaseURL:        cfg.PublicBaseURL,
+		Retention:      cfg.PhotoRetention,
+		MaxUploadBytes: cfg.UploadMaxBytes,
+		ImageLimits: imaging.Limits{
+			MaxWidth:  cfg.ImageMaxWidth,
+			MaxHeight: cfg.ImageMaxHeight,
+			MaxPixels: cfg.ImageMaxPixels,
+		},
+		JPEGQuality: cfg.ImageJPEGQuality,
+	})
+	mediaHandler := mHandler.NewMediaHandler(mediaUsecase)
+
+	uploadRepository := mRepo.NewUploadRepository(db)
+	uploadUsecase := mUcase.NewResumableUploadUsecase(uploadRepository, mediaUsecase, store, cfg.UploadTTL)
+	uploadHandler := mHandler.NewUploadHandler(uploadUsecase)
+
+	// filter (warna & LUT di server)
+	filterRepository := flRepo.NewFilterRepository(db)
+	filterUsecase := flUcase.NewFilterUsecase(filterRepository, photoRepository, mediaUsecase, store)
+	filterHandler := flHandler.NewFilterHandler(filterUsecase)
+
+	// background (latar green screen)
+	backgroundRepository := bgRepo.NewBackgroundRepository(db)
+	backgroundUsecase := bgUcase.NewBackgroundUsecase(backgroundRepository, photoRepository, store)
+	backgroundHandler := bgHandler.NewBackgroundHandler(backgroundUsecase)
+
+	// notification (pemberitahuan untuk tenant)
+	notificationRepository := nRepo.NewNotificationRepository(db)
+	notificationUsecase := nUcase.NewNotificationUsecase(notificationRepository)
+	notificationHandler := nHandler.NewNotificationHandler(notificationUsecase)
+
+	// printer (printer per booth & sisa media)
+	printerRepository := prRepo.NewPrinterRepository(db)
+	printerUsecase := prUcase.NewPrinterUsecase(printerRepository, boothRepository, notificationUsecase)
+	printerHandler := prHandler.NewPrinterHandler(printerUsecase)
+
+	// print (lembar cetak & antrean cetak)
+	printConfig := printing.Config{
+		Driver:      cfg.PrintDriver,
+		URI:         cfg.PrintIPPURI,
+		Queue:       cfg.PrintQueue,
+		SumatraPath: cfg.SumatraPath,
+	}
+	printJobRepository := pRepo.NewPrintJobRepository(db)
+	printUsecase := pUcase.NewPrintUsecase(printJobRepository, photoRepository, mediaUsecase, boothRepository, trxRepo, tenantRepository, printerUsecase, store, printConfig)
+	printHandler := pHandler.NewPrintHandler(printUsecase)
+
+	// frame (template strip foto & render server)
+	frameRepository := fRepo.NewFrameRepository(db)
+	frameUsecase := fUcase.NewFrameUsecase(frameRepository, photoRepository, trxRepo, boothRepository, tenantRepository, mediaUsecase, filterUsecase, backgroundUsecase, store)
+	frameHandler := fHandler.NewFrameHandler(frameUsecase)
+
+	// short link (QR)
+	shortLinkRepository := sRepo.NewShortLinkRepository(db)
+	shortLinkUsecase := sUcase.NewShortLinkUsecase(shortLinkRepository, mediaUsecase, trxRepo, boothRepository, tenantRepository, store, cfg.PublicBaseURL)
+	shortLinkHandler := sHandler.NewShortLinkHandler(shortLinkUsecase)
+
+	// struk sesi (ESC/POS, teks, PDF)
+	receiptRepo := trRepo.NewReceiptTemplateRepository(db)
+	receiptUcase := trUcase.NewReceiptUsecase(receiptRepo, trxRepo, boothRepository, tenantRepository, shortLinkUsecase)
+	receiptHandler := trHandler.NewReceiptHandler(receiptUcase)
+
+	// pembayaran QRIS lewat payment gateway
+	paymentProvider, err := payment.New(payment.Config{
+		Provider:      cfg.PaymentProvider,
+		BaseURL:       cfg.PaymentBaseURL,
+		ServerKey:     cfg.PaymentServerKey,
+		WebhookSecret: cfg.PaymentWebhookSecret,
+	})
+	if err != nil {
+		slog.Warn("Payment gateway tidak aktif", "provider", cfg.PaymentProvider, "error", err)
+	}
+	paymentUsecase := payUcase.NewPaymentUsecase(payRepo.NewPaymentRepository(db), trxUcase, paymentProvider, cfg.PaymentQRTTL)
+	paymentHandler := payHandler.NewPaymentHandler(paymentUsecase)
+
+	// idempotency key untuk request mutasi dari mesin booth
+	idempotencyRepository := iRepo.NewIdempotencyRepository(db)
+
+	// animation (GIF / boomerang dari burst)
+	animationUsecase := aUcase.NewAnimationUsecase(photoRepository, trxRepo, frameRepository, mediaUsecase, shortLinkUsecase, filterUsecase, store)
+	animationHandler := aHandler.NewAnimationHandler(animationUsecase)
+
+	// BACKGROUND JOBS
+	renditionUsecase.Start(context.Background())
+	printUsecase.Start(context.Background())
+	go utils.RunEvery(context.Background(), "requeue_pending_renditions", 5*time.Minute, func(ctx context.Context) error {
+		n, err := renditionUsecase.RequeuePending(ctx)
+		if n > 0 {
+			slog.Info("Foto pending dimasukkan lagi ke antrean rendition", "count", n)
+		}
+		return err
+	})
+	go utils.RunEvery(context.Background(), "purge_expired_uploads", 10*time.Minute, func(ctx context.Context) error {
+		n, err := uploadUsecase.PurgeExpired(ctx)
+		if n > 0 {
+			slog.Info("Upload kedaluwarsa dibersihkan", "count", n)
+		}
+		return err
+	})
+
+	go utils.RunEvery(context.Background(), "fail_stale_sessions", time.Minute, func(ctx context.Context) error {
+		n, err := trxUcase.FailStale(cfg.SessionTimeout, cfg.SessionPaidTimeout)
+		if n > 0 {
+			slog.Info("Sesi foto yang terbengkalai ditandai failed", "count", n)
+		}
+		return err
+	})
+	go utils.RunEvery(context.Background(), "purge_expired_idempotency_keys", time.Hour, func(ctx context.Context) error {
+		n, err := idempotencyRepository.PurgeExpired(time.Now())
+		if n > 0 {
+			slog.Info("Idempotency key kedaluwarsa dibersihkan", "count", n)
+		}
+		return err
+	})
+
 	// ROUTER SETUP
 	if os.Getenv("APP_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -102,13 +290,8 @@ func main() {
 	r.Use(middleware.GlobalRecovery())
 	r.Use(middleware.CORS())
 
-	r.Use(func(c *gin.Context) {
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 15 << 20)
-		c.Next()
-	})
-
-	// STATIC SERVING: Agar file di storage bisa diakses via browser/QR Code
-	r.Static("/storage", "./storage")
+	// Upload multipart dibatasi per tenant di usecase, bukan oleh limit global
+	r.Use(middleware.BodyLimit(15<<20, "/api/v1/photos/upload"))
 
 	// Swagger Route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -124,6 +307,9 @@ func main() {
 		response.Success(c, http.StatusOK, "System is UP", nil)
 	})
 
+	// SHORT LINK: tujuan QR code yang dicetak di strip foto
+	r.GET("/s/:code", shortLinkHandler.Redirect)
+
 	// API VERSION 1
 	v1 := r.Group("/api/v1")
 	{
@@ -131,125 +317,128 @@ func main() {
 		v1.POST("/login", userHandler.Login)
 		v1.POST("/booths/pair", boothHandler.Pair)
 
-		v1.POST("/save-history", func(c *gin.Context) {
-			// Batasi ukuran body (Misal: max 10MB) agar server tidak hang
-			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20) 
-
-			var input struct {
-				Image     string `json:"image"`
-				FrameName string `json:"frameName"`
-			}
-
-			if err := c.ShouldBindJSON(&input); err != nil {
-				slog.Error("Gagal bind JSON history", "error", err)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Payload terlalu besar atau format salah"})
-				return
-			}
-
-			// Setup Folder
-			storagePath := "./storage/history"
-			if err := os.MkdirAll(storagePath, os.ModePerm); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat folder storage"})
-				return
-			}
-
-			// Decode Base64
-			idx := strings.Index(input.Image, ",")
-			if idx == -1 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Format gambar tidak valid"})
-				return
-			}
-			
-			rawB64 := input.Image[idx+1:]
-			data, err := base64.StdEncoding.DecodeString(rawB64)
-			if err != nil {
-				slog.Error("Gagal decode base64", "error", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses gambar"})
-				return
-			}
-
-			// Penamaan File
-			timestamp := time.Now().Unix()
-			reg := regexp.MustCompile("[^a-zA-Z0-9]+")
-			cleanFrameName := reg.ReplaceAllString(input.FrameName, "_")
-			
-			fileName := fmt.Sprintf("%d_%s.jpg", timestamp, strings.ToLower(cleanFrameName))
-			filePath := filepath.Join(storagePath, fileName)
-
-			// Simpan ke Disk
-			if err := os.WriteFile(filePath, data, 0644); err != nil {
-				slog.Error("Gagal menulis file", "error", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan file"})
-				return
-			}
-
-			slog.Info("History saved successfully", "file", fileName)
-			c.JSON(http.StatusOK, gin.H{
-				"status": "success",
-				"file":   fileName,
-				"path":   "/storage/history/" + fileName, // Path relative untuk QR Code
-			})
-		})
+		// PUBLIC FILES: hanya bisa diakses lewat link bertanda tangan (QR Code)
+		v1.GET("/files/photos/:id", mediaHandler.Download)
+		v1.GET("/files/sessions/:id", mediaHandler.SessionGallery)
+		v1.GET("/files/booths/:id/gallery", mediaHandler.EventGallery)
+
+		// WEBHOOK: dipanggil payment gateway, diautentikasi lewat tanda tangan
+		v1.POST("/payments/qris/webhook", paymentHandler.Webhook)
 
 		// AUTHORIZED ROUTES
 		authorized := v1.Group("/")
 		authorized.Use(middleware.AuthMiddleware())
+		authorized.Use(middleware.Idempotency(idempotencyRepository, 24*time.Hour, "/api/v1/photos/upload"))
 		{
 			authorized.POST("/booths", boothHandler.Register)
 			authorized.GET("/booths", boothHandler.GetAllBooth)
+			authorized.PUT("/booths/:id/group", middleware.StaffOnly(), boothHandler.SetGroup)
+			authorized.GET("/booths/:id/printer", middleware.StaffOnly(), printerHandler.Get)
+			authorized.PUT("/booths/:id/printer", middleware.StaffOnly(), printerHandler.Set)
+			authorized.DELETE("/booths/:id/printer", middleware.StaffOnly(), printerHandler.Remove)
+			authorized.POST("/booths/:id/printer/reload", middleware.StaffOnly(), printerHandler.Reload)
+			authorized.GET("/booths/me/frames", middleware.DeviceOnly(), frameHandler.CurrentFrames)
+			authorized.GET("/booths/me/packages", middleware.DeviceOnly(), packageHandler.BoothPackages)
+			authorized.GET("/booths/me/printer", middleware.DeviceOnly(), printHandler.PrinterStatus)
+			authorized.POST("/booths/me/printer/status", middleware.DeviceOnly(), printerHandler.Report)
+			authorized.GET("/booths/me/printer/jobs/:job_id", middleware.DeviceOnly(), printHandler.JobStatus)
 			authorized.POST("/transactions/session", middleware.DeviceOnly(), trxHandler.StartSession)
+			authorized.GET("/transactions/active", middleware.DeviceOnly(), trxHandler.ActiveSession)
+			authorized.GET("/transactions/:id", trxHandler.GetSession)
+			authorized.POST("/transactions/:id/status", trxHandler.Transition)
+			authorized.GET("/transactions/:id/history", trxHandler.History)
+			authorized.POST("/transactions/:id/payment", middleware.DeviceOnly(), paymentHandler.CreateCharge)
+			authorized.GET("/transactions/:id/payment", paymentHandler.GetPayment)
+			authorized.GET("/transactions/:id/photos", mediaHandler.ListSessionPhotos)
+			authorized.GET("/transactions/:id/qr", shortLinkHandler.SessionQR)
+			authorized.POST("/transactions/:id/render", middleware.DeviceOnly(), frameHandler.RenderSession)
+			authorized.POST("/transactions/:id/animation", middleware.DeviceOnly(), animationHandler.Create)
+			authorized.GET("/transactions/:id/receipt", receiptHandler.Receipt)
+			authorized.GET("/receipt-template", receiptHandler.GetTemplate)
+			authorized.PUT("/receipt-template", middleware.StaffOnly(), receiptHandler.UpdateTemplate)
+
+			// MEDIA
+			authorized.POST("/save-history", middleware.DeviceOnly(), mediaHandler.SaveHistory)
+			authorized.POST("/photos/upload", middleware.DeviceOnly(), mediaHandler.Upload)
+			authorized.GET("/photos", mediaHandler.ListPhotos)
+
+			// RESUMABLE UPLOADS (tus-style)
+			uploads := authorized.Group("/uploads", middleware.DeviceOnly())
+			uploads.POST("", uploadHandler.Create)
+			uploads.HEAD("/:id", uploadHandler.Head)
+			uploads.PATCH("/:id", uploadHandler.Patch)
+			uploads.POST("/:id/finalize", uploadHandler.Finalize)
+			authorized.GET("/photos/:id", mediaHandler.GetPhoto)
+			authorized.GET("/photos/:id/qr", shortLinkHandler.PhotoQR)
+			authorized.POST("/photos/:id/filter", filterHandler.ApplyToPhoto)
+			authorized.GET("/photos/:id/print-sheet", printHandler.Sheet)
+			authorized.POST("/photos/:id/print", middleware.DeviceOnly(), printHandler.Print)
+
+			// LEGACY PRINT ROUTE (deprecated: base64 JSON)
+			authorized.POST("/print", middleware.DeviceOnly(), printHandler.Legacy)
+
+			// PRINT QUEUE
+			authorized.GET("/print-jobs", printHandler.ListJobs)
+			authorized.GET("/print-jobs/:id", printHandler.GetJob)
+			authorized.POST("/print-jobs/:id/cancel", middleware.StaffOnly(), printHandler.CancelJob)
+			authorized.POST("/print-jobs/:id/requeue", middleware.StaffOnly(), printHandler.RequeueJob)
+			authorized.GET("/reports/prints", printHandler.Report)
+
+			// SHORT LINKS
+			authorized.POST("/shortlinks", shortLinkHandler.Create)
+			authorized.GET("/shortlinks", shortLinkHandler.List)
+			authorized.GET("/shortlinks/stats", shortLinkHandler.Stats)
+			authorized.GET("/shortlinks/:code/qr", shortLinkHandler.LinkQR)
+
+			// FRAMES
+			authorized.POST("/frames", middleware.StaffOnly(), frameHandler.Create)
+			authorized.GET("/frames", frameHandler.List)
+			authorized.GET("/frames/:id", frameHandler.Get)
+			authorized.PUT("/frames/:id", middleware.StaffOnly(), frameHandler.Update)
+			authorized.DELETE("/frames/:id", middleware.StaffOnly(), frameHandler.Archive)
+			authorized.GET("/frames/:id/versions", frameHandler.Versions)
+			authorized.POST("/frames/:id/versions/:version/publish", middleware.StaffOnly(), frameHandler.Publish)
+			authorized.GET("/frames/:id/preview", frameHandler.Preview)
+			authorized.POST("/frames/:id/assignments", middleware.StaffOnly(), frameHandler.Assign)
+			authorized.GET("/frames/:id/assignments", frameHandler.Assignments)
+			authorized.DELETE("/frames/:id/assignments/:assignment_id", middleware.StaffOnly(), frameHandler.Unassign)
+
+			// PAKET HARGA
+			authorized.POST("/packages", middleware.StaffOnly(), packageHandler.Create)
+			authorized.GET("/packages", packageHandler.List)
+			authorized.GET("/packages/:id", packageHandler.Get)
+			authorized.PUT("/packages/:id", middleware.StaffOnly(), packageHandler.Update)
+			authorized.DELETE("/packages/:id", middleware.StaffOnly(), packageHandler.Archive)
+			authorized.POST("/packages/:id/assignments", middleware.StaffOnly(), packageHandler.Assign)
+			authorized.GET("/packages/:id/assignments", packageHandler.Assignments)
+			authorized.DELETE("/packages/:id/assignments/:assignment_id", middleware.StaffOnly(), packageHandler.Unassign)
+
+			// FILTERS
+			authorized.POST("/filters", middleware.StaffOnly(), filterHandler.Create)
+			authorized.GET("/filters", filterHandler.List)
+			authorized.GET("/filters/:id", filterHandler.Get)
+			authorized.DELETE("/filters/:id", middleware.StaffOnly(), filterHandler.Archive)
+
+			// BACKGROUNDS (green screen)
+			authorized.POST("/backgrounds", middleware.StaffOnly(), backgroundHandler.Upload)
+			authorized.GET("/backgrounds", backgroundHandler.List)
+			authorized.POST("/backgrounds/preview", backgroundHandler.Preview)
+			authorized.GET("/backgrounds/:id", backgroundHandler.Get)
+			authorized.DELETE("/backgrounds/:id", middleware.StaffOnly(), backgroundHandler.Archive)
+
+			// TENANT
+			authorized.PUT("/tenants/logo", middleware.StaffOnly(), tenantHandler.UploadLogo)
+			authorized.PUT("/tenants/reprint-pin", middleware.StaffOnly(), tenantHandler.SetReprintPIN)
+
+			// NOTIFICATIONS
+			authorized.GET("/notifications", notificationHandler.List)
+			authorized.POST("/notifications/:id/read", notificationHandler.MarkRead)
 		}
 	}
 
-	// LEGACY PRINT ROUTE
-	v1.POST("/print", func(c *gin.Context) {
-		var input struct {
-			Image string `json:"image"`
-		}
-		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(400, gin.H{"error": err.Error()})
-			return
-		}
-
-		b64data := input.Image[strings.IndexByte(input.Image, ',')+1:]
-		data, _ := base64.StdEncoding.DecodeString(b64data)
-
-		tmpFile := "temp_print.jpg"
-		_ = os.WriteFile(tmpFile, data, 0644)
-
-		if err := executePrint(tmpFile); err != nil {
-			c.JSON(500, gin.H{"status": "error", "detail": err.Error()})
-			return
-		}
-		c.JSON(200, gin.H{"status": "success", "message": "Printing started"})
-	})
-
 	// SERVER STARTUP
 	slog.Info("Server Photobooth berjalan", "port", cfg.AppPort)
 	if err := r.Run(":" + cfg.AppPort); err != nil {
 		log.Fatalf("Gagal menjalankan server: %v", err)
 	}
 }
-
-func executePrint(filePath string) error {
-	if runtime.GOOS == "windows" {
-		printerName := "Brother HL-L5100DN series"
-		absImagePath, _ := filepath.Abs(filePath)
-		if _, err := os.Stat(absImagePath); os.IsNotExist(err) {
-			return fmt.Errorf("File gambar tidak ditemukan")
-		}
-
-		sumatraPath, _ := filepath.Abs("./SumatraPDF.exe")
-		cmd := exec.Command(sumatraPath,
-			"-print-to", printerName,
-			"-print-settings", "fit,paper=A4",
-			absImagePath,
-		)
-
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("SumatraPDF Error: %s, Output: %s", err, string(output))
-		}
-	}
-	return nil
-}
\ No newline at end of file
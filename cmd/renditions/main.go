@@ -0,0 +1,70 @@
+// Command renditions membuat ulang thumbnail dan ukuran web foto yang sudah
+// tersimpan, misalnya setelah ukuran rendition diubah atau WebP diaktifkan.
+//
+//	go run ./cmd/renditions -tenant <uuid> -missing
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/config"
+	"photobooth-core/internal/platform/postgres"
+	"photobooth-core/internal/platform/storage"
+
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	tenant := flag.String("tenant", "", "hanya proses foto milik tenant ini (kosong = semua tenant)")
+	missing := flag.Bool("missing", false, "lewati foto yang rendition-nya sudah lengkap")
+	flag.Parse()
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	cfg := config.LoadConfig()
+
+	var tenantID *uuid.UUID
+	if *tenant != "" {
+		id, err := uuid.Parse(*tenant)
+		if err != nil {
+			slog.Error("Tenant ID tidak valid", "tenant", *tenant)
+			os.Exit(1)
+		}
+		tenantID = &id
+	}
+
+	db, err := postgres.NewConnection(cfg.DBDSN)
+	if err != nil {
+		slog.Error("Kritikal: Gagal terhubung ke database", "error", err)
+		os.Exit(1)
+	}
+	db.AutoMigrate(&domain.Photo{}, &domain.PhotoRendition{})
+
+	store, err := storage.New(cfg)
+	if err != nil {
+		slog.Error("Kritikal: Gagal menyiapkan storage", "driver", cfg.StorageDriver, "error", err)
+		os.Exit(1)
+	}
+
+	renditions := mUcase.NewRenditionUsecase(mRepo.NewPhotoRepository(db), mRepo.NewRenditionRepository(db), store, mUcase.RenditionConfig{
+		WebP:        cfg.RenditionWebP,
+		JPEGQuality: 85,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	n, err := renditions.Regenerate(ctx, tenantID, *missing)
+	if err != nil {
+		slog.Error("Regenerate rendition berhenti", "processed", n, "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Regenerate rendition selesai", "processed", n)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/animation/usecase"
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnimationHandler struct {
+	usecase usecase.AnimationUsecase
+}
+
+func NewAnimationHandler(u usecase.AnimationUsecase) *AnimationHandler {
+	return &AnimationHandler{u}
+}
+
+// Create godoc
+// @Summary      Buat GIF animasi / boomerang dari jepretan burst
+// @Description  Foto burst diupload dulu sebagai foto raw sesi, lalu dikirim urut lewat photo_ids. Hasilnya Photo kind "animation" plus short link.
+// @Tags         Media
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Transaction ID"
+// @Param        request  body      domain.CreateAnimationRequest  true  "Urutan foto dan opsi animasi"
+// @Success      201  {object}  response.Response
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      422  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/animation [post]
+func (h *AnimationHandler) Create(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	boothID, _ := utils.GetBoothID(c)
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Sesi tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.CreateAnimationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	result, err := h.usecase.Create(c.Request.Context(), boothID, tenantID, trxID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrSessionNotFound):
+			response.Error(c, http.StatusNotFound, "Sesi tidak ditemukan", nil)
+		case errors.Is(err, usecase.ErrFrameNotFound):
+			response.Error(c, http.StatusNotFound, "Frame tidak ditemukan", nil)
+		case errors.Is(err, usecase.ErrPhotoNotInSession):
+			response.Error(c, http.StatusUnprocessableEntity, "Foto tidak cocok dengan sesi", err.Error())
+		case errors.Is(err, usecase.ErrNotInPackage):
+			response.Error(c, http.StatusForbidden, "Animasi tidak termasuk paket sesi", err.Error())
+		default:
+			slog.Error("Gagal membuat animasi", "error", err)
+			response.Error(c, http.StatusInternalServerError, "Gagal membuat animasi", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Animasi berhasil dibuat", result)
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"time"
+
+	"photobooth-core/internal/domain"
+	flUcase "photobooth-core/internal/filter/usecase"
+	fRepo "photobooth-core/internal/frame/repository"
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+	sUcase "photobooth-core/internal/shortlink/usecase"
+	trRepo "photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrSessionNotFound   = errors.New("sesi transaksi tidak ditemukan untuk booth ini")
+	ErrPhotoNotInSession = errors.New("foto bukan jepretan mentah dari sesi ini")
+	ErrFrameNotFound     = errors.New("frame tidak ditemukan atau belum dipublish")
+	ErrNotInPackage      = errors.New("paket sesi ini tidak termasuk animasi")
+)
+
+// Default animasi kalau request tidak mengisi.
+const (
+	defaultDelay  = 100 * time.Millisecond
+	defaultSize   = 480
+	defaultColors = 256
+)
+
+type AnimationUsecase interface {
+	Create(ctx context.Context, boothID, tenantID, trxID uuid.UUID, req domain.CreateAnimationRequest) (*domain.AnimationResult, error)
+}
+
+type animationUsecase struct {
+	photoRepo mRepo.PhotoRepository
+	trxRepo   trRepo.TransactionRepository
+	frameRepo fRepo.FrameRepository
+	media     mUcase.MediaUsecase
+	links     sUcase.ShortLinkUsecase
+	filters   flUcase.FilterUsecase
+	storage   storage.Storage
+}
+
+func NewAnimationUsecase(photoRepo mRepo.PhotoRepository, trxRepo trRepo.TransactionRepository, frameRepo fRepo.FrameRepository,
+	media mUcase.MediaUsecase, links sUcase.ShortLinkUsecase, filters flUcase.FilterUsecase, store storage.Storage) AnimationUsecase {
+	return &animationUsecase{photoRepo, trxRepo, frameRepo, media, links, filters, store}
+}
+
+// Create membuat GIF dari jepretan burst, menyimpannya sebagai Photo kind
+// "animation", lalu membuatkan short link supaya langsung bisa dibagikan.
+func (u *animationUsecase) Create(ctx context.Context, boothID, tenantID, trxID uuid.UUID, req domain.CreateAnimationRequest) (*domain.AnimationResult, error) {
+	trx, err := u.trxRepo.FindByID(tenantID, trxID)
+	if err != nil || trx.BoothID != boothID {
+		return nil, ErrSessionNotFound
+	}
+	if !trx.AllowsAnimation() {
+		return nil, ErrNotInPackage
+	}
+
+	size := req.Size
+	if size == 0 {
+		size = defaultSize
+	}
+	delay := defaultDelay
+	if req.DelayMs > 0 {
+		delay = time.Duration(req.DelayMs) * time.Millisecond
+	}
+	colors := req.Colors
+	if colors == 0 {
+		colors = defaultColors
+	}
+
+	// Cek semua foto dulu sebelum decode, supaya request salah gagal cepat
+	shots := make([]*domain.Photo, 0, len(req.PhotoIDs))
+	for _, id := range req.PhotoIDs {
+		photo, err := u.photoRepo.FindByID(tenantID, id)
+		if err != nil || photo.TransactionID == nil || *photo.TransactionID != trxID || photo.Kind != domain.PhotoRaw {
+			return nil, fmt.Errorf("%w: %s", ErrPhotoNotInSession, id)
+		}
+		shots = append(shots, photo)
+	}
+
+	var overlay image.Image
+	var filter *imaging.Filter
+	frameName := "animation"
+	var frameVersionID *uuid.UUID
+	if req.FrameID != nil {
+		frame, err := u.frameRepo.FindByID(tenantID, *req.FrameID)
+		if err != nil || frame.PublishedVersion == nil {
+			return nil, ErrFrameNotFound
+		}
+		frameName = frame.Name
+		frameVersionID = &frame.PublishedVersion.ID
+		if frame.PublishedVersion.OverlayKey != "" {
+			if overlay, err = u.decode(ctx, frame.PublishedVersion.OverlayKey); err != nil {
+				return nil, err
+			}
+		}
+		// Animasi tidak punya slot, jadi yang dipakai filter level layout
+		if filterID := frame.PublishedVersion.Layout.FilterID; filterID != nil {
+			if filter, err = u.filters.Load(ctx, tenantID, *filterID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	frames := make([]image.Image, 0, len(shots))
+	for i, shot := range shots {
+		img, err := u.decode(ctx, shot.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+
+		// Semua frame mengikuti ukuran frame pertama supaya GIF tidak loncat
+		var fitted *image.RGBA
+		if i == 0 {
+			first := imaging.Fit(img, size)
+			fitted = imaging.Cover(first, first.Bounds().Dx(), first.Bounds().Dy())
+		} else {
+			fitted = imaging.Cover(img, frames[0].Bounds().Dx(), frames[0].Bounds().Dy())
+		}
+		if filter != nil {
+			fitted = filter.Apply(fitted)
+		}
+		if overlay != nil {
+			draw.Draw(fitted, fitted.Bounds(), imaging.Cover(overlay, fitted.Bounds().Dx(), fitted.Bounds().Dy()), image.Point{}, draw.Over)
+		}
+		frames = append(frames, fitted)
+	}
+
+	if req.Mode == domain.AnimationBoomerang {
+		// Maju lalu mundur tanpa mengulang frame ujung
+		for i := len(frames) - 2; i > 0; i-- {
+			frames = append(frames, frames[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.EncodeGIF(&buf, frames, imaging.AnimationOptions{Delay: delay, Colors: colors, Dither: req.Dither}); err != nil {
+		return nil, err
+	}
+
+	photo, err := u.media.SaveEncoded(ctx, boothID, tenantID, domain.SaveImageRequest{
+		TransactionID:  &trxID,
+		FrameName:      frameName,
+		FrameVersionID: frameVersionID,
+		Kind:           domain.PhotoAnimation,
+	}, &imaging.Sanitized{
+		Data:        buf.Bytes(),
+		Image:       frames[0],
+		ContentType: "image/gif",
+		Ext:         ".gif",
+		Width:       frames[0].Bounds().Dx(),
+		Height:      frames[0].Bounds().Dy(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := u.links.CreateLink(tenantID, domain.CreateShortLinkRequest{
+		TargetType: domain.LinkTargetPhoto,
+		TargetID:   photo.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AnimationResult{Photo: photo, ShortLink: link}, nil
+}
+
+func (u *animationUsecase) decode(ctx context.Context, key string) (image.Image, error) {
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode %s: %w", key, err)
+	}
+	return img, nil
+}
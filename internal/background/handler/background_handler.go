@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/background/usecase"
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BackgroundHandler struct {
+	usecase usecase.BackgroundUsecase
+}
+
+func NewBackgroundHandler(u usecase.BackgroundUsecase) *BackgroundHandler {
+	return &BackgroundHandler{u}
+}
+
+// Upload godoc
+// @Summary      Upload latar green screen
+// @Description  Latar dipakai slot frame yang punya pengaturan chroma, atau dipilih tamu saat render. Format JPEG/PNG, maksimal 8000 pixel per sisi.
+// @Tags         Backgrounds
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name  formData  string  true  "Nama latar"
+// @Param        file  formData  file    true  "File gambar"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/backgrounds [post]
+func (h *BackgroundHandler) Upload(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		response.Error(c, http.StatusBadRequest, "Nama latar wajib diisi", nil)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "File latar wajib diisi", err.Error())
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Gagal membaca file latar", err.Error())
+		return
+	}
+	defer src.Close()
+
+	background, err := h.usecase.Upload(c.Request.Context(), tenantID, name, src)
+	if err != nil {
+		writeBackgroundError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Latar berhasil disimpan", background)
+}
+
+// List godoc
+// @Summary      Daftar latar milik tenant
+// @Tags         Backgrounds
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/backgrounds [get]
+func (h *BackgroundHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	backgrounds, err := h.usecase.List(tenantID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data latar", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data latar", backgrounds)
+}
+
+// Get godoc
+// @Summary      Detail latar
+// @Tags         Backgrounds
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Background ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/backgrounds/{id} [get]
+func (h *BackgroundHandler) Get(c *gin.Context) {
+	tenantID, id, ok := backgroundParams(c)
+	if !ok {
+		return
+	}
+
+	background, err := h.usecase.Get(tenantID, id)
+	if err != nil {
+		writeBackgroundError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data latar", background)
+}
+
+// Archive godoc
+// @Summary      Arsipkan latar
+// @Description  Latar hilang dari daftar, tapi frame yang sudah memakainya tetap bisa merender.
+// @Tags         Backgrounds
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Background ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/backgrounds/{id} [delete]
+func (h *BackgroundHandler) Archive(c *gin.Context) {
+	tenantID, id, ok := backgroundParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Archive(tenantID, id); err != nil {
+		writeBackgroundError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Latar berhasil diarsipkan", nil)
+}
+
+// Preview godoc
+// @Summary      Coba pengaturan chroma key
+// @Description  Menjalankan chroma key ke satu foto dan mengembalikan JPEG kecil tanpa menyimpan apa pun. Dipakai untuk menyetel toleransi di lokasi sebelum disimpan ke layout frame.
+// @Tags         Backgrounds
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      image/jpeg
+// @Param        request  body  domain.ChromaPreviewRequest  true  "Foto dan pengaturan chroma"
+// @Success      200
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/backgrounds/preview [post]
+func (h *BackgroundHandler) Preview(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.ChromaPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	data, err := h.usecase.Preview(c.Request.Context(), tenantID, req)
+	if err != nil {
+		writeBackgroundError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/jpeg", data)
+}
+
+// backgroundParams mengambil tenant dan background ID dari request; false berarti respons error sudah dikirim.
+func backgroundParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Latar tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+func writeBackgroundError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBackground):
+		response.Error(c, http.StatusBadRequest, "Latar tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrInvalidChroma):
+		response.Error(c, http.StatusBadRequest, "Pengaturan chroma tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrBackgroundNotFound):
+		response.Error(c, http.StatusNotFound, "Latar tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrPhotoNotFound):
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+	default:
+		slog.Error("Gagal memproses latar", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses latar", err.Error())
+	}
+}
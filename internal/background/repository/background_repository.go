@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BackgroundRepository interface {
+	Create(background *domain.Background) error
+	FindByID(tenantID, id uuid.UUID) (*domain.Background, error)
+	FindByTenant(tenantID uuid.UUID) ([]domain.Background, error)
+	Archive(tenantID, id uuid.UUID, at time.Time) error
+}
+
+type backgroundRepository struct {
+	db *gorm.DB
+}
+
+func NewBackgroundRepository(db *gorm.DB) BackgroundRepository {
+	return &backgroundRepository{db}
+}
+
+func (r *backgroundRepository) Create(background *domain.Background) error {
+	return r.db.Create(background).Error
+}
+
+// FindByID juga mengembalikan latar yang sudah diarsipkan, karena versi
+// frame lama masih boleh merender dengan latar tersebut.
+func (r *backgroundRepository) FindByID(tenantID, id uuid.UUID) (*domain.Background, error) {
+	var background domain.Background
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&background).Error
+	return &background, err
+}
+
+func (r *backgroundRepository) FindByTenant(tenantID uuid.UUID) ([]domain.Background, error) {
+	backgrounds := []domain.Background{}
+	err := r.db.Where("tenant_id = ? AND archived_at IS NULL", tenantID).
+		Order("name ASC").
+		Find(&backgrounds).Error
+	return backgrounds, err
+}
+
+func (r *backgroundRepository) Archive(tenantID, id uuid.UUID, at time.Time) error {
+	res := r.db.Model(&domain.Background{}).
+		Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).
+		Update("archived_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
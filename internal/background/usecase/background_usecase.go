@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"time"
+
+	"photobooth-core/internal/background/repository"
+	"photobooth-core/internal/domain"
+	mRepo "photobooth-core/internal/media/repository"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBackgroundNotFound = errors.New("latar tidak ditemukan")
+	ErrInvalidBackground  = errors.New("latar harus gambar JPEG atau PNG yang valid")
+	ErrInvalidChroma      = errors.New("pengaturan chroma key tidak valid")
+	ErrPhotoNotFound      = errors.New("foto tidak ditemukan")
+)
+
+// defaultKeyColor adalah hijau kain chroma standar.
+const defaultKeyColor = "#00b140"
+
+// Batas upload latar, sama dengan overlay frame.
+const (
+	maxBackgroundBytes = 12 << 20
+	maxBackgroundSide  = 8000
+	previewMaxSide     = 1200
+	maxFeather         = 50
+)
+
+type BackgroundUsecase interface {
+	Upload(ctx context.Context, tenantID uuid.UUID, name string, file io.Reader) (*domain.Background, error)
+	List(tenantID uuid.UUID) ([]domain.Background, error)
+	Get(tenantID, id uuid.UUID) (*domain.Background, error)
+	Archive(tenantID, id uuid.UUID) error
+
+	// Load membuka gambar latar, termasuk yang sudah diarsipkan.
+	Load(ctx context.Context, tenantID, id uuid.UUID) (image.Image, error)
+	Preview(ctx context.Context, tenantID uuid.UUID, req domain.ChromaPreviewRequest) ([]byte, error)
+}
+
+type backgroundUsecase struct {
+	repo      repository.BackgroundRepository
+	photoRepo mRepo.PhotoRepository
+	storage   storage.Storage
+}
+
+func NewBackgroundUsecase(repo repository.BackgroundRepository, photoRepo mRepo.PhotoRepository, store storage.Storage) BackgroundUsecase {
+	return &backgroundUsecase{repo, photoRepo, store}
+}
+
+// NewChromaKey memvalidasi pengaturan chroma dari layout atau request dan
+// mengubahnya jadi pengaturan imaging.
+func NewChromaKey(settings domain.ChromaKey) (*imaging.ChromaKey, error) {
+	hex := settings.Color
+	if hex == "" {
+		hex = defaultKeyColor
+	}
+	key, err := imaging.ParseHexColor(hex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChroma, err)
+	}
+	if settings.Tolerance < 0 || settings.Tolerance > 1 || settings.Softness < 0 || settings.Softness > 1 {
+		return nil, fmt.Errorf("%w: tolerance dan softness harus 0-1", ErrInvalidChroma)
+	}
+	if settings.Spill < 0 || settings.Spill > 1 {
+		return nil, fmt.Errorf("%w: spill harus 0-1", ErrInvalidChroma)
+	}
+	if settings.Feather < 0 || settings.Feather > maxFeather {
+		return nil, fmt.Errorf("%w: feather harus 0-%d pixel", ErrInvalidChroma, maxFeather)
+	}
+
+	return &imaging.ChromaKey{
+		Key:       key,
+		Tolerance: settings.Tolerance,
+		Softness:  settings.Softness,
+		Spill:     settings.Spill,
+		Feather:   settings.Feather,
+	}, nil
+}
+
+// Upload menyimpan gambar latar baru. Gambar di-sanitize seperti foto tamu
+// supaya metadata kamera/GPS dari foto venue tidak ikut tersimpan.
+func (u *backgroundUsecase) Upload(ctx context.Context, tenantID uuid.UUID, name string, file io.Reader) (*domain.Background, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxBackgroundBytes))
+	if err != nil {
+		return nil, err
+	}
+	clean, err := imaging.Sanitize(bytes.NewReader(data), imaging.Limits{MaxWidth: maxBackgroundSide, MaxHeight: maxBackgroundSide}, 0)
+	if err != nil {
+		return nil, ErrInvalidBackground
+	}
+
+	background := &domain.Background{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Name:        name,
+		ContentType: clean.ContentType,
+		Width:       clean.Width,
+		Height:      clean.Height,
+		CreatedAt:   time.Now(),
+	}
+	background.StorageKey = fmt.Sprintf("backgrounds/%s/%s%s", tenantID, background.ID, clean.Ext)
+
+	if err := u.storage.Put(ctx, background.StorageKey, bytes.NewReader(clean.Data), int64(len(clean.Data)), clean.ContentType); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Create(background); err != nil {
+		_ = u.storage.Delete(ctx, background.StorageKey)
+		return nil, err
+	}
+	return background, nil
+}
+
+func (u *backgroundUsecase) List(tenantID uuid.UUID) ([]domain.Background, error) {
+	return u.repo.FindByTenant(tenantID)
+}
+
+func (u *backgroundUsecase) Get(tenantID, id uuid.UUID) (*domain.Background, error) {
+	background, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrBackgroundNotFound
+	}
+	return background, nil
+}
+
+// Archive menyembunyikan latar dari daftar. Filenya tetap ada untuk versi frame lama.
+func (u *backgroundUsecase) Archive(tenantID, id uuid.UUID) error {
+	if err := u.repo.Archive(tenantID, id, time.Now()); err != nil {
+		return ErrBackgroundNotFound
+	}
+	return nil
+}
+
+func (u *backgroundUsecase) Load(ctx context.Context, tenantID, id uuid.UUID) (image.Image, error) {
+	background, err := u.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.decode(ctx, background.StorageKey)
+}
+
+// Preview menjalankan chroma key ke satu foto dan mengembalikan JPEG kecil,
+// tanpa menyimpan apa pun.
+func (u *backgroundUsecase) Preview(ctx context.Context, tenantID uuid.UUID, req domain.ChromaPreviewRequest) ([]byte, error) {
+	key, err := NewChromaKey(req.Chroma)
+	if err != nil {
+		return nil, err
+	}
+	photo, err := u.photoRepo.FindByID(tenantID, req.PhotoID)
+	if err != nil || photo.Kind == domain.PhotoAnimation {
+		return nil, ErrPhotoNotFound
+	}
+
+	src, err := u.decode(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	src = imaging.Fit(src, previewMaxSide)
+
+	// Tanpa latar, preview ditaruh di atas abu-abu supaya area transparan kelihatan
+	bg := image.Image(image.NewUniform(color.RGBA{128, 128, 128, 255}))
+	if req.Chroma.BackgroundID != nil {
+		if bg, err = u.Load(ctx, tenantID, *req.Chroma.BackgroundID); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := imaging.Encode(&buf, imaging.Composite(key.Apply(src), bg), imaging.FormatJPEG, 85); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (u *backgroundUsecase) decode(ctx context.Context, key string) (image.Image, error) {
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode %s: %w", key, err)
+	}
+	return img, nil
+}
@@ -5,6 +5,7 @@ import (
 	"photobooth-core/internal/booth/usecase"
 	"photobooth-core/internal/domain"
 	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,10 +33,8 @@ func (h *BoothHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Ambil dari context (ini masih interface{})
-	tenantIDRaw, _ := c.Get("tenant_id")
-
-	tenantID, err := uuid.Parse(tenantIDRaw.(string)) // Cast ke string dulu baru di-Parse
+	// AuthMiddleware menyimpan tenant_id sebagai uuid.UUID
+	tenantID, err := utils.GetTenantID(c)
 	if err != nil {
 		response.Error(c, http.StatusUnauthorized, "Format Tenant ID tidak valid", err.Error())
 		return
@@ -59,16 +58,9 @@ func (h *BoothHandler) Register(c *gin.Context) {
 // @Router       /api/v1/booths [get]
 func (h *BoothHandler) GetAllBooth(c *gin.Context) {
 	// get tenant_id by context injected on auth middleware
-	tenantIDRaw, exists := c.Get("tenant_id")
-	if !exists {
-		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Tenant ID missing")
-		return
-	}
-
-	// Type Assertion secara aman
-	tenantID, ok := uuid.Parse(tenantIDRaw.(string))
-	if ok != nil {
-		response.Error(c, http.StatusInternalServerError, "Internal Server Error", "Invalid tenant_id type in context")
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
 		return
 	}
 
@@ -83,6 +75,45 @@ func (h *BoothHandler) GetAllBooth(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Berhasil mengambil daftar booth", booths)
 }
 
+// SetGroup godoc
+// @Summary      Atur grup booth
+// @Description  Grup dipakai untuk memasang frame ke banyak booth sekaligus. Kosongkan untuk melepas booth dari grup.
+// @Tags         Booths
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                          true  "Booth ID"
+// @Param        request  body      domain.UpdateBoothGroupRequest  true  "Grup"
+// @Success      200      {object}  response.Response
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/booths/{id}/group [put]
+func (h *BoothHandler) SetGroup(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Booth tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.UpdateBoothGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	booth, err := h.usecase.SetGroup(tenantID, id, req.Group)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Booth tidak ditemukan", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Grup booth berhasil diubah", booth)
+}
+
 // Pair godoc
 // @Summary      Device Handshake (Pairing)
 // @Description  Endpoint khusus untuk mesin fisik melakukan login menggunakan Device Code & Secret Key
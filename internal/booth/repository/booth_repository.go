@@ -11,7 +11,9 @@ type BoothRepository interface {
 	Create(booth *domain.Booth) error
 	FindByTenant(tenantID uuid.UUID) ([]domain.Booth, error)
 	FindByDeviceCode(code string) (*domain.Booth, error)
+	FindByID(tenantID, id uuid.UUID) (*domain.Booth, error)
 	UpdateStatus(id uuid.UUID, status string) error
+	UpdateGroup(tenantID, id uuid.UUID, group string) error
 }
 
 type boothRepository struct {
@@ -28,7 +30,7 @@ func (r *boothRepository) Create(booth *domain.Booth) error {
 
 func (r *boothRepository) FindByTenant(tenantID uuid.UUID) ([]domain.Booth, error) {
 	var booths []domain.Booth
-	err := r.db.Where("tenant_id = ?", tenantID).Find(&booths).Error
+	err := r.db.Preload("Printer").Where("tenant_id = ?", tenantID).Find(&booths).Error
 	return booths, err
 }
 
@@ -38,7 +40,24 @@ func (r *boothRepository) FindByDeviceCode(code string) (*domain.Booth, error) {
 	return &booth, err
 }
 
+func (r *boothRepository) FindByID(tenantID, id uuid.UUID) (*domain.Booth, error) {
+	var booth domain.Booth
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&booth).Error
+	return &booth, err
+}
+
 func (r *boothRepository) UpdateStatus(id uuid.UUID, status string) error {
 	// Kita update status dan timestamp 'updated_at' otomatis oleh GORM
 	return r.db.Model(&domain.Booth{}).Where("id = ?", id).Update("status", status).Error
 }
+
+func (r *boothRepository) UpdateGroup(tenantID, id uuid.UUID, group string) error {
+	res := r.db.Model(&domain.Booth{}).Where("tenant_id = ? AND id = ?", tenantID, id).Update("booth_group", group)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ type BoothUsecase interface {
 	GetMyBooths(tenantID uuid.UUID) ([]domain.Booth, error)
 	PairDevice(req domain.BoothPairingRequest) (*domain.BoothPairingResponse, error)
 	Heartbeat(boothID uuid.UUID) error
+	SetGroup(tenantID, id uuid.UUID, group string) (*domain.Booth, error)
 }
 
 type boothUsecase struct {
@@ -39,6 +40,7 @@ func (u *boothUsecase) RegisterBooth(tenantID uuid.UUID, req domain.CreateBoothR
 		ID:         uuid.New(),
 		TenantID:   tenantID,
 		Name:       req.Name,
+		Group:      req.Group,
 		DeviceCode: deviceCode,
 		SecretKey:  secret,
 		Status:     domain.BoothActive,
@@ -88,3 +90,10 @@ func (u *boothUsecase) PairDevice(req domain.BoothPairingRequest) (*domain.Booth
 func (u *boothUsecase) Heartbeat(boothID uuid.UUID) error {
 	return u.repo.UpdateStatus(boothID, "online")
 }
+
+func (u *boothUsecase) SetGroup(tenantID, id uuid.UUID, group string) (*domain.Booth, error) {
+	if err := u.repo.UpdateGroup(tenantID, id, group); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(tenantID, id)
+}
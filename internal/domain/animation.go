@@ -0,0 +1,28 @@
+package domain
+
+import "github.com/google/uuid"
+
+type AnimationMode string
+
+const (
+	AnimationForward   AnimationMode = "forward"
+	AnimationBoomerang AnimationMode = "boomerang" // maju lalu mundur
+)
+
+// CreateAnimationRequest menyusun jepretan burst sesi menjadi GIF animasi.
+// PhotoIDs urut sesuai jepretan.
+type CreateAnimationRequest struct {
+	PhotoIDs []uuid.UUID   `json:"photo_ids" binding:"required,min=2,max=40"`
+	Mode     AnimationMode `json:"mode" binding:"omitempty,oneof=forward boomerang" example:"boomerang"`
+	DelayMs  int           `json:"delay_ms" binding:"omitempty,min=20,max=2000" example:"100"`
+	Size     int           `json:"size" binding:"omitempty,min=120,max=1080" example:"480"` // sisi terpanjang
+	Colors   int           `json:"colors" binding:"omitempty,min=2,max=256" example:"256"`
+	Dither   bool          `json:"dither"`
+	FrameID  *uuid.UUID    `json:"frame_id"` // overlay versi published dipasang di tiap frame
+}
+
+// AnimationResult adalah GIF yang tersimpan beserta short link untuk dibagikan.
+type AnimationResult struct {
+	Photo     *Photo     `json:"photo"`
+	ShortLink *ShortLink `json:"short_link"`
+}
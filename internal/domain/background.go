@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Background adalah gambar latar milik tenant untuk booth green screen.
+// Dipakai dari slot frame (FrameSlot.Chroma) atau dipilih tamu saat render.
+type Background struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID    uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	StorageKey  string     `gorm:"type:varchar(255);not null" json:"-"`
+	ContentType string     `gorm:"type:varchar(50)" json:"content_type"`
+	Width       int        `gorm:"type:integer;default:0" json:"width"`
+	Height      int        `gorm:"type:integer;default:0" json:"height"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ChromaPreviewRequest mencoba pengaturan chroma key ke satu foto tanpa
+// menyimpan hasilnya, untuk menyetel toleransi di lokasi acara.
+type ChromaPreviewRequest struct {
+	PhotoID uuid.UUID `json:"photo_id" binding:"required"`
+	Chroma  ChromaKey `json:"chroma"`
+}
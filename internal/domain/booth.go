@@ -13,15 +13,22 @@ type Booth struct {
 	DeviceCode string      `gorm:"type:varchar(50);unique;index;not null" json:"device_code"`
 	SecretKey  string      `gorm:"type:varchar(100);not null" json:"-"` // Hidden from JSON
 	Status     BoothStatus `gorm:"type:varchar(20);default:active" json:"status"`
+	Group      string      `gorm:"column:booth_group;type:varchar(50);index" json:"group"` // dipakai untuk assignment frame
 	CreatedAt  time.Time   `json:"created_at"`
 	UpdatedAt  time.Time   `json:"updated_at"`
 
 	// Relationships
-	Tenant Tenant `gorm:"foreignKey:TenantID" json:"-"`
+	Tenant  Tenant   `gorm:"foreignKey:TenantID" json:"-"`
+	Printer *Printer `gorm:"foreignKey:BoothID" json:"printer,omitempty"`
 }
 
 type CreateBoothRequest struct {
-	Name string `json:"name" binding:"required" example:"Booth Cabang Sudirman"`
+	Name  string `json:"name" binding:"required" example:"Booth Cabang Sudirman"`
+	Group string `json:"group" binding:"max=50" example:"wedding-jakarta"`
+}
+
+type UpdateBoothGroupRequest struct {
+	Group string `json:"group" binding:"max=50" example:"wedding-jakarta"`
 }
 
 // BoothPairingRequest is used when the physical machine first connects
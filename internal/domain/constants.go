@@ -16,11 +16,18 @@ const (
 	BoothOffline     BoothStatus = "offline"
 )
 
-// status transaction
+// status transaction (sesi foto), lihat transactionTransitions untuk alurnya
 type TransactionStatus string
 
 const (
-	TransPending   TransactionStatus = "pending"
-	TransCompleted TransactionStatus = "completed"
-	TransFailed    TransactionStatus = "failed"
+	TransCreated         TransactionStatus = "created"
+	TransAwaitingPayment TransactionStatus = "awaiting_payment"
+	TransPaid            TransactionStatus = "paid"
+	TransShooting        TransactionStatus = "shooting"
+	TransRendering       TransactionStatus = "rendering"
+	TransPrinted         TransactionStatus = "printed"
+	TransCompleted       TransactionStatus = "completed"
+	TransFailed          TransactionStatus = "failed"
+	TransCancelled       TransactionStatus = "cancelled"
+	TransExpired         TransactionStatus = "expired" // hanya sesi lama, tidak lagi dipakai
 )
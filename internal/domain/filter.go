@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Filter adalah resep warna milik tenant yang dijalankan di server, supaya
+// hasil "vintage" sama persis di semua booth. Filter tidak bisa diedit:
+// frame yang sudah menunjuk ID-nya harus tetap menghasilkan warna yang sama.
+type Filter struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Tone       string     `gorm:"type:varchar(20)" json:"tone,omitempty"` // bw, sepia, atau kosong
+	Brightness float64    `gorm:"type:numeric(4,3);default:0" json:"brightness"`
+	Contrast   float64    `gorm:"type:numeric(4,3);default:0" json:"contrast"`
+	Saturation float64    `gorm:"type:numeric(4,3);default:0" json:"saturation"`
+	Intensity  float64    `gorm:"type:numeric(4,3);default:1" json:"intensity"`
+	LUTKey     string     `gorm:"type:varchar(255)" json:"-"`
+	LUTSize    int        `gorm:"type:integer;default:0" json:"lut_size,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreateFilterRequest adalah field form saat membuat filter. File .cube
+// dikirim di part "lut" dan tidak bisa digabung dengan tone.
+type CreateFilterRequest struct {
+	Name       string   `form:"name" binding:"required,max=100" example:"Vintage"`
+	Tone       string   `form:"tone" binding:"omitempty,oneof=bw sepia"`
+	Brightness float64  `form:"brightness" binding:"min=-1,max=1"`
+	Contrast   float64  `form:"contrast" binding:"min=-1,max=1"`
+	Saturation float64  `form:"saturation" binding:"min=-1,max=1"`
+	Intensity  *float64 `form:"intensity" binding:"omitempty,min=0,max=1"` // kosong = 1
+}
+
+// ApplyFilterRequest meminta filter dijalankan ke foto yang sudah ada.
+// Hasilnya Photo baru; foto asal tidak diubah.
+type ApplyFilterRequest struct {
+	FilterID uuid.UUID `json:"filter_id" binding:"required"`
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidLayout = errors.New("layout frame tidak valid")
+
+// Frame adalah template strip foto milik tenant. Isi layout-nya ada di
+// FrameVersion yang tidak pernah diubah, jadi mengedit frame tidak mengubah
+// foto yang sudah dirender dengan versi sebelumnya.
+type Frame struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID           uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Name               string     `gorm:"type:varchar(100);not null" json:"name"`
+	LatestVersion      int        `gorm:"type:integer;default:0" json:"latest_version"`
+	PublishedVersionID *uuid.UUID `gorm:"type:uuid" json:"published_version_id"`
+	ArchivedAt         *time.Time `json:"archived_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Relationships
+	PublishedVersion *FrameVersion `gorm:"foreignKey:PublishedVersionID" json:"published_version,omitempty"`
+}
+
+// FrameVersion adalah snapshot layout dan overlay. Dibuat sekali, tidak diupdate.
+type FrameVersion struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	FrameID    uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_frame_version" json:"frame_id"`
+	TenantID   uuid.UUID   `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Version    int         `gorm:"type:integer;not null;uniqueIndex:idx_frame_version" json:"version"`
+	Layout     FrameLayout `gorm:"type:jsonb;not null" json:"layout"`
+	OverlayKey string      `gorm:"type:varchar(255)" json:"-"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// FrameAssignment memasang frame ke satu booth atau ke semua booth dalam
+// grup, opsional dibatasi rentang tanggal acara.
+type FrameAssignment struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	FrameID    uuid.UUID  `gorm:"type:uuid;index;not null" json:"frame_id"`
+	BoothID    *uuid.UUID `gorm:"type:uuid;index" json:"booth_id,omitempty"`
+	BoothGroup string     `gorm:"type:varchar(50);index" json:"booth_group,omitempty"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CurrentFrame adalah frame yang aktif untuk booth saat ini, versi published-nya.
+type CurrentFrame struct {
+	FrameID    uuid.UUID   `json:"frame_id"`
+	Name       string      `json:"name"`
+	VersionID  uuid.UUID   `json:"version_id"`
+	Version    int         `json:"version"`
+	Layout     FrameLayout `json:"layout"`
+	PreviewURL string      `json:"preview_url"`
+}
+
+// FrameLayout adalah ukuran kanvas dan posisi elemen dalam pixel.
+// Urutan gambar: background, foto di slot, overlay, lalu teks.
+// FilterID dijalankan ke semua foto di slot, kecuali slot punya filter sendiri.
+type FrameLayout struct {
+	Width      int         `json:"width"`
+	Height     int         `json:"height"`
+	Background string      `json:"background,omitempty" example:"#ffffff"`
+	FilterID   *uuid.UUID  `json:"filter_id,omitempty"`
+	Slots      []FrameSlot `json:"slots"`
+	Texts      []FrameText `json:"texts,omitempty"`
+}
+
+// FrameSlot adalah kotak foto. Shot adalah indeks foto (urutan jepretan)
+// yang masuk ke slot ini; nil berarti sesuai urutan slot.
+type FrameSlot struct {
+	X        int        `json:"x"`
+	Y        int        `json:"y"`
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	Rotation float64    `json:"rotation,omitempty"` // derajat, searah jarum jam
+	Fit      string     `json:"fit,omitempty"`      // cover (default) atau contain
+	Shot     *int       `json:"shot,omitempty"`
+	FilterID *uuid.UUID `json:"filter_id,omitempty"`
+	Chroma   *ChromaKey `json:"chroma,omitempty"`
+}
+
+// ChromaKey mengganti latar green screen di slot. Tolerance dan Softness
+// adalah jarak warna 0..1, Spill 0..1, Feather radius blur tepi dalam pixel.
+// BackgroundID kosong berarti latar jadi transparan (terlihat background kanvas).
+type ChromaKey struct {
+	Color        string     `json:"color" example:"#00b140"`
+	Tolerance    float64    `json:"tolerance" example:"0.25"`
+	Softness     float64    `json:"softness" example:"0.1"`
+	Spill        float64    `json:"spill" example:"0.5"`
+	Feather      int        `json:"feather" example:"2"`
+	BackgroundID *uuid.UUID `json:"background_id,omitempty"`
+}
+
+// FrameText adalah teks dengan placeholder {date}, {time}, {reference},
+// {tenant}, {booth} dan {frame}. DateFormat memakai layout waktu Go.
+type FrameText struct {
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width,omitempty"`
+	Text       string  `json:"text" example:"{tenant} - {date}"`
+	DateFormat string  `json:"date_format,omitempty" example:"02 Jan 2006"`
+	FontSize   float64 `json:"font_size"`
+	Bold       bool    `json:"bold,omitempty"`
+	Color      string  `json:"color,omitempty" example:"#000000"`
+	Align      string  `json:"align,omitempty"` // left, center, right
+}
+
+func (l FrameLayout) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *FrameLayout) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return errors.New("layout frame harus berupa JSON")
+	}
+	return json.Unmarshal(raw, l)
+}
+
+// RenderSessionRequest meminta server menyusun foto-foto sesi ke dalam frame.
+// PhotoIDs urut sesuai jepretan; kalau kosong dipakai foto raw sesi sesuai waktu upload.
+// Version 0 berarti versi yang sedang published. BackgroundID (pilihan tamu)
+// menggantikan latar semua slot chroma di layout.
+type RenderSessionRequest struct {
+	FrameID      uuid.UUID   `json:"frame_id" binding:"required"`
+	Version      int         `json:"version"`
+	PhotoIDs     []uuid.UUID `json:"photo_ids"`
+	BackgroundID *uuid.UUID  `json:"background_id"`
+}
+
+type CreateFrameAssignmentRequest struct {
+	BoothID    *uuid.UUID `json:"booth_id"`
+	BoothGroup string     `json:"booth_group" example:"wedding-jakarta"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
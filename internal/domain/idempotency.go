@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord menyimpan respons request mesin booth yang membawa header
+// Idempotency-Key, supaya retry setelah timeout mendapat respons yang sama
+// dan tidak mengeksekusi ulang. StatusCode 0 berarti request pertama masih
+// diproses.
+type IdempotencyRecord struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	BoothID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_booth_key" json:"booth_id"`
+	Key          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_booth_key" json:"key"`
+	Method       string    `gorm:"type:varchar(10);not null" json:"method"`
+	Path         string    `gorm:"type:varchar(255);not null" json:"path"`
+	RequestHash  string    `gorm:"type:varchar(64);not null" json:"request_hash"` // sha256 method, path, dan body
+	StatusCode   int       `gorm:"default:0" json:"status_code"`
+	ContentType  string    `gorm:"type:varchar(100)" json:"content_type"`
+	ResponseBody []byte    `gorm:"type:bytea" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `gorm:"index" json:"expires_at"`
+}
+
+// Pending berarti request pertama dengan key ini belum selesai.
+func (r *IdempotencyRecord) Pending() bool {
+	return r.StatusCode == 0
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind string
+
+const (
+	NotificationPrinterLowMedia NotificationKind = "printer_low_media"
+)
+
+// Notification adalah pemberitahuan untuk tenant di dashboard, misalnya
+// kertas printer booth yang hampir habis.
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID  uuid.UUID        `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID   *uuid.UUID       `gorm:"type:uuid;index" json:"booth_id"`
+	Kind      NotificationKind `gorm:"type:varchar(50);not null" json:"kind"`
+	Title     string           `gorm:"type:varchar(150);not null" json:"title"`
+	Message   string           `gorm:"type:text" json:"message"`
+	ReadAt    *time.Time       `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
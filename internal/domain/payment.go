@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPending PaymentStatus = "pending"
+	PaymentPaid    PaymentStatus = "paid"
+	PaymentExpired PaymentStatus = "expired"
+	PaymentFailed  PaymentStatus = "failed"
+)
+
+// Payment adalah satu tagihan di payment gateway untuk satu sesi. Sesi bisa
+// punya beberapa tagihan kalau QR sebelumnya kedaluwarsa; sesi baru pindah ke
+// paid setelah webhook gateway untuk salah satunya terverifikasi.
+type Payment struct {
+	ID            uuid.UUID     `gorm:"type:uuid;primaryKey" json:"id"` // dikirim ke gateway sebagai order_id
+	TenantID      uuid.UUID     `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	TransactionID uuid.UUID     `gorm:"type:uuid;index;not null" json:"transaction_id"`
+	Provider      string        `gorm:"type:varchar(20);not null" json:"provider"`
+	ProviderRef   string        `gorm:"type:varchar(100);uniqueIndex" json:"provider_ref"` // ID tagihan di gateway
+	Amount        float64       `gorm:"type:decimal(10,2)" json:"amount"`
+	Status        PaymentStatus `gorm:"type:varchar(20);index;not null" json:"status"`
+	QRString      string        `gorm:"type:text" json:"qr_string"` // payload QRIS untuk layar booth
+	ExpiresAt     time.Time     `json:"expires_at"`
+	PaidAt        *time.Time    `json:"paid_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
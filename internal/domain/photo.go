@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Photo adalah hasil foto yang disimpan booth. File fisiknya ada di storage,
+// tabel ini cuma menyimpan metadata supaya bisa dicari per tenant dan per sesi.
+type Photo struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID       uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID        uuid.UUID  `gorm:"type:uuid;index;not null" json:"booth_id"`
+	TransactionID  *uuid.UUID `gorm:"type:uuid;index" json:"transaction_id"`
+	FrameName      string     `gorm:"type:varchar(100)" json:"frame_name"`
+	Kind           PhotoKind  `gorm:"type:varchar(20);default:'raw';index" json:"kind"`
+	FrameVersionID *uuid.UUID `gorm:"type:uuid;index" json:"frame_version_id,omitempty"` // hanya untuk hasil render server
+	SourcePhotoID  *uuid.UUID `gorm:"type:uuid;index" json:"source_photo_id,omitempty"`  // foto asal kalau hasil filter
+	FilterID       *uuid.UUID `gorm:"type:uuid;index" json:"filter_id,omitempty"`
+	StorageKey     string     `gorm:"type:varchar(255);unique;not null" json:"storage_key"`
+	ContentType    string     `gorm:"type:varchar(50)" json:"content_type"`
+	Size           int64      `gorm:"type:bigint;default:0" json:"size"`
+	Width          int        `gorm:"type:integer;default:0" json:"width"`
+	Height         int        `gorm:"type:integer;default:0" json:"height"`
+	Checksum       string     `gorm:"type:varchar(64);index" json:"checksum"` // SHA-256 hex
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// RenditionStatus diisi worker setelah thumbnail dan ukuran web selesai dibuat.
+	RenditionStatus RenditionStatus `gorm:"type:varchar(20);default:'pending';index" json:"rendition_status"`
+
+	// DownloadURL adalah link bertanda tangan untuk tamu, dihitung saat dibaca.
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
+
+	// Relationships
+	Booth       Booth            `gorm:"foreignKey:BoothID" json:"-"`
+	Transaction *Transaction     `gorm:"foreignKey:TransactionID" json:"-"`
+	Renditions  []PhotoRendition `gorm:"foreignKey:PhotoID;constraint:OnDelete:CASCADE" json:"renditions,omitempty"`
+}
+
+// PhotoKind membedakan jepretan mentah dari booth dan hasil olahan server.
+type PhotoKind string
+
+const (
+	PhotoRaw       PhotoKind = "raw"
+	PhotoComposite PhotoKind = "composite"
+	PhotoAnimation PhotoKind = "animation"
+)
+
+type RenditionStatus string
+
+const (
+	RenditionPending RenditionStatus = "pending"
+	RenditionReady   RenditionStatus = "ready"
+	RenditionFailed  RenditionStatus = "failed"
+)
+
+type RenditionKind string
+
+const (
+	RenditionThumbnail RenditionKind = "thumbnail"
+	RenditionWeb       RenditionKind = "web"
+	RenditionOriginal  RenditionKind = "original"
+)
+
+// PhotoRendition adalah turunan foto dalam ukuran dan format tertentu,
+// supaya galeri dan dashboard tidak perlu mengunduh file resolusi cetak.
+type PhotoRendition struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primaryKey" json:"-"`
+	PhotoID     uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_photo_rendition" json:"-"`
+	Kind        RenditionKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_photo_rendition" json:"kind"`
+	Format      string        `gorm:"type:varchar(10);not null;uniqueIndex:idx_photo_rendition" json:"format"`
+	StorageKey  string        `gorm:"type:varchar(255);not null" json:"-"`
+	ContentType string        `gorm:"type:varchar(50)" json:"content_type"`
+	Size        int64         `gorm:"type:bigint;default:0" json:"size"`
+	Width       int           `gorm:"type:integer;default:0" json:"width"`
+	Height      int           `gorm:"type:integer;default:0" json:"height"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// URL adalah link bertanda tangan ke file rendition, dihitung saat dibaca.
+	URL string `gorm:"-" json:"url,omitempty"`
+}
+
+// SavePhotoRequest adalah payload lama dari booth (data URL base64).
+// Deprecated: pakai upload multipart (UploadPhotoRequest).
+type SavePhotoRequest struct {
+	Image         string     `json:"image" binding:"required"`
+	FrameName     string     `json:"frameName" example:"Wedding Gold"`
+	TransactionID *uuid.UUID `json:"transaction_id"`
+}
+
+// UploadPhotoRequest adalah field metadata di upload multipart.
+// Field ini harus dikirim sebelum part "file" supaya file bisa langsung dialirkan.
+type UploadPhotoRequest struct {
+	TransactionID *uuid.UUID `form:"transaction_id"`
+	FrameName     string     `form:"frame_name" example:"Wedding Gold"`
+
+	// PhotoID diisi server (finalize upload bertahap) supaya finalize ulang
+	// menghasilkan Photo yang sama. Tidak pernah diambil dari client.
+	PhotoID *uuid.UUID `form:"-" json:"-" swaggerignore:"true"`
+}
+
+// SaveImageRequest adalah metadata gambar hasil olahan server (composite, animasi, filter).
+type SaveImageRequest struct {
+	TransactionID  *uuid.UUID
+	FrameName      string
+	FrameVersionID *uuid.UUID
+	SourcePhotoID  *uuid.UUID
+	FilterID       *uuid.UUID
+	Kind           PhotoKind
+	PhotoID        *uuid.UUID
+}
+
+// PhotoFilter dipakai untuk query daftar foto milik tenant.
+type PhotoFilter struct {
+	BoothID       *uuid.UUID
+	TransactionID *uuid.UUID
+	Kind          PhotoKind
+	From          *time.Time
+	To            *time.Time
+	Limit         int
+	Offset        int
+}
+
+// SessionGallery adalah isi galeri publik satu sesi foto.
+type SessionGallery struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	ReferenceNo   string    `json:"reference_no"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Photos        []Photo   `json:"photos"`
+}
+
+// EventGallery adalah galeri publik satu booth dalam rentang waktu acara.
+type EventGallery struct {
+	BoothID   uuid.UUID `json:"booth_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Photos    []Photo   `json:"photos"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingPackage adalah paket harga sesi foto milik tenant. Booth hanya
+// mengirim ID paket saat memulai sesi; harga dan kuota dihitung server lalu
+// disalin ke transaksi, jadi mengubah paket tidak mengubah sesi lama.
+type PricingPackage struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID          uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Name              string     `gorm:"type:varchar(100);not null" json:"name"`
+	Price             float64    `gorm:"type:decimal(10,2)" json:"price"`
+	Shots             int        `gorm:"type:integer" json:"shots"`  // jumlah jepretan per sesi
+	Prints            int        `gorm:"type:integer" json:"prints"` // lembar cetak yang termasuk harga
+	DigitalOnly       bool       `json:"digital_only"`
+	AnimationIncluded bool       `json:"animation_included"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// PackageAssignment menawarkan paket di satu booth atau semua booth dalam grup.
+type PackageAssignment struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	PackageID  uuid.UUID  `gorm:"type:uuid;index;not null" json:"package_id"`
+	BoothID    *uuid.UUID `gorm:"type:uuid;index" json:"booth_id,omitempty"`
+	BoothGroup string     `gorm:"type:varchar(50);index" json:"booth_group,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// PackageRequest dipakai untuk membuat maupun mengganti paket. Paket digital
+// tidak termasuk cetak, jadi Prints-nya dianggap 0.
+type PackageRequest struct {
+	Name              string  `json:"name" binding:"required,max=100" example:"Paket Strip 2 Cetak"`
+	Price             float64 `json:"price" binding:"min=0" example:"35000"`
+	Shots             int     `json:"shots" binding:"required,min=1,max=20" example:"4"`
+	Prints            int     `json:"prints" binding:"min=0,max=50" example:"2"`
+	DigitalOnly       bool    `json:"digital_only"`
+	AnimationIncluded bool    `json:"animation_included" example:"true"`
+}
+
+type CreatePackageAssignmentRequest struct {
+	BoothID    *uuid.UUID `json:"booth_id"`
+	BoothGroup string     `json:"booth_group" binding:"max=50" example:"wedding-jakarta"`
+}
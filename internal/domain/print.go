@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrintSheetRequest mengatur lembar cetak dari satu foto. Layout 2x6 menaruh
+// dua strip di kertas 4x6; ukuran lain satu foto per lembar.
+type PrintSheetRequest struct {
+	Layout   string  `form:"layout" json:"layout" binding:"required,oneof=4x6 2x6 5x7 6x8" example:"2x6"`
+	DPI      int     `form:"dpi" json:"dpi" binding:"omitempty,min=72,max=600" example:"300"`
+	BleedMM  float64 `form:"bleed_mm" json:"bleed_mm" binding:"min=0,max=10" example:"2"`
+	MarginMM float64 `form:"margin_mm" json:"margin_mm" binding:"min=0,max=25"`
+	CutLine  bool    `form:"cut_line" json:"cut_line"`
+	Format   string  `form:"format" json:"format" binding:"omitempty,oneof=jpeg pdf" example:"pdf"`
+}
+
+// PrintPhotoRequest mencetak foto ke printer booth. Lembar selalu dikirim
+// sebagai PDF seukuran kertas, jadi Format diabaikan. Tanpa Reprint, salinan
+// diambil dari jatah cetak sesi foto tersebut.
+type PrintPhotoRequest struct {
+	PrintSheetRequest
+	Copies  int                   `json:"copies" binding:"omitempty,min=1,max=10" example:"1"`
+	Reprint *ReprintAuthorization `json:"reprint"`
+}
+
+// ReprintAuthorization mengizinkan cetak di luar jatah sesi. Isi salah satu:
+// token login staf atau PIN cetak ulang tenant. PIN lama 4-5 digit masih
+// diterima di sini, PIN baru wajib minimal 6 digit.
+type ReprintAuthorization struct {
+	StaffToken string `json:"staff_token"`
+	PIN        string `json:"pin" binding:"omitempty,numeric,min=4,max=8" example:"482916"`
+	Reason     string `json:"reason" binding:"required,max=255" example:"Foto tamu tertukar"`
+}
+
+// LegacyPrintRequest adalah body route /print lama (gambar base64). Client
+// lama tidak mengirim layout, jadi default-nya 4x6.
+type LegacyPrintRequest struct {
+	Image         string                `json:"image" binding:"required"`
+	TransactionID *uuid.UUID            `json:"transaction_id"`
+	Layout        string                `json:"layout" binding:"omitempty,oneof=4x6 2x6 5x7 6x8" example:"4x6"`
+	Reprint       *ReprintAuthorization `json:"reprint"`
+}
+
+// RequeuePrintJobRequest mengantrekan ulang job gagal/batal. Jatahnya sudah
+// dikembalikan saat job gagal atau batal, jadi tanpa Reprint salinan dipotong
+// lagi dari jatah sesi job tersebut.
+type RequeuePrintJobRequest struct {
+	Reprint *ReprintAuthorization `json:"reprint"`
+}
+
+type PrintKind string
+
+const (
+	PrintPaid    PrintKind = "paid"    // diambil dari jatah sesi
+	PrintReprint PrintKind = "reprint" // cetak ulang gratis dengan otorisasi staf
+)
+
+type PrintJobStatus string
+
+const (
+	PrintJobQueued    PrintJobStatus = "queued"
+	PrintJobPrinting  PrintJobStatus = "printing"
+	PrintJobDone      PrintJobStatus = "done"
+	PrintJobFailed    PrintJobStatus = "failed"
+	PrintJobCancelled PrintJobStatus = "cancelled"
+)
+
+// PrintJob adalah satu permintaan cetak di antrean. Dokumennya dirender saat
+// job dibuat dan disimpan di storage per job, jadi retry mengirim file yang
+// sama persis. Job dari route legacy tidak punya foto.
+type PrintJob struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID      uuid.UUID      `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID       *uuid.UUID     `gorm:"type:uuid;index" json:"booth_id"`
+	TransactionID *uuid.UUID     `gorm:"type:uuid;index" json:"transaction_id"`
+	PhotoID       *uuid.UUID     `gorm:"type:uuid" json:"photo_id"`
+	Layout        string         `gorm:"type:varchar(10)" json:"layout"`
+	Copies        int            `gorm:"default:1" json:"copies"`
+	Kind          PrintKind      `gorm:"type:varchar(10);index;default:paid" json:"kind"`
+	AuthorizedBy  *uuid.UUID     `gorm:"type:uuid" json:"authorized_by,omitempty"` // user staf, kosong kalau lewat PIN
+	AuthMethod    string         `gorm:"type:varchar(20)" json:"auth_method,omitempty"`
+	Reason        string         `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	StorageKey    string         `gorm:"type:varchar(255);not null" json:"-"`
+	ContentType   string         `gorm:"type:varchar(50)" json:"content_type"`
+	Status        PrintJobStatus `gorm:"type:varchar(20);index;default:queued" json:"status"`
+	Attempts      int            `gorm:"default:0" json:"attempts"`
+	Error         string         `gorm:"type:text" json:"error,omitempty"`
+	Driver        string         `gorm:"type:varchar(20)" json:"driver,omitempty"`
+	PrinterJobID  string         `gorm:"type:varchar(100)" json:"printer_job_id,omitempty"`
+	PrinterKey    string         `gorm:"type:varchar(255);index" json:"-"` // printer fisik, satu job printing per printer
+	ClaimedBy     string         `gorm:"type:varchar(100)" json:"-"`       // instance API yang sedang mencetak
+	HeartbeatAt   *time.Time     `json:"heartbeat_at,omitempty"`
+	NextAttemptAt time.Time      `gorm:"index" json:"next_attempt_at"`
+	CompletedAt   *time.Time     `json:"completed_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// ReprintDenial mencatat otorisasi cetak ulang yang ditolak. Dipakai untuk
+// mengunci PIN di booth yang terlalu sering salah, dan sebagai jejak audit.
+type ReprintDenial struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID  uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID   *uuid.UUID `gorm:"type:uuid;index" json:"booth_id"`
+	JobID     uuid.UUID  `gorm:"type:uuid" json:"job_id"`
+	Method    string     `gorm:"type:varchar(20)" json:"method"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// ReprintPINCounter adalah penghitung percobaan PIN cetak ulang per booth
+// dalam satu jendela waktu. Dinaikkan secara atomik sebelum PIN dicek, jadi
+// percobaan bersamaan tidak bisa melewati batas.
+type ReprintPINCounter struct {
+	BoothID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"booth_id"`
+	Attempts        int       `gorm:"not null" json:"attempts"`
+	WindowStartedAt time.Time `gorm:"not null" json:"window_started_at"`
+}
+
+// PrintJobFilter dipakai untuk query antrean cetak milik tenant.
+type PrintJobFilter struct {
+	BoothID       *uuid.UUID
+	TransactionID *uuid.UUID
+	Status        PrintJobStatus
+	Limit         int
+	Offset        int
+}
+
+// PrintReportFilter membatasi laporan cetak ke rentang waktu atau satu booth.
+type PrintReportFilter struct {
+	BoothID *uuid.UUID
+	From    *time.Time
+	To      *time.Time
+}
+
+// PrintReport adalah jumlah lembar tercetak per booth, dipisah antara yang
+// dibayar dari jatah sesi dan cetak ulang gratis.
+type PrintReport struct {
+	BoothID       uuid.UUID `json:"booth_id"`
+	BoothName     string    `json:"booth_name"`
+	Jobs          int       `json:"jobs"`
+	PaidPrints    int       `json:"paid_prints"`
+	ReprintPrints int       `json:"reprint_prints"`
+}
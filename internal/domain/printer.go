@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLowMediaThreshold dipakai kalau printer tidak mengatur batasnya sendiri.
+const DefaultLowMediaThreshold = 20
+
+// Printer adalah printer yang terpasang di satu booth beserta sisa medianya.
+// Remaining berkurang tiap job cetak selesai dan bisa ditimpa oleh laporan
+// booth. Booth tanpa printer memakai printer default dari config server.
+type Printer struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID          uuid.UUID  `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID           uuid.UUID  `gorm:"type:uuid;uniqueIndex;not null" json:"booth_id"`
+	Name              string     `gorm:"type:varchar(100);not null" json:"name"`
+	Driver            string     `gorm:"type:varchar(20);not null" json:"driver"`  // ipp atau sumatra
+	URI               string     `gorm:"type:varchar(255)" json:"uri,omitempty"`   // ipp://host:631/printers/<antrian>
+	Queue             string     `gorm:"type:varchar(100)" json:"queue,omitempty"` // nama printer Windows untuk SumatraPDF
+	MediaSize         string     `gorm:"type:varchar(10)" json:"media_size"`       // 4x6, 5x7, 6x8; kosong = tidak dicek
+	PrintsPerRoll     int        `gorm:"default:0" json:"prints_per_roll"`
+	Remaining         int        `gorm:"default:0" json:"remaining"`
+	LowMediaThreshold int        `gorm:"default:0" json:"low_media_threshold"`
+	LowMedia          bool       `gorm:"default:false" json:"low_media"`
+	State             string     `gorm:"type:varchar(20)" json:"state,omitempty"` // laporan terakhir dari booth
+	StateReasons      string     `gorm:"type:varchar(255)" json:"state_reasons,omitempty"`
+	ReportedAt        *time.Time `json:"reported_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Tracked berarti sisa media printer ini dihitung.
+func (p *Printer) Tracked() bool {
+	return p.PrintsPerRoll > 0
+}
+
+// Threshold mengembalikan batas media menipis yang berlaku.
+func (p *Printer) Threshold() int {
+	if p.LowMediaThreshold > 0 {
+		return p.LowMediaThreshold
+	}
+	return DefaultLowMediaThreshold
+}
+
+type UpdatePrinterRequest struct {
+	Name              string `json:"name" binding:"required,max=100" example:"DNP DS620"`
+	Driver            string `json:"driver" binding:"required,oneof=ipp sumatra" example:"ipp"`
+	URI               string `json:"uri" binding:"required_if=Driver ipp,omitempty,url" example:"ipp://localhost:631/printers/DNP_DS620"`
+	Queue             string `json:"queue" binding:"required_if=Driver sumatra,max=100" example:"Brother HL-L5100DN series"`
+	MediaSize         string `json:"media_size" binding:"omitempty,oneof=4x6 5x7 6x8" example:"4x6"`
+	PrintsPerRoll     int    `json:"prints_per_roll" binding:"min=0,max=10000" example:"400"`
+	LowMediaThreshold int    `json:"low_media_threshold" binding:"min=0,max=10000" example:"40"`
+}
+
+// ReloadPrinterRequest dipakai setelah ganti roll. Remaining kosong berarti
+// roll penuh (PrintsPerRoll).
+type ReloadPrinterRequest struct {
+	Remaining *int `json:"remaining" binding:"omitempty,min=0,max=10000" example:"400"`
+}
+
+// PrinterReport adalah status printer yang dilaporkan aplikasi booth.
+type PrinterReport struct {
+	State     string   `json:"state" binding:"required,max=20" example:"idle"`
+	Reasons   []string `json:"reasons" binding:"max=10" example:"media-low"`
+	Remaining *int     `json:"remaining" binding:"omitempty,min=0,max=10000" example:"120"`
+}
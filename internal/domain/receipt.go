@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReceiptTemplate adalah tata letak struk milik satu tenant. Tenant yang
+// belum mengatur memakai DefaultReceiptTemplate.
+type ReceiptTemplate struct {
+	TenantID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"tenant_id"`
+	Title        string    `gorm:"type:varchar(50)" json:"title"` // kosong = nama tenant
+	Header       string    `gorm:"type:text" json:"header"`       // alamat, NPWP, dll; satu baris per baris struk
+	Footer       string    `gorm:"type:text" json:"footer"`       // ucapan terima kasih, sosmed
+	PaperWidth   int       `gorm:"not null" json:"paper_width"`   // 58 atau 80 mm
+	TaxLabel     string    `gorm:"type:varchar(20)" json:"tax_label"`
+	TaxRate      float64   `gorm:"type:decimal(5,2)" json:"tax_rate"` // persen, 0 = baris pajak tidak dicetak
+	TaxInclusive bool      `json:"tax_inclusive"`                     // harga sesi sudah termasuk pajak
+	ShowQR       bool      `json:"show_qr"`
+	QRCaption    string    `gorm:"type:varchar(100)" json:"qr_caption"`
+	Timezone     string    `gorm:"type:varchar(50)" json:"timezone"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DefaultReceiptTemplate dipakai untuk tenant yang belum punya template.
+func DefaultReceiptTemplate(tenantID uuid.UUID) *ReceiptTemplate {
+	return &ReceiptTemplate{
+		TenantID:     tenantID,
+		Footer:       "Terima kasih!",
+		PaperWidth:   58,
+		TaxLabel:     "PPN",
+		TaxInclusive: true,
+		ShowQR:       true,
+		QRCaption:    "Scan untuk unduh foto",
+		Timezone:     "Asia/Jakarta",
+	}
+}
+
+type UpdateReceiptTemplateRequest struct {
+	Title        string  `json:"title" binding:"max=50" example:"Faiz Photo Studio"`
+	Header       string  `json:"header" binding:"max=500" example:"Jl. Sudirman No. 1, Jakarta"`
+	Footer       string  `json:"footer" binding:"max=500" example:"Terima kasih! IG @faizphoto"`
+	PaperWidth   int     `json:"paper_width" binding:"required,oneof=58 80" example:"58"`
+	TaxLabel     string  `json:"tax_label" binding:"max=20" example:"PPN"`
+	TaxRate      float64 `json:"tax_rate" binding:"min=0,max=100" example:"11"`
+	TaxInclusive bool    `json:"tax_inclusive" example:"true"`
+	ShowQR       bool    `json:"show_qr" example:"true"`
+	QRCaption    string  `json:"qr_caption" binding:"max=100" example:"Scan untuk unduh foto"`
+	Timezone     string  `json:"timezone" binding:"max=50" example:"Asia/Jakarta"`
+}
+
+// ReceiptRequest memilih format struk lewat query string.
+type ReceiptRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=escpos text pdf" example:"escpos"`
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShortLinkTarget menentukan apa yang dibuka oleh short link.
+type ShortLinkTarget string
+
+const (
+	LinkTargetPhoto   ShortLinkTarget = "photo"   // TargetID = Photo.ID
+	LinkTargetSession ShortLinkTarget = "session" // TargetID = Transaction.ID
+	LinkTargetEvent   ShortLinkTarget = "event"   // TargetID = Booth.ID, dibatasi EventStartsAt-EventEndsAt
+)
+
+// ShortLink memetakan kode pendek (/s/{code}) ke foto, galeri sesi, atau galeri acara.
+// QR yang dicetak cukup memuat kode ini, URL bertanda tangan dibuat saat discan.
+type ShortLink struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID      uuid.UUID       `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	Code          string          `gorm:"type:varchar(16);unique;not null" json:"code"`
+	TargetType    ShortLinkTarget `gorm:"type:varchar(20);not null;index:idx_short_link_target" json:"target_type"`
+	TargetID      uuid.UUID       `gorm:"type:uuid;not null;index:idx_short_link_target" json:"target_id"`
+	EventStartsAt *time.Time      `json:"event_starts_at,omitempty"`
+	EventEndsAt   *time.Time      `json:"event_ends_at,omitempty"`
+
+	// Dimensi analitik, disalin dari target saat link dibuat
+	BoothID   *uuid.UUID `gorm:"type:uuid;index" json:"booth_id"`
+	FrameName string     `gorm:"type:varchar(100)" json:"frame_name"`
+
+	ScanCount      int64      `gorm:"type:bigint;default:0" json:"scan_count"`
+	FirstScannedAt *time.Time `json:"first_scanned_at"`
+	LastScannedAt  *time.Time `json:"last_scanned_at"`
+	LastUserAgent  string     `gorm:"type:varchar(255)" json:"last_user_agent"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	ShortURL string `gorm:"-" json:"short_url"`
+}
+
+// ShortLinkScan mencatat setiap kali short link dibuka.
+type ShortLinkScan struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ShortLinkID uuid.UUID `gorm:"type:uuid;index;not null" json:"short_link_id"`
+	UserAgent   string    `gorm:"type:varchar(255)" json:"user_agent"`
+	ScannedAt   time.Time `gorm:"index" json:"scanned_at"`
+}
+
+type CreateShortLinkRequest struct {
+	TargetType ShortLinkTarget `json:"target_type" binding:"required,oneof=photo session event" example:"photo"`
+	TargetID   uuid.UUID       `json:"target_id" binding:"required"`
+	StartsAt   *time.Time      `json:"starts_at"` // wajib untuk target event
+	EndsAt     *time.Time      `json:"ends_at"`   // wajib untuk target event
+}
+
+// ShortLinkStat adalah hasil agregasi scan per booth atau per frame.
+type ShortLinkStat struct {
+	Key          string `json:"key"` // booth_id atau frame_name
+	Links        int64  `json:"links"`
+	ScannedLinks int64  `json:"scanned_links"`
+	Scans        int64  `json:"scans"`
+}
+
+// QRCodeRequest adalah opsi QR code yang dikirim lewat query string.
+type QRCodeRequest struct {
+	Format string `form:"format" example:"png"` // png atau svg
+	Size   int    `form:"size" example:"512"`
+	Level  string `form:"level" example:"M"` // L, M, Q, H
+	Logo   bool   `form:"logo"`              // tempel logo tenant di tengah
+}
@@ -1,17 +1,26 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidLogo dikembalikan kalau file logo bukan gambar PNG/JPEG yang wajar.
+var ErrInvalidLogo = errors.New("logo harus berupa gambar PNG atau JPEG maksimal 1024x1024")
+
 // Tenant adalah model data untuk pemilik bisnis (SaaS Owner).
 type Tenant struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name           string    `gorm:"not null" json:"name"`
+	LogoKey        string    `gorm:"type:varchar(255)" json:"logo_key,omitempty"`   // Logo di storage, dipakai di tengah QR
+	MaxUploadBytes int64     `gorm:"type:bigint;default:0" json:"max_upload_bytes"` // 0 = pakai default server
+	ReprintPINHash string    `gorm:"type:varchar(100)" json:"-"`                    // PIN staf untuk cetak ulang di booth
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 // RegisterTenantRequest digunakan untuk membedakan nama Bisnis dan nama Owner
@@ -22,6 +31,11 @@ type RegisterTenantRequest struct {
 	Password   string `json:"password" binding:"required,min=6"`
 }
 
+// SetReprintPINRequest mengganti PIN staf yang dipakai untuk cetak ulang di booth.
+type SetReprintPINRequest struct {
+	PIN string `json:"pin" binding:"required,numeric,min=6,max=8" example:"482916"`
+}
+
 type TenantSubscription struct {
 	ID               uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	Name             string    `gorm:"type:not null" json:"name"`
@@ -32,6 +46,9 @@ type TenantSubscription struct {
 // TenantRepository mendefinisikan cara data disimpan (Database abstraction).
 type TenantRepository interface {
 	Create(tenant *Tenant) error
+	FindByID(id uuid.UUID) (*Tenant, error)
+	UpdateLogo(id uuid.UUID, logoKey string) error
+	UpdateReprintPIN(id uuid.UUID, pinHash string) error
 }
 
 type TenantSubscriptionRepository interface {
@@ -45,6 +62,8 @@ type TenantSubscriptionRepository interface {
 type TenantUsecase interface {
 	// RegisterTenant(name string) (*Tenant, error)
 	RegisterTenant(req RegisterTenantRequest) (*Tenant, *User, error)
+	UploadLogo(ctx context.Context, tenantID uuid.UUID, file io.Reader) (*Tenant, error)
+	SetReprintPIN(tenantID uuid.UUID, pin string) error
 }
 
 type TenantPayment interface {
@@ -1,27 +1,132 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Transaction struct {
-	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	BoothID       uuid.UUID `gorm:"type:uuid;index;not null" json:"booth_id"`
-	TenantID      uuid.UUID `gorm:"type:uuid;index;not null" json:"tenant_id"`
-	ReferenceNo   string    `gorm:"type:varchar(100);unique;not null" json:"reference_no"`
-	Amount        float64   `gorm:"type:decimal(10,2)" json:"amount"`
-	PaymentStatus string    `gorm:"type:varchar(20);default:'pending'" json:"payment_status"`
-	TotalPhotos   int       `gorm:"type:integer;default:0" json:"total_photos"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID          uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	BoothID     uuid.UUID         `gorm:"type:uuid;index;not null" json:"booth_id"`
+	TenantID    uuid.UUID         `gorm:"type:uuid;index;uniqueIndex:idx_transactions_tenant_reference,priority:1;not null" json:"tenant_id"`
+	ReferenceNo string            `gorm:"type:varchar(100);uniqueIndex:idx_transactions_tenant_reference,priority:2;not null" json:"reference_no"` // unik per tenant
+	Amount      float64           `gorm:"type:decimal(10,2)" json:"amount"`
+	Status      TransactionStatus `gorm:"type:varchar(20);index;not null;default:'created'" json:"status"`
+	TotalPhotos int               `gorm:"type:integer;default:0" json:"total_photos"`
+	PrintQuota  int               `gorm:"type:integer" json:"print_quota"` // jumlah lembar yang sudah dibayar
+	PrintsUsed  int               `gorm:"type:integer;default:0" json:"prints_used"`
+
+	// Salinan paket saat sesi dimulai; mengubah paket tidak mengubah sesi ini.
+	// Sesi dari sebelum ada paket harga tidak punya PackageID.
+	PackageID         *uuid.UUID `gorm:"type:uuid;index" json:"package_id,omitempty"`
+	PackageName       string     `gorm:"type:varchar(100)" json:"package_name,omitempty"`
+	Shots             int        `gorm:"type:integer" json:"shots"`
+	DigitalOnly       bool       `json:"digital_only"`
+	AnimationIncluded bool       `json:"animation_included"`
+
+	// Waktu masuk ke tiap status; status awal created memakai CreatedAt
+	AwaitingPaymentAt *time.Time `json:"awaiting_payment_at,omitempty"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+	ShootingAt        *time.Time `json:"shooting_at,omitempty"`
+	RenderingAt       *time.Time `json:"rendering_at,omitempty"`
+	PrintedAt         *time.Time `json:"printed_at,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	FailedAt          *time.Time `json:"failed_at,omitempty"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`
+	ExpiredAt         *time.Time `json:"expired_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relationships
 	Booth Booth `gorm:"foreignKey:BoothID" json:"-"`
 }
 
+// transactionTransitions adalah alur sesi foto. Sesi gratis boleh langsung
+// dari created ke paid, dan sesi tanpa cetak boleh rendering ke completed.
+// completed, failed, dan cancelled adalah status akhir. Sesi yang ditinggal
+// dipindah server ke failed; expired hanya ada di sesi lama.
+var transactionTransitions = map[TransactionStatus][]TransactionStatus{
+	TransCreated:         {TransAwaitingPayment, TransPaid, TransCancelled, TransFailed},
+	TransAwaitingPayment: {TransPaid, TransCancelled, TransFailed},
+	TransPaid:            {TransShooting, TransCancelled, TransFailed},
+	TransShooting:        {TransRendering, TransFailed},
+	TransRendering:       {TransPrinted, TransCompleted, TransFailed},
+	TransPrinted:         {TransCompleted, TransFailed},
+}
+
+// ActiveStatuses adalah status sesi yang belum berakhir. Satu booth hanya
+// boleh punya satu sesi di status ini (dijaga unique index parsial).
+var ActiveStatuses = []TransactionStatus{TransCreated, TransAwaitingPayment, TransPaid, TransShooting, TransRendering, TransPrinted}
+
+// UnpaidActiveStatuses adalah status aktif sebelum tamu membayar. Sesi di
+// status ini yang ditinggal cepat dibereskan; sesi yang sudah dibayar diberi
+// waktu lebih lama karena tamu masih berfoto atau menunggu cetak.
+var UnpaidActiveStatuses = []TransactionStatus{TransCreated, TransAwaitingPayment}
+
+// PaidStatuses adalah status sesi yang sudah dibayar (termasuk yang sudah
+// lanjut ke foto, render, cetak, atau selesai).
+var PaidStatuses = []TransactionStatus{TransPaid, TransShooting, TransRendering, TransPrinted, TransCompleted}
+
+// CanTransitionTo mengecek apakah status s boleh pindah ke next.
+func (s TransactionStatus) CanTransitionTo(next TransactionStatus) bool {
+	return slices.Contains(transactionTransitions[s], next)
+}
+
+// Final berarti sesi sudah berakhir dan tidak bisa pindah status lagi.
+func (s TransactionStatus) Final() bool {
+	return len(transactionTransitions[s]) == 0
+}
+
+// Paid berarti sesi boleh mencetak dan dibuatkan struk.
+func (s TransactionStatus) Paid() bool {
+	return slices.Contains(PaidStatuses, s)
+}
+
+// TimestampColumn adalah kolom waktu yang diisi saat sesi masuk ke status s.
+func (s TransactionStatus) TimestampColumn() string {
+	if s == TransCreated {
+		return "created_at"
+	}
+	return string(s) + "_at"
+}
+
+// TransactionEvent mencatat setiap perpindahan status sesi.
+type TransactionEvent struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	TransactionID uuid.UUID         `gorm:"type:uuid;index;not null" json:"transaction_id"`
+	FromStatus    TransactionStatus `gorm:"type:varchar(20)" json:"from_status"` // kosong untuk event pembuatan sesi
+	ToStatus      TransactionStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Reason        string            `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	BoothID       *uuid.UUID        `gorm:"type:uuid" json:"booth_id,omitempty"` // pelaku: booth atau user dashboard
+	UserID        *uuid.UUID        `gorm:"type:uuid" json:"user_id,omitempty"`
+	CreatedAt     time.Time         `gorm:"index" json:"created_at"`
+}
+
+// AllowsAnimation berarti paket sesi ini termasuk GIF/boomerang. Sesi lama
+// tanpa paket tetap boleh, seperti sebelum ada paket harga.
+func (t *Transaction) AllowsAnimation() bool {
+	return t.PackageID == nil || t.AnimationIncluded
+}
+
+// StartSessionRequest hanya menyebut paket yang dipilih tamu. Harga dan kuota
+// cetak dihitung server dari paket, bukan dari booth.
 type StartSessionRequest struct {
-	ReferenceNo string  `json:"reference_no" binding:"required"`
-	Amount      float64 `json:"amount"`
+	ReferenceNo string    `json:"reference_no" binding:"required"`
+	PackageID   uuid.UUID `json:"package_id" binding:"required"`
+}
+
+// TransitionRequest memindahkan sesi ke status berikutnya. Sesi yang macet
+// ditandai failed oleh server, bukan lewat request ini.
+type TransitionRequest struct {
+	Status TransactionStatus `json:"status" binding:"required,oneof=awaiting_payment paid shooting rendering printed completed failed cancelled" example:"paid"`
+	Reason string            `json:"reason" binding:"max=255" example:"pembayaran QRIS diterima"`
+}
+
+// TransitionActor adalah siapa yang memindahkan status, disimpan di riwayat.
+type TransitionActor struct {
+	BoothID *uuid.UUID
+	UserID  *uuid.UUID
 }
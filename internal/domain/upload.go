@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UploadStatus string
+
+const (
+	UploadInProgress UploadStatus = "uploading"
+	UploadFinalizing UploadStatus = "finalizing"
+	UploadCompleted  UploadStatus = "completed"
+	UploadExpired    UploadStatus = "expired"
+)
+
+// Upload adalah upload foto yang bisa dilanjutkan (gaya tus). Potongan file
+// disimpan sebagai object terpisah di storage sampai upload di-finalize.
+type Upload struct {
+	ID            uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID      uuid.UUID    `gorm:"type:uuid;index;not null" json:"tenant_id"`
+	BoothID       uuid.UUID    `gorm:"type:uuid;index;not null" json:"booth_id"`
+	TransactionID *uuid.UUID   `gorm:"type:uuid" json:"transaction_id"`
+	FrameName     string       `gorm:"type:varchar(100)" json:"frame_name"`
+	Length        int64        `gorm:"type:bigint;not null" json:"length"`
+	Offset        int64        `gorm:"type:bigint;default:0" json:"offset"`
+	Status        UploadStatus `gorm:"type:varchar(20);index;default:uploading" json:"status"`
+	PhotoID       *uuid.UUID   `gorm:"type:uuid" json:"photo_id"`
+	ExpiresAt     time.Time    `gorm:"index" json:"expires_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// UploadChunk adalah potongan yang sudah diterima untuk satu offset. Setiap
+// PATCH menulis ke key sendiri; hanya key milik PATCH yang berhasil memajukan
+// offset yang dicatat di sini dan dibaca saat finalize.
+type UploadChunk struct {
+	UploadID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"upload_id"`
+	Offset    int64     `gorm:"type:bigint;primaryKey;autoIncrement:false" json:"offset"`
+	Size      int64     `gorm:"type:bigint;not null" json:"size"`
+	Key       string    `gorm:"type:varchar(255);not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateUploadRequest struct {
+	Length        int64      `json:"length" binding:"required,gt=0" example:"4194304"`
+	TransactionID *uuid.UUID `json:"transaction_id"`
+	FrameName     string     `json:"frame_name" example:"Wedding Gold"`
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/filter/usecase"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FilterHandler struct {
+	usecase usecase.FilterUsecase
+}
+
+func NewFilterHandler(u usecase.FilterUsecase) *FilterHandler {
+	return &FilterHandler{u}
+}
+
+// Create godoc
+// @Summary      Buat filter foto
+// @Description  Penyesuaian brightness/contrast/saturation (-1..1) dengan tone bw/sepia, atau LUT 3D dari file .cube. Filter tidak bisa diedit; buat filter baru untuk mengubah tampilan.
+// @Tags         Filters
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name        formData  string  true   "Nama filter"
+// @Param        tone        formData  string  false  "bw atau sepia"
+// @Param        brightness  formData  number  false  "-1..1"
+// @Param        contrast    formData  number  false  "-1..1"
+// @Param        saturation  formData  number  false  "-1..1"
+// @Param        intensity   formData  number  false  "0..1, default 1"
+// @Param        lut         formData  file    false  "LUT 3D (.cube)"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/filters [post]
+func (h *FilterHandler) Create(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.CreateFilterRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	var filter *domain.Filter
+	if file, ferr := c.FormFile("lut"); ferr == nil {
+		src, err := file.Open()
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Gagal membaca file LUT", err.Error())
+			return
+		}
+		defer src.Close()
+		filter, err = h.usecase.Create(c.Request.Context(), tenantID, req, src)
+	} else {
+		filter, err = h.usecase.Create(c.Request.Context(), tenantID, req, nil)
+	}
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Filter berhasil dibuat", filter)
+}
+
+// List godoc
+// @Summary      Daftar filter milik tenant
+// @Tags         Filters
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/filters [get]
+func (h *FilterHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	filters, err := h.usecase.List(tenantID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data filter", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data filter", filters)
+}
+
+// Get godoc
+// @Summary      Detail filter
+// @Tags         Filters
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Filter ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/filters/{id} [get]
+func (h *FilterHandler) Get(c *gin.Context) {
+	tenantID, id, ok := filterParams(c)
+	if !ok {
+		return
+	}
+
+	filter, err := h.usecase.Get(tenantID, id)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data filter", filter)
+}
+
+// Archive godoc
+// @Summary      Arsipkan filter
+// @Description  Filter hilang dari daftar, tapi frame yang sudah memakainya tetap bisa merender.
+// @Tags         Filters
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Filter ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/filters/{id} [delete]
+func (h *FilterHandler) Archive(c *gin.Context) {
+	tenantID, id, ok := filterParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Archive(tenantID, id); err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Filter berhasil diarsipkan", nil)
+}
+
+// ApplyToPhoto godoc
+// @Summary      Terapkan filter ke foto
+// @Description  Hasilnya Photo baru dengan source_photo_id menunjuk foto asal; foto asal tidak berubah.
+// @Tags         Filters
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Photo ID"
+// @Param        request  body      domain.ApplyFilterRequest  true  "Filter"
+// @Success      201  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      422  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/{id}/filter [post]
+func (h *FilterHandler) ApplyToPhoto(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.ApplyFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	photo, err := h.usecase.ApplyToPhoto(c.Request.Context(), tenantID, photoID, req)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Filter berhasil diterapkan", photo)
+}
+
+// filterParams mengambil tenant dan filter ID dari request; false berarti respons error sudah dikirim.
+func filterParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Filter tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+func writeFilterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidFilter):
+		response.Error(c, http.StatusBadRequest, "Filter tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrFilterNotFound):
+		response.Error(c, http.StatusNotFound, "Filter tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrPhotoNotFound):
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrPhotoNotFilterable):
+		response.Error(c, http.StatusUnprocessableEntity, "Foto tidak bisa difilter", err.Error())
+	default:
+		slog.Error("Gagal memproses filter", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses filter", err.Error())
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FilterRepository interface {
+	Create(filter *domain.Filter) error
+	FindByID(tenantID, id uuid.UUID) (*domain.Filter, error)
+	FindByTenant(tenantID uuid.UUID) ([]domain.Filter, error)
+	Archive(tenantID, id uuid.UUID, at time.Time) error
+}
+
+type filterRepository struct {
+	db *gorm.DB
+}
+
+func NewFilterRepository(db *gorm.DB) FilterRepository {
+	return &filterRepository{db}
+}
+
+func (r *filterRepository) Create(filter *domain.Filter) error {
+	return r.db.Create(filter).Error
+}
+
+// FindByID juga mengembalikan filter yang sudah diarsipkan, karena versi
+// frame lama masih boleh merender dengan filter tersebut.
+func (r *filterRepository) FindByID(tenantID, id uuid.UUID) (*domain.Filter, error) {
+	var filter domain.Filter
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&filter).Error
+	return &filter, err
+}
+
+func (r *filterRepository) FindByTenant(tenantID uuid.UUID) ([]domain.Filter, error) {
+	filters := []domain.Filter{}
+	err := r.db.Where("tenant_id = ? AND archived_at IS NULL", tenantID).
+		Order("name ASC").
+		Find(&filters).Error
+	return filters, err
+}
+
+func (r *filterRepository) Archive(tenantID, id uuid.UUID, at time.Time) error {
+	res := r.db.Model(&domain.Filter{}).
+		Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).
+		Update("archived_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
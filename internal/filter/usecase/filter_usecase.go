@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/filter/repository"
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFilterNotFound     = errors.New("filter tidak ditemukan")
+	ErrInvalidFilter      = errors.New("filter tidak valid")
+	ErrPhotoNotFound      = errors.New("foto tidak ditemukan")
+	ErrPhotoNotFilterable = errors.New("filter hanya bisa dipakai untuk foto diam")
+)
+
+// maxLUTBytes cukup untuk LUT 65^3 dengan presisi 6 desimal.
+const maxLUTBytes = 10 << 20
+
+// maxCachedLUTs membatasi LUT yang ditahan di memori (LUT 65^3 sekitar 3MB).
+const maxCachedLUTs = 32
+
+type FilterUsecase interface {
+	Create(ctx context.Context, tenantID uuid.UUID, req domain.CreateFilterRequest, lut io.Reader) (*domain.Filter, error)
+	List(tenantID uuid.UUID) ([]domain.Filter, error)
+	Get(tenantID, id uuid.UUID) (*domain.Filter, error)
+	Archive(tenantID, id uuid.UUID) error
+
+	// Load menyiapkan filter untuk dijalankan, termasuk filter yang sudah diarsipkan.
+	Load(ctx context.Context, tenantID, id uuid.UUID) (*imaging.Filter, error)
+	ApplyToPhoto(ctx context.Context, tenantID, photoID uuid.UUID, req domain.ApplyFilterRequest) (*domain.Photo, error)
+}
+
+type filterUsecase struct {
+	repo      repository.FilterRepository
+	photoRepo mRepo.PhotoRepository
+	media     mUcase.MediaUsecase
+	storage   storage.Storage
+
+	// LUT di storage tidak pernah berubah (filter immutable), jadi aman di-cache per key
+	mu   sync.Mutex
+	luts map[string]*imaging.LUT3D
+}
+
+func NewFilterUsecase(repo repository.FilterRepository, photoRepo mRepo.PhotoRepository, media mUcase.MediaUsecase, store storage.Storage) FilterUsecase {
+	return &filterUsecase{
+		repo:      repo,
+		photoRepo: photoRepo,
+		media:     media,
+		storage:   store,
+		luts:      map[string]*imaging.LUT3D{},
+	}
+}
+
+// Create menyimpan filter baru. File .cube diparse dulu supaya LUT rusak
+// ditolak saat upload, bukan saat tamu menunggu hasil cetak.
+func (u *filterUsecase) Create(ctx context.Context, tenantID uuid.UUID, req domain.CreateFilterRequest, lut io.Reader) (*domain.Filter, error) {
+	filter := &domain.Filter{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Name:       req.Name,
+		Tone:       req.Tone,
+		Brightness: req.Brightness,
+		Contrast:   req.Contrast,
+		Saturation: req.Saturation,
+		Intensity:  1,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if req.Intensity != nil {
+		filter.Intensity = *req.Intensity
+	}
+
+	if lut != nil {
+		if req.Tone != "" {
+			return nil, fmt.Errorf("%w: tone dan LUT tidak bisa dipakai bersamaan", ErrInvalidFilter)
+		}
+		data, err := io.ReadAll(io.LimitReader(lut, maxLUTBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxLUTBytes {
+			return nil, fmt.Errorf("%w: file LUT maksimal %dMB", ErrInvalidFilter, maxLUTBytes>>20)
+		}
+		parsed, err := imaging.ParseCube(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+
+		filter.LUTKey = fmt.Sprintf("filters/%s/%s.cube", tenantID, filter.ID)
+		filter.LUTSize = parsed.Size
+		if err := u.storage.Put(ctx, filter.LUTKey, bytes.NewReader(data), int64(len(data)), "text/plain"); err != nil {
+			return nil, err
+		}
+		u.cacheLUT(filter.LUTKey, parsed)
+	}
+
+	if err := u.repo.Create(filter); err != nil {
+		if filter.LUTKey != "" {
+			_ = u.storage.Delete(ctx, filter.LUTKey)
+		}
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (u *filterUsecase) List(tenantID uuid.UUID) ([]domain.Filter, error) {
+	return u.repo.FindByTenant(tenantID)
+}
+
+func (u *filterUsecase) Get(tenantID, id uuid.UUID) (*domain.Filter, error) {
+	filter, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrFilterNotFound
+	}
+	return filter, nil
+}
+
+// Archive menyembunyikan filter dari daftar. File LUT tidak dihapus karena
+// versi frame lama masih bisa merender dengan filter ini.
+func (u *filterUsecase) Archive(tenantID, id uuid.UUID) error {
+	if err := u.repo.Archive(tenantID, id, time.Now()); err != nil {
+		return ErrFilterNotFound
+	}
+	return nil
+}
+
+func (u *filterUsecase) Load(ctx context.Context, tenantID, id uuid.UUID) (*imaging.Filter, error) {
+	filter, err := u.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &imaging.Filter{
+		Tone:       imaging.Tone(filter.Tone),
+		Brightness: filter.Brightness,
+		Contrast:   filter.Contrast,
+		Saturation: filter.Saturation,
+		Intensity:  filter.Intensity,
+	}
+	if filter.LUTKey != "" {
+		lut, err := u.loadLUT(ctx, filter.LUTKey)
+		if err != nil {
+			return nil, err
+		}
+		f.LUT = lut
+	}
+	return f, nil
+}
+
+// ApplyToPhoto menjalankan filter ke foto yang sudah ada dan menyimpan
+// hasilnya sebagai Photo baru di sesi yang sama.
+func (u *filterUsecase) ApplyToPhoto(ctx context.Context, tenantID, photoID uuid.UUID, req domain.ApplyFilterRequest) (*domain.Photo, error) {
+	photo, err := u.photoRepo.FindByID(tenantID, photoID)
+	if err != nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.Kind == domain.PhotoAnimation || photo.ContentType == "image/gif" {
+		return nil, ErrPhotoNotFilterable
+	}
+
+	f, err := u.Load(ctx, tenantID, req.FilterID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := u.storage.Get(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	src, _, err := image.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode %s: %w", photo.StorageKey, err)
+	}
+
+	return u.media.SaveImage(ctx, photo.BoothID, tenantID, domain.SaveImageRequest{
+		TransactionID:  photo.TransactionID,
+		FrameName:      photo.FrameName,
+		FrameVersionID: photo.FrameVersionID,
+		SourcePhotoID:  &photo.ID,
+		FilterID:       &req.FilterID,
+		Kind:           photo.Kind,
+	}, f.Apply(src))
+}
+
+func (u *filterUsecase) loadLUT(ctx context.Context, key string) (*imaging.LUT3D, error) {
+	u.mu.Lock()
+	lut, ok := u.luts[key]
+	u.mu.Unlock()
+	if ok {
+		return lut, nil
+	}
+
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	lut, err = imaging.ParseCube(body)
+	if err != nil {
+		return nil, err
+	}
+	u.cacheLUT(key, lut)
+	return lut, nil
+}
+
+func (u *filterUsecase) cacheLUT(key string, lut *imaging.LUT3D) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// Cukup dikosongkan kalau penuh, LUT yang sering dipakai akan cepat masuk lagi
+	if len(u.luts) >= maxCachedLUTs {
+		u.luts = map[string]*imaging.LUT3D{}
+	}
+	u.luts[key] = lut
+}
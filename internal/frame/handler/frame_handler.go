@@ -0,0 +1,487 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/frame/usecase"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FrameHandler struct {
+	usecase usecase.FrameUsecase
+}
+
+func NewFrameHandler(u usecase.FrameUsecase) *FrameHandler {
+	return &FrameHandler{u}
+}
+
+// Create godoc
+// @Summary      Buat template frame
+// @Description  Layout dikirim sebagai JSON (domain.FrameLayout) di field "layout". Overlay PNG transparan opsional; kalau width/height layout kosong, ukuran overlay yang dipakai.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name     formData  string  true   "Nama frame"
+// @Param        layout   formData  string  true   "Layout JSON"
+// @Param        overlay  formData  file    false  "Overlay PNG"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/frames [post]
+func (h *FrameHandler) Create(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		response.Error(c, http.StatusBadRequest, "Nama frame wajib diisi", nil)
+		return
+	}
+	layout, overlay, ok := bindFrameForm(c)
+	if !ok {
+		return
+	}
+	if layout == nil {
+		layout = &domain.FrameLayout{}
+	}
+	if overlay != nil {
+		defer overlay.Close()
+	}
+
+	frame, err := h.usecase.Create(c.Request.Context(), tenantID, name, *layout, readerOrNil(overlay))
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Frame berhasil dibuat", frame)
+}
+
+// List godoc
+// @Summary      Daftar frame milik tenant
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/frames [get]
+func (h *FrameHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	frames, err := h.usecase.List(tenantID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data frame", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data frame", frames)
+}
+
+// Get godoc
+// @Summary      Detail frame
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Frame ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id} [get]
+func (h *FrameHandler) Get(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Frame tidak ditemukan", nil)
+		return
+	}
+
+	frame, err := h.usecase.Get(tenantID, id)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data frame", frame)
+}
+
+// Update godoc
+// @Summary      Edit frame
+// @Description  Layout atau overlay baru selalu membuat versi baru; versi lama tidak berubah. Kirim publish=true supaya versi baru langsung dipakai booth.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id       path      string  true   "Frame ID"
+// @Param        name     formData  string  false  "Nama frame"
+// @Param        layout   formData  string  false  "Layout JSON"
+// @Param        overlay  formData  file    false  "Overlay PNG"
+// @Param        publish  formData  bool    false  "Langsung publish versi baru"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id} [put]
+func (h *FrameHandler) Update(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+
+	layout, overlay, ok := bindFrameForm(c)
+	if !ok {
+		return
+	}
+	if overlay != nil {
+		defer overlay.Close()
+	}
+	publish, _ := strconv.ParseBool(c.PostForm("publish"))
+
+	frame, err := h.usecase.Update(c.Request.Context(), tenantID, id, usecase.FrameChanges{
+		Name:    c.PostForm("name"),
+		Layout:  layout,
+		Overlay: readerOrNil(overlay),
+		Publish: publish,
+	})
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Frame berhasil diubah", frame)
+}
+
+// Archive godoc
+// @Summary      Arsipkan frame
+// @Description  Frame hilang dari daftar dan booth, tapi versinya tetap disimpan untuk foto yang sudah dirender.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Frame ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id} [delete]
+func (h *FrameHandler) Archive(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Archive(tenantID, id); err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Frame berhasil diarsipkan", nil)
+}
+
+// Versions godoc
+// @Summary      Riwayat versi frame
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Frame ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id}/versions [get]
+func (h *FrameHandler) Versions(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.usecase.Versions(tenantID, id)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil versi frame", versions)
+}
+
+// Publish godoc
+// @Summary      Publish versi frame
+// @Description  Menjadikan versi ini yang dipakai booth. Bisa dipakai untuk rollback.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true  "Frame ID"
+// @Param        version  path      int     true  "Nomor versi"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id}/versions/{version}/publish [post]
+func (h *FrameHandler) Publish(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		response.Error(c, http.StatusNotFound, "Versi frame tidak ditemukan", nil)
+		return
+	}
+
+	frame, err := h.usecase.Publish(tenantID, id, version)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Versi frame berhasil dipublish", frame)
+}
+
+// Preview godoc
+// @Summary      Preview render frame
+// @Description  Render frame dengan foto contoh. Tanpa parameter version, dipakai versi published.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      image/png
+// @Param        id       path   string  true   "Frame ID"
+// @Param        version  query  int     false  "Nomor versi"
+// @Success      200
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id}/preview [get]
+func (h *FrameHandler) Preview(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+	version, _ := strconv.Atoi(c.Query("version"))
+
+	data, err := h.usecase.Preview(c.Request.Context(), tenantID, id, version)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+// Assign godoc
+// @Summary      Pasang frame ke booth atau grup booth
+// @Description  Isi salah satu: booth_id atau booth_group. starts_at/ends_at opsional untuk jadwal acara.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Frame ID"
+// @Param        request  body      domain.CreateFrameAssignmentRequest  true  "Target dan jadwal"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id}/assignments [post]
+func (h *FrameHandler) Assign(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateFrameAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	assignment, err := h.usecase.Assign(tenantID, id, req)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Frame berhasil dipasang", assignment)
+}
+
+// Assignments godoc
+// @Summary      Daftar pemasangan frame
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Frame ID"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/frames/{id}/assignments [get]
+func (h *FrameHandler) Assignments(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.usecase.Assignments(tenantID, id)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil pemasangan frame", assignments)
+}
+
+// Unassign godoc
+// @Summary      Lepas pemasangan frame
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id             path  string  true  "Frame ID"
+// @Param        assignment_id  path  string  true  "Assignment ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/frames/{id}/assignments/{assignment_id} [delete]
+func (h *FrameHandler) Unassign(c *gin.Context) {
+	tenantID, id, ok := frameParams(c)
+	if !ok {
+		return
+	}
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Frame tidak ditemukan", nil)
+		return
+	}
+
+	if err := h.usecase.Unassign(tenantID, id, assignmentID); err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Pemasangan frame dilepas", nil)
+}
+
+// CurrentFrames godoc
+// @Summary      Frame aktif untuk booth ini
+// @Description  Dipanggil booth saat startup. Berisi frame yang dipasang ke booth atau grupnya dan jadwalnya sedang berjalan.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/booths/me/frames [get]
+func (h *FrameHandler) CurrentFrames(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	boothID, _ := utils.GetBoothID(c)
+
+	frames, err := h.usecase.CurrentForBooth(tenantID, boothID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil frame booth", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil frame booth", frames)
+}
+
+// RenderSession godoc
+// @Summary      Render strip foto sesi di server
+// @Description  Menyusun jepretan mentah sesi ke dalam frame. Hasilnya disimpan sebagai Photo kind "composite" dan dikembalikan beserta link download.
+// @Tags         Frames
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Transaction ID"
+// @Param        request  body      domain.RenderSessionRequest  true  "Frame dan urutan foto"
+// @Success      201  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      422  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/render [post]
+func (h *FrameHandler) RenderSession(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	boothID, _ := utils.GetBoothID(c)
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Sesi tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.RenderSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	photo, err := h.usecase.RenderSession(c.Request.Context(), boothID, tenantID, trxID, req)
+	if err != nil {
+		writeFrameError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Strip foto berhasil dirender", photo)
+}
+
+// frameParams mengambil tenant dan frame ID dari request; false berarti respons error sudah dikirim.
+func frameParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Frame tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+// bindFrameForm membaca field layout (JSON) dan file overlay dari form
+// multipart. Keduanya opsional; false berarti respons error sudah dikirim.
+func bindFrameForm(c *gin.Context) (*domain.FrameLayout, multipart.File, bool) {
+	var layout *domain.FrameLayout
+	if raw := c.PostForm("layout"); raw != "" {
+		layout = &domain.FrameLayout{}
+		if err := json.Unmarshal([]byte(raw), layout); err != nil {
+			response.Error(c, http.StatusBadRequest, "Layout frame harus JSON yang valid", err.Error())
+			return nil, nil, false
+		}
+	}
+
+	file, err := c.FormFile("overlay")
+	if err != nil {
+		return layout, nil, true
+	}
+	src, err := file.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Gagal membaca overlay", err.Error())
+		return nil, nil, false
+	}
+	return layout, src, true
+}
+
+// readerOrNil menghindari interface berisi pointer nil saat overlay tidak dikirim.
+func readerOrNil(f multipart.File) io.Reader {
+	if f == nil {
+		return nil
+	}
+	return f
+}
+
+func writeFrameError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidLayout), errors.Is(err, usecase.ErrInvalidOverlay), errors.Is(err, usecase.ErrInvalidAssignment):
+		response.Error(c, http.StatusBadRequest, "Template frame tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrFrameNotFound):
+		response.Error(c, http.StatusNotFound, "Frame tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrVersionNotFound):
+		response.Error(c, http.StatusNotFound, "Versi frame tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrBackgroundNotFound):
+		response.Error(c, http.StatusNotFound, "Latar tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrSessionNotFound):
+		response.Error(c, http.StatusNotFound, "Sesi tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrNotEnoughShots), errors.Is(err, usecase.ErrPhotoNotInSession):
+		response.Error(c, http.StatusUnprocessableEntity, "Foto sesi tidak cocok dengan frame", err.Error())
+	default:
+		slog.Error("Gagal memproses frame", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses frame", err.Error())
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FrameRepository interface {
+	Create(frame *domain.Frame, version *domain.FrameVersion) error
+	FindByID(tenantID, id uuid.UUID) (*domain.Frame, error)
+	FindByTenant(tenantID uuid.UUID) ([]domain.Frame, error)
+	UpdateName(tenantID, id uuid.UUID, name string) error
+	Archive(tenantID, id uuid.UUID, at time.Time) error
+
+	CreateVersion(version *domain.FrameVersion, publish bool) error
+	FindVersion(frameID uuid.UUID, version int) (*domain.FrameVersion, error)
+	FindVersions(frameID uuid.UUID) ([]domain.FrameVersion, error)
+	Publish(frameID, versionID uuid.UUID) error
+
+	CreateAssignment(assignment *domain.FrameAssignment) error
+	FindAssignments(tenantID, frameID uuid.UUID) ([]domain.FrameAssignment, error)
+	DeleteAssignment(tenantID, frameID, id uuid.UUID) error
+	FindCurrentForBooth(tenantID, boothID uuid.UUID, group string, now time.Time) ([]domain.Frame, error)
+}
+
+type frameRepository struct {
+	db *gorm.DB
+}
+
+func NewFrameRepository(db *gorm.DB) FrameRepository {
+	return &frameRepository{db}
+}
+
+// Create menyimpan frame baru beserta versi pertamanya (langsung published).
+// Versi disimpan duluan karena frame punya foreign key ke versi published.
+func (r *frameRepository) Create(frame *domain.Frame, version *domain.FrameVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		frame.LatestVersion = version.Version
+		frame.PublishedVersionID = &version.ID
+		return tx.Omit("PublishedVersion").Create(frame).Error
+	})
+}
+
+func (r *frameRepository) FindByID(tenantID, id uuid.UUID) (*domain.Frame, error) {
+	var frame domain.Frame
+	err := r.db.Preload("PublishedVersion").
+		Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).
+		First(&frame).Error
+	return &frame, err
+}
+
+func (r *frameRepository) FindByTenant(tenantID uuid.UUID) ([]domain.Frame, error) {
+	frames := []domain.Frame{}
+	err := r.db.Preload("PublishedVersion").
+		Where("tenant_id = ? AND archived_at IS NULL", tenantID).
+		Order("name ASC").
+		Find(&frames).Error
+	return frames, err
+}
+
+func (r *frameRepository) UpdateName(tenantID, id uuid.UUID, name string) error {
+	return r.db.Model(&domain.Frame{}).Where("tenant_id = ? AND id = ?", tenantID, id).Update("name", name).Error
+}
+
+// Archive menyembunyikan frame tanpa menghapus versinya, karena foto lama
+// masih menunjuk ke versi tersebut.
+func (r *frameRepository) Archive(tenantID, id uuid.UUID, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Frame{}).
+			Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).
+			Update("archived_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("frame_id = ?", id).Delete(&domain.FrameAssignment{}).Error
+	})
+}
+
+// CreateVersion menambah versi baru. Nomor versi dikunci lewat unique index,
+// jadi dua edit bersamaan tidak bisa menghasilkan nomor yang sama.
+func (r *frameRepository) CreateVersion(version *domain.FrameVersion, publish bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"latest_version": version.Version, "updated_at": time.Now()}
+		if publish {
+			updates["published_version_id"] = version.ID
+		}
+		return tx.Model(&domain.Frame{}).Where("id = ?", version.FrameID).Updates(updates).Error
+	})
+}
+
+func (r *frameRepository) FindVersion(frameID uuid.UUID, version int) (*domain.FrameVersion, error) {
+	var v domain.FrameVersion
+	err := r.db.Where("frame_id = ? AND version = ?", frameID, version).First(&v).Error
+	return &v, err
+}
+
+func (r *frameRepository) FindVersions(frameID uuid.UUID) ([]domain.FrameVersion, error) {
+	versions := []domain.FrameVersion{}
+	err := r.db.Where("frame_id = ?", frameID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *frameRepository) Publish(frameID, versionID uuid.UUID) error {
+	return r.db.Model(&domain.Frame{}).Where("id = ?", frameID).Update("published_version_id", versionID).Error
+}
+
+func (r *frameRepository) CreateAssignment(assignment *domain.FrameAssignment) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *frameRepository) FindAssignments(tenantID, frameID uuid.UUID) ([]domain.FrameAssignment, error) {
+	assignments := []domain.FrameAssignment{}
+	err := r.db.Where("tenant_id = ? AND frame_id = ?", tenantID, frameID).Order("created_at ASC").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *frameRepository) DeleteAssignment(tenantID, frameID, id uuid.UUID) error {
+	res := r.db.Where("tenant_id = ? AND frame_id = ? AND id = ?", tenantID, frameID, id).Delete(&domain.FrameAssignment{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindCurrentForBooth mencari frame published yang dipasang ke booth ini
+// (langsung atau lewat grup) dan jadwalnya mencakup waktu now.
+func (r *frameRepository) FindCurrentForBooth(tenantID, boothID uuid.UUID, group string, now time.Time) ([]domain.Frame, error) {
+	assigned := r.db.Model(&domain.FrameAssignment{}).Select("frame_id").
+		Where("tenant_id = ?", tenantID).
+		Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", now, now)
+	if group != "" {
+		assigned = assigned.Where("booth_id = ? OR booth_group = ?", boothID, group)
+	} else {
+		assigned = assigned.Where("booth_id = ?", boothID)
+	}
+
+	frames := []domain.Frame{}
+	err := r.db.Preload("PublishedVersion").
+		Where("tenant_id = ? AND archived_at IS NULL AND published_version_id IS NOT NULL", tenantID).
+		Where("id IN (?)", assigned).
+		Order("name ASC").
+		Find(&frames).Error
+	return frames, err
+}
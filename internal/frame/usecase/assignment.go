@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Assign memasang frame ke satu booth atau satu grup booth. Rentang waktu
+// opsional; tanpa rentang, frame aktif sampai assignment dihapus.
+func (u *frameUsecase) Assign(tenantID, frameID uuid.UUID, req domain.CreateFrameAssignmentRequest) (*domain.FrameAssignment, error) {
+	if _, err := u.Get(tenantID, frameID); err != nil {
+		return nil, err
+	}
+	if (req.BoothID == nil) == (req.BoothGroup == "") {
+		return nil, ErrInvalidAssignment
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, ErrInvalidAssignment
+	}
+	if req.BoothID != nil {
+		if _, err := u.boothRepo.FindByID(tenantID, *req.BoothID); err != nil {
+			return nil, ErrInvalidAssignment
+		}
+	}
+
+	assignment := &domain.FrameAssignment{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		FrameID:    frameID,
+		BoothID:    req.BoothID,
+		BoothGroup: req.BoothGroup,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		CreatedAt:  time.Now(),
+	}
+	if err := u.repo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (u *frameUsecase) Assignments(tenantID, frameID uuid.UUID) ([]domain.FrameAssignment, error) {
+	if _, err := u.Get(tenantID, frameID); err != nil {
+		return nil, err
+	}
+	return u.repo.FindAssignments(tenantID, frameID)
+}
+
+func (u *frameUsecase) Unassign(tenantID, frameID, id uuid.UUID) error {
+	err := u.repo.DeleteAssignment(tenantID, frameID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrFrameNotFound
+	}
+	return err
+}
+
+// CurrentForBooth dipanggil booth saat startup untuk mengambil frame yang
+// sedang aktif untuknya, lengkap dengan layout versi published.
+func (u *frameUsecase) CurrentForBooth(tenantID, boothID uuid.UUID) ([]domain.CurrentFrame, error) {
+	booth, err := u.boothRepo.FindByID(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+
+	frames, err := u.repo.FindCurrentForBooth(tenantID, boothID, booth.Group, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]domain.CurrentFrame, 0, len(frames))
+	for _, frame := range frames {
+		if frame.PublishedVersion == nil {
+			continue
+		}
+		current = append(current, domain.CurrentFrame{
+			FrameID:    frame.ID,
+			Name:       frame.Name,
+			VersionID:  frame.PublishedVersion.ID,
+			Version:    frame.PublishedVersion.Version,
+			Layout:     frame.PublishedVersion.Layout,
+			PreviewURL: "/api/v1/frames/" + frame.ID.String() + "/preview?version=" + strconv.Itoa(frame.PublishedVersion.Version),
+		})
+	}
+	return current, nil
+}
@@ -0,0 +1,454 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"time"
+
+	bgUcase "photobooth-core/internal/background/usecase"
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	flUcase "photobooth-core/internal/filter/usecase"
+	"photobooth-core/internal/frame/repository"
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+	trRepo "photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrFrameNotFound      = errors.New("frame tidak ditemukan")
+	ErrVersionNotFound    = errors.New("versi frame tidak ditemukan")
+	ErrInvalidAssignment  = errors.New("assignment harus menunjuk tepat satu booth atau satu grup booth")
+	ErrInvalidOverlay     = errors.New("overlay frame harus PNG yang valid")
+	ErrSessionNotFound    = errors.New("sesi transaksi tidak ditemukan untuk booth ini")
+	ErrNotEnoughShots     = errors.New("jumlah foto kurang untuk slot frame")
+	ErrPhotoNotInSession  = errors.New("foto bukan jepretan mentah dari sesi ini")
+	ErrBackgroundNotFound = errors.New("latar tidak ditemukan")
+)
+
+// maxOverlayBytes masih di bawah limit body global (15MB).
+const maxOverlayBytes = 12 << 20
+
+// previewMaxSide membatasi ukuran gambar preview supaya ringan di dashboard.
+const previewMaxSide = 1200
+
+type FrameUsecase interface {
+	Create(ctx context.Context, tenantID uuid.UUID, name string, layout domain.FrameLayout, overlay io.Reader) (*domain.Frame, error)
+	List(tenantID uuid.UUID) ([]domain.Frame, error)
+	Get(tenantID, id uuid.UUID) (*domain.Frame, error)
+	Update(ctx context.Context, tenantID, id uuid.UUID, changes FrameChanges) (*domain.Frame, error)
+	Archive(tenantID, id uuid.UUID) error
+	Versions(tenantID, id uuid.UUID) ([]domain.FrameVersion, error)
+	Publish(tenantID, id uuid.UUID, version int) (*domain.Frame, error)
+	Preview(ctx context.Context, tenantID, id uuid.UUID, version int) ([]byte, error)
+
+	Assign(tenantID, frameID uuid.UUID, req domain.CreateFrameAssignmentRequest) (*domain.FrameAssignment, error)
+	Assignments(tenantID, frameID uuid.UUID) ([]domain.FrameAssignment, error)
+	Unassign(tenantID, frameID, id uuid.UUID) error
+	CurrentForBooth(tenantID, boothID uuid.UUID) ([]domain.CurrentFrame, error)
+
+	RenderSession(ctx context.Context, boothID, tenantID, trxID uuid.UUID, req domain.RenderSessionRequest) (*domain.Photo, error)
+}
+
+// FrameChanges adalah isi edit frame. Layout atau overlay yang berubah
+// selalu menghasilkan versi baru; yang tidak dikirim disalin dari versi terakhir.
+type FrameChanges struct {
+	Name    string // kosong = tidak diubah
+	Layout  *domain.FrameLayout
+	Overlay io.Reader
+	Publish bool
+}
+
+type frameUsecase struct {
+	repo        repository.FrameRepository
+	photoRepo   mRepo.PhotoRepository
+	trxRepo     trRepo.TransactionRepository
+	boothRepo   bRepo.BoothRepository
+	tenantRepo  domain.TenantRepository
+	media       mUcase.MediaUsecase
+	filters     flUcase.FilterUsecase
+	backgrounds bgUcase.BackgroundUsecase
+	storage     storage.Storage
+}
+
+func NewFrameUsecase(repo repository.FrameRepository, photoRepo mRepo.PhotoRepository, trxRepo trRepo.TransactionRepository,
+	boothRepo bRepo.BoothRepository, tenantRepo domain.TenantRepository, media mUcase.MediaUsecase,
+	filters flUcase.FilterUsecase, backgrounds bgUcase.BackgroundUsecase, store storage.Storage) FrameUsecase {
+	return &frameUsecase{repo, photoRepo, trxRepo, boothRepo, tenantRepo, media, filters, backgrounds, store}
+}
+
+// Create menyimpan template frame baru dengan versi 1 yang langsung published.
+// Kalau ukuran kanvas kosong, dipakai ukuran overlay-nya.
+func (u *frameUsecase) Create(ctx context.Context, tenantID uuid.UUID, name string, layout domain.FrameLayout, overlay io.Reader) (*domain.Frame, error) {
+	frame := &domain.Frame{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	version, err := u.buildVersion(ctx, frame, 1, layout, overlay, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Create(frame, version); err != nil {
+		if version.OverlayKey != "" {
+			_ = u.storage.Delete(ctx, version.OverlayKey)
+		}
+		return nil, err
+	}
+	return u.Get(tenantID, frame.ID)
+}
+
+// Update mengganti nama frame dan/atau membuat versi baru. Versi lama tetap
+// ada, jadi foto yang sudah dirender tetap menunjuk layout aslinya.
+func (u *frameUsecase) Update(ctx context.Context, tenantID, id uuid.UUID, changes FrameChanges) (*domain.Frame, error) {
+	frame, err := u.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Name != "" && changes.Name != frame.Name {
+		if err := u.repo.UpdateName(tenantID, id, changes.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if changes.Layout != nil || changes.Overlay != nil {
+		latest, err := u.repo.FindVersion(id, frame.LatestVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		layout := latest.Layout
+		if changes.Layout != nil {
+			layout = *changes.Layout
+		}
+		version, err := u.buildVersion(ctx, frame, latest.Version+1, layout, changes.Overlay, latest.OverlayKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := u.repo.CreateVersion(version, changes.Publish); err != nil {
+			if version.OverlayKey != latest.OverlayKey {
+				_ = u.storage.Delete(ctx, version.OverlayKey)
+			}
+			return nil, err
+		}
+	}
+
+	return u.Get(tenantID, id)
+}
+
+// buildVersion memvalidasi layout dan menyimpan overlay baru (kalau ada)
+// di key khusus versi tersebut. Tanpa overlay baru, overlay sebelumnya dipakai ulang.
+func (u *frameUsecase) buildVersion(ctx context.Context, frame *domain.Frame, number int, layout domain.FrameLayout, overlay io.Reader, previousOverlay string) (*domain.FrameVersion, error) {
+	version := &domain.FrameVersion{
+		ID:         uuid.New(),
+		FrameID:    frame.ID,
+		TenantID:   frame.TenantID,
+		Version:    number,
+		OverlayKey: previousOverlay,
+		CreatedAt:  time.Now(),
+	}
+
+	var overlayPNG *imaging.Sanitized
+	if overlay != nil {
+		clean, err := u.sanitizeOverlay(overlay)
+		if err != nil {
+			return nil, err
+		}
+		overlayPNG = clean
+		if layout.Width == 0 && layout.Height == 0 {
+			layout.Width, layout.Height = clean.Width, clean.Height
+		}
+	}
+
+	if err := validateLayout(layout); err != nil {
+		return nil, err
+	}
+	// Versi baru hanya boleh memakai filter dan latar yang masih aktif
+	for _, id := range filterIDs(layout) {
+		filter, err := u.filters.Get(frame.TenantID, id)
+		if err != nil || filter.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: filter %s tidak ditemukan", domain.ErrInvalidLayout, id)
+		}
+	}
+	for _, id := range backgroundIDs(layout, nil) {
+		background, err := u.backgrounds.Get(frame.TenantID, id)
+		if err != nil || background.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: latar %s tidak ditemukan", domain.ErrInvalidLayout, id)
+		}
+	}
+	version.Layout = layout
+
+	if overlayPNG != nil {
+		version.OverlayKey = fmt.Sprintf("frames/%s/%s/v%d/overlay.png", frame.TenantID, frame.ID, number)
+		if err := u.storage.Put(ctx, version.OverlayKey, bytes.NewReader(overlayPNG.Data), int64(len(overlayPNG.Data)), overlayPNG.ContentType); err != nil {
+			return nil, err
+		}
+	}
+	return version, nil
+}
+
+func (u *frameUsecase) sanitizeOverlay(overlay io.Reader) (*imaging.Sanitized, error) {
+	data, err := io.ReadAll(io.LimitReader(overlay, maxOverlayBytes))
+	if err != nil {
+		return nil, err
+	}
+	clean, err := imaging.Sanitize(bytes.NewReader(data), imaging.Limits{MaxWidth: maxCanvasSide, MaxHeight: maxCanvasSide}, 0)
+	if err != nil || clean.ContentType != "image/png" {
+		return nil, ErrInvalidOverlay
+	}
+	return clean, nil
+}
+
+func (u *frameUsecase) List(tenantID uuid.UUID) ([]domain.Frame, error) {
+	return u.repo.FindByTenant(tenantID)
+}
+
+func (u *frameUsecase) Get(tenantID, id uuid.UUID) (*domain.Frame, error) {
+	frame, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrFrameNotFound
+	}
+	return frame, nil
+}
+
+func (u *frameUsecase) Archive(tenantID, id uuid.UUID) error {
+	if err := u.repo.Archive(tenantID, id, time.Now()); err != nil {
+		return ErrFrameNotFound
+	}
+	return nil
+}
+
+func (u *frameUsecase) Versions(tenantID, id uuid.UUID) ([]domain.FrameVersion, error) {
+	if _, err := u.Get(tenantID, id); err != nil {
+		return nil, err
+	}
+	return u.repo.FindVersions(id)
+}
+
+// Publish menjadikan versi tertentu sebagai yang dipakai booth. Bisa juga
+// dipakai untuk rollback ke versi lama.
+func (u *frameUsecase) Publish(tenantID, id uuid.UUID, version int) (*domain.Frame, error) {
+	if _, err := u.Get(tenantID, id); err != nil {
+		return nil, err
+	}
+	v, err := u.repo.FindVersion(id, version)
+	if err != nil {
+		return nil, ErrVersionNotFound
+	}
+	if err := u.repo.Publish(id, v.ID); err != nil {
+		return nil, err
+	}
+	return u.Get(tenantID, id)
+}
+
+// Preview merender versi frame dengan foto contoh, hasilnya PNG.
+func (u *frameUsecase) Preview(ctx context.Context, tenantID, id uuid.UUID, version int) ([]byte, error) {
+	frame, err := u.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := u.resolveVersion(frame, version)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := u.loadAssets(ctx, tenantID, v, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values := renderValues{Booth: "Booth", Reference: "PREVIEW-0001", Frame: frame.Name, Time: time.Now()}
+	if tenant, err := u.tenantRepo.FindByID(tenantID); err == nil {
+		values.Tenant = tenant.Name
+	}
+
+	img, err := renderFrame(v.Layout, assets, placeholderShot, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, imaging.Fit(img, previewMaxSide)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resolveVersion mengambil versi tertentu, atau versi published kalau 0.
+func (u *frameUsecase) resolveVersion(frame *domain.Frame, version int) (*domain.FrameVersion, error) {
+	if version == 0 {
+		if frame.PublishedVersion == nil {
+			return nil, ErrVersionNotFound
+		}
+		return frame.PublishedVersion, nil
+	}
+	v, err := u.repo.FindVersion(frame.ID, version)
+	if err != nil {
+		return nil, ErrVersionNotFound
+	}
+	return v, nil
+}
+
+// placeholderShot adalah foto contoh abu-abu bernomor untuk preview.
+func placeholderShot(i int) (image.Image, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 1500, 1000))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{200, 200, 200, 255}), image.Point{}, draw.Src)
+	err := imaging.DrawText(img, fmt.Sprintf("Foto %d", i+1), 0, 420, 1500, imaging.TextStyle{
+		Size:  160,
+		Bold:  true,
+		Color: color.RGBA{120, 120, 120, 255},
+		Align: "center",
+	})
+	return img, err
+}
+
+// RenderSession menyusun jepretan mentah sesi ke dalam frame dan menyimpan
+// hasilnya sebagai Photo jenis composite milik sesi yang sama.
+func (u *frameUsecase) RenderSession(ctx context.Context, boothID, tenantID, trxID uuid.UUID, req domain.RenderSessionRequest) (*domain.Photo, error) {
+	trx, err := u.trxRepo.FindByID(tenantID, trxID)
+	if err != nil || trx.BoothID != boothID {
+		return nil, ErrSessionNotFound
+	}
+	frame, err := u.Get(tenantID, req.FrameID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := u.resolveVersion(frame, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	shots, err := u.sessionShots(tenantID, trxID, req.PhotoIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(shots) < shotCount(version.Layout) {
+		return nil, fmt.Errorf("%w: butuh %d, ada %d", ErrNotEnoughShots, shotCount(version.Layout), len(shots))
+	}
+
+	if req.BackgroundID != nil {
+		// Pilihan tamu harus latar yang masih aktif
+		background, err := u.backgrounds.Get(tenantID, *req.BackgroundID)
+		if err != nil || background.ArchivedAt != nil {
+			return nil, ErrBackgroundNotFound
+		}
+	}
+	assets, err := u.loadAssets(ctx, tenantID, version, req.BackgroundID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := renderValues{Reference: trx.ReferenceNo, Frame: frame.Name, Time: trx.CreatedAt}
+	if tenant, err := u.tenantRepo.FindByID(tenantID); err == nil {
+		values.Tenant = tenant.Name
+	}
+	if booth, err := u.boothRepo.FindByID(tenantID, boothID); err == nil {
+		values.Booth = booth.Name
+	}
+
+	img, err := renderFrame(version.Layout, assets, func(i int) (image.Image, error) {
+		return u.decode(ctx, shots[i].StorageKey)
+	}, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.media.SaveImage(ctx, boothID, tenantID, domain.SaveImageRequest{
+		TransactionID:  &trxID,
+		FrameName:      frame.Name,
+		FrameVersionID: &version.ID,
+		Kind:           domain.PhotoComposite,
+	}, img)
+}
+
+// sessionShots mengambil foto mentah sesi, sesuai urutan photoIDs kalau
+// dikirim, atau urutan upload kalau kosong.
+func (u *frameUsecase) sessionShots(tenantID, trxID uuid.UUID, photoIDs []uuid.UUID) ([]domain.Photo, error) {
+	if len(photoIDs) == 0 {
+		photos, err := u.photoRepo.FindByTenant(tenantID, domain.PhotoFilter{TransactionID: &trxID, Kind: domain.PhotoRaw})
+		if err != nil {
+			return nil, err
+		}
+		// Repository mengurutkan terbaru dulu, jepretan harus dari yang pertama
+		for i, j := 0, len(photos)-1; i < j; i, j = i+1, j-1 {
+			photos[i], photos[j] = photos[j], photos[i]
+		}
+		return photos, nil
+	}
+
+	photos := make([]domain.Photo, 0, len(photoIDs))
+	for _, id := range photoIDs {
+		photo, err := u.photoRepo.FindByID(tenantID, id)
+		if err != nil || photo.TransactionID == nil || *photo.TransactionID != trxID || photo.Kind != domain.PhotoRaw {
+			return nil, fmt.Errorf("%w: %s", ErrPhotoNotInSession, id)
+		}
+		photos = append(photos, *photo)
+	}
+	return photos, nil
+}
+
+// loadAssets memuat overlay, filter dan latar yang dirujuk versi frame.
+// Filter dan latar yang sudah diarsipkan tetap dipakai supaya versi lama
+// menghasilkan gambar yang sama.
+func (u *frameUsecase) loadAssets(ctx context.Context, tenantID uuid.UUID, version *domain.FrameVersion, background *uuid.UUID) (renderAssets, error) {
+	assets := renderAssets{
+		Filters:     map[uuid.UUID]*imaging.Filter{},
+		Backgrounds: map[uuid.UUID]image.Image{},
+		Background:  background,
+	}
+
+	if version.OverlayKey != "" {
+		overlay, err := u.decode(ctx, version.OverlayKey)
+		if err != nil {
+			return assets, err
+		}
+		assets.Overlay = overlay
+	}
+
+	for _, id := range filterIDs(version.Layout) {
+		f, err := u.filters.Load(ctx, tenantID, id)
+		if err != nil {
+			return assets, fmt.Errorf("gagal memuat filter %s: %w", id, err)
+		}
+		assets.Filters[id] = f
+	}
+
+	for _, id := range backgroundIDs(version.Layout, background) {
+		bg, err := u.backgrounds.Load(ctx, tenantID, id)
+		if err != nil {
+			return assets, fmt.Errorf("gagal memuat latar %s: %w", id, err)
+		}
+		assets.Backgrounds[id] = bg
+	}
+	return assets, nil
+}
+
+func (u *frameUsecase) decode(ctx context.Context, key string) (image.Image, error) {
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode %s: %w", key, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"time"
+
+	bgUcase "photobooth-core/internal/background/usecase"
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/imaging"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+)
+
+const (
+	maxCanvasSide = 8000
+	maxFontSize   = 500
+)
+
+// shotLoader membuka foto ke-i dari sesi. Renderer memanggilnya per slot,
+// jadi foto resolusi penuh tidak perlu ditahan di memori bersamaan.
+type shotLoader func(i int) (image.Image, error)
+
+// renderValues adalah isi placeholder teks frame.
+type renderValues struct {
+	Tenant    string
+	Booth     string
+	Reference string
+	Frame     string
+	Time      time.Time
+}
+
+// renderAssets adalah file pendukung layout yang sudah dimuat pemanggil.
+// Background, kalau diisi, menggantikan latar semua slot chroma (pilihan tamu).
+type renderAssets struct {
+	Overlay     image.Image
+	Filters     map[uuid.UUID]*imaging.Filter
+	Backgrounds map[uuid.UUID]image.Image
+	Background  *uuid.UUID
+}
+
+// renderFrame menyusun kanvas: background, foto di slot, overlay, lalu teks.
+// Di tiap slot urutannya: fit, chroma key + latar, lalu filter.
+func renderFrame(layout domain.FrameLayout, assets renderAssets, shot shotLoader, values renderValues) (*image.RGBA, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, layout.Width, layout.Height))
+
+	background := color.RGBA{255, 255, 255, 255}
+	if layout.Background != "" {
+		c, err := imaging.ParseHexColor(layout.Background)
+		if err != nil {
+			return nil, err
+		}
+		background = c
+	}
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	for i, slot := range layout.Slots {
+		index := i
+		if slot.Shot != nil {
+			index = *slot.Shot
+		}
+		src, err := shot(index)
+		if err != nil {
+			return nil, err
+		}
+
+		var fitted *image.RGBA
+		if slot.Fit == "contain" {
+			fitted = imaging.Contain(src, slot.Width, slot.Height)
+		} else {
+			fitted = imaging.Cover(src, slot.Width, slot.Height)
+		}
+		// Chroma dan filter dijalankan setelah di-fit, jadi cuma sebanyak pixel slot
+		if slot.Chroma != nil {
+			key, err := bgUcase.NewChromaKey(*slot.Chroma)
+			if err != nil {
+				return nil, err
+			}
+			fitted = key.Apply(fitted)
+			if bgID := slotBackground(slot, assets.Background); bgID != nil {
+				if bg := assets.Backgrounds[*bgID]; bg != nil {
+					fitted = imaging.Composite(fitted, bg)
+				}
+			}
+		}
+		if filterID := slotFilter(layout, slot); filterID != nil {
+			if f := assets.Filters[*filterID]; f != nil {
+				fitted = f.Apply(fitted)
+			}
+		}
+		cx := float64(slot.X) + float64(slot.Width)/2
+		cy := float64(slot.Y) + float64(slot.Height)/2
+		imaging.DrawRotated(canvas, fitted, cx, cy, slot.Rotation)
+	}
+
+	if overlay := assets.Overlay; overlay != nil {
+		if overlay.Bounds().Dx() != layout.Width || overlay.Bounds().Dy() != layout.Height {
+			draw.CatmullRom.Scale(canvas, canvas.Bounds(), overlay, overlay.Bounds(), draw.Over, nil)
+		} else {
+			draw.Draw(canvas, canvas.Bounds(), overlay, overlay.Bounds().Min, draw.Over)
+		}
+	}
+
+	for _, text := range layout.Texts {
+		col := color.RGBA{0, 0, 0, 255}
+		if text.Color != "" {
+			c, err := imaging.ParseHexColor(text.Color)
+			if err != nil {
+				return nil, err
+			}
+			col = c
+		}
+		width := text.Width
+		if width == 0 {
+			width = layout.Width - text.X
+		}
+		err := imaging.DrawText(canvas, expandText(text, values), text.X, text.Y, width, imaging.TextStyle{
+			Size:  text.FontSize,
+			Bold:  text.Bold,
+			Color: col,
+			Align: text.Align,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return canvas, nil
+}
+
+// slotFilter adalah filter untuk slot: milik slot sendiri, atau milik layout.
+func slotFilter(layout domain.FrameLayout, slot domain.FrameSlot) *uuid.UUID {
+	if slot.FilterID != nil {
+		return slot.FilterID
+	}
+	return layout.FilterID
+}
+
+// filterIDs adalah daftar unik filter yang dirujuk layout.
+func filterIDs(layout domain.FrameLayout) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	ids := []uuid.UUID{}
+	for _, slot := range layout.Slots {
+		if id := slotFilter(layout, slot); id != nil && !seen[*id] {
+			seen[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+// slotBackground adalah latar chroma untuk slot: pilihan tamu, atau milik slot.
+func slotBackground(slot domain.FrameSlot, override *uuid.UUID) *uuid.UUID {
+	if slot.Chroma == nil {
+		return nil
+	}
+	if override != nil {
+		return override
+	}
+	return slot.Chroma.BackgroundID
+}
+
+// backgroundIDs adalah daftar unik latar yang dirujuk layout.
+func backgroundIDs(layout domain.FrameLayout, override *uuid.UUID) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	ids := []uuid.UUID{}
+	for _, slot := range layout.Slots {
+		if id := slotBackground(slot, override); id != nil && !seen[*id] {
+			seen[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+func expandText(text domain.FrameText, values renderValues) string {
+	dateFormat := text.DateFormat
+	if dateFormat == "" {
+		dateFormat = "02 Jan 2006"
+	}
+	return strings.NewReplacer(
+		"{date}", values.Time.Format(dateFormat),
+		"{time}", values.Time.Format("15:04"),
+		"{reference}", values.Reference,
+		"{tenant}", values.Tenant,
+		"{booth}", values.Booth,
+		"{frame}", values.Frame,
+	).Replace(text.Text)
+}
+
+// validateLayout memastikan layout bisa dirender sebelum disimpan, supaya
+// kesalahan template ketahuan saat upload, bukan saat tamu sedang menunggu.
+func validateLayout(layout domain.FrameLayout) error {
+	if layout.Width <= 0 || layout.Height <= 0 || layout.Width > maxCanvasSide || layout.Height > maxCanvasSide {
+		return fmt.Errorf("%w: ukuran kanvas harus 1-%d pixel", domain.ErrInvalidLayout, maxCanvasSide)
+	}
+	if len(layout.Slots) == 0 {
+		return fmt.Errorf("%w: minimal satu slot foto", domain.ErrInvalidLayout)
+	}
+	if layout.Background != "" {
+		if _, err := imaging.ParseHexColor(layout.Background); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidLayout, err)
+		}
+	}
+
+	for i, slot := range layout.Slots {
+		if slot.Width <= 0 || slot.Height <= 0 || slot.Width > maxCanvasSide || slot.Height > maxCanvasSide {
+			return fmt.Errorf("%w: ukuran slot %d tidak valid", domain.ErrInvalidLayout, i)
+		}
+		if slot.Fit != "" && slot.Fit != "cover" && slot.Fit != "contain" {
+			return fmt.Errorf("%w: fit slot %d harus cover atau contain", domain.ErrInvalidLayout, i)
+		}
+		if slot.Shot != nil && *slot.Shot < 0 {
+			return fmt.Errorf("%w: shot slot %d tidak boleh negatif", domain.ErrInvalidLayout, i)
+		}
+		if slot.Chroma != nil {
+			if _, err := bgUcase.NewChromaKey(*slot.Chroma); err != nil {
+				return fmt.Errorf("%w: slot %d: %v", domain.ErrInvalidLayout, i, err)
+			}
+		}
+	}
+
+	for i, text := range layout.Texts {
+		if text.FontSize <= 0 || text.FontSize > maxFontSize {
+			return fmt.Errorf("%w: font_size teks %d harus 1-%d", domain.ErrInvalidLayout, i, maxFontSize)
+		}
+		if text.Align != "" && text.Align != "left" && text.Align != "center" && text.Align != "right" {
+			return fmt.Errorf("%w: align teks %d tidak valid", domain.ErrInvalidLayout, i)
+		}
+		if text.Color != "" {
+			if _, err := imaging.ParseHexColor(text.Color); err != nil {
+				return fmt.Errorf("%w: %v", domain.ErrInvalidLayout, err)
+			}
+		}
+	}
+	return nil
+}
+
+// shotCount adalah jumlah foto minimal yang dibutuhkan layout.
+func shotCount(layout domain.FrameLayout) int {
+	n := 0
+	for i, slot := range layout.Slots {
+		index := i
+		if slot.Shot != nil {
+			index = *slot.Shot
+		}
+		if index+1 > n {
+			n = index + 1
+		}
+	}
+	return n
+}
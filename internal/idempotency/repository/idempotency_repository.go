@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository dipakai langsung oleh middleware, tanpa usecase.
+type IdempotencyRepository interface {
+	// Begin mencoba mengklaim key untuk booth. false berarti key sudah
+	// dipakai request lain yang belum kedaluwarsa.
+	Begin(record *domain.IdempotencyRecord) (bool, error)
+	Find(boothID uuid.UUID, key string) (*domain.IdempotencyRecord, error)
+	Complete(id uuid.UUID, statusCode int, contentType string, body []byte) error
+	Delete(id uuid.UUID) error
+	PurgeExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db}
+}
+
+// Begin menghapus record lama yang sudah kedaluwarsa untuk key yang sama,
+// lalu insert dengan ON CONFLICT DO NOTHING. Dua retry yang datang bersamaan
+// hanya satu yang berhasil mengklaim.
+func (r *idempotencyRepository) Begin(record *domain.IdempotencyRecord) (bool, error) {
+	err := r.db.Where("booth_id = ? AND key = ? AND expires_at <= ?", record.BoothID, record.Key, record.CreatedAt).
+		Delete(&domain.IdempotencyRecord{}).Error
+	if err != nil {
+		return false, err
+	}
+
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *idempotencyRepository) Find(boothID uuid.UUID, key string) (*domain.IdempotencyRecord, error) {
+	var record domain.IdempotencyRecord
+	err := r.db.Where("booth_id = ? AND key = ?", boothID, key).First(&record).Error
+	return &record, err
+}
+
+func (r *idempotencyRepository) Complete(id uuid.UUID, statusCode int, contentType string, body []byte) error {
+	return r.db.Model(&domain.IdempotencyRecord{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+}
+
+func (r *idempotencyRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&domain.IdempotencyRecord{}).Error
+}
+
+func (r *idempotencyRepository) PurgeExpired(now time.Time) (int64, error) {
+	res := r.db.Where("expires_at <= ?", now).Delete(&domain.IdempotencyRecord{})
+	return res.RowsAffected, res.Error
+}
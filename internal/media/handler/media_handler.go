@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/auth"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/storage"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MediaHandler struct {
+	usecase usecase.MediaUsecase
+}
+
+func NewMediaHandler(u usecase.MediaUsecase) *MediaHandler {
+	return &MediaHandler{u}
+}
+
+// SaveHistory godoc
+// @Summary      Simpan foto hasil sesi (base64, deprecated)
+// @Description  Jalur lama untuk booth versi lama. Gunakan POST /api/v1/photos/upload (multipart) untuk booth baru.
+// @Deprecated
+// @Tags         Media
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.SavePhotoRequest  true  "Data Foto"
+// @Success      201      {object}  response.Response
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      422      {object}  response.ErrorResponse
+// @Router       /api/v1/save-history [post]
+func (h *MediaHandler) SaveHistory(c *gin.Context) {
+	// Batasi ukuran body (Misal: max 10MB) agar server tidak hang
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
+
+	// Tandai sebagai deprecated supaya client tahu harus pindah ke multipart
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</api/v1/photos/upload>; rel="successor-version"`)
+
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	var req domain.SavePhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.Error("Gagal bind JSON history", "error", err)
+		response.Error(c, http.StatusBadRequest, "Payload terlalu besar atau format salah", err.Error())
+		return
+	}
+
+	photo, err := h.usecase.SavePhoto(c.Request.Context(), boothID, tenantID, req)
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	slog.Info("History saved successfully", "photo_id", photo.ID, "key", photo.StorageKey)
+	response.Success(c, http.StatusCreated, "Foto berhasil disimpan", gin.H{
+		"photo": photo,
+		"url":   photo.DownloadURL, // Link bertanda tangan untuk QR Code
+	})
+}
+
+// Upload godoc
+// @Summary      Upload foto (multipart)
+// @Description  File ditampung dulu di file sementara (dibatasi ukuran maksimum tenant), lalu divalidasi dan di-encode ulang: JPEG/WebP jadi JPEG, PNG tetap PNG, orientasi EXIF diterapkan lalu metadata dibuang. Hasil bersih itu yang disimpan ke storage. Kirim field transaction_id dan frame_name sebelum part file.
+// @Tags         Media
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        transaction_id  formData  string  false  "ID sesi"
+// @Param        frame_name      formData  string  false  "Nama frame"
+// @Param        file            formData  file    true   "File foto"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      413  {object}  response.ErrorResponse
+// @Failure      422  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/upload [post]
+func (h *MediaHandler) Upload(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Request harus multipart/form-data", err.Error())
+		return
+	}
+
+	var req domain.UploadPhotoRequest
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			response.Error(c, http.StatusBadRequest, "File foto wajib diisi", "part 'file' tidak ditemukan")
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Gagal membaca multipart", err.Error())
+			return
+		}
+
+		switch part.FormName() {
+		case "transaction_id":
+			raw, _ := io.ReadAll(io.LimitReader(part, 64))
+			if req.TransactionID, err = optionalUUID(strings.TrimSpace(string(raw))); err != nil {
+				response.Error(c, http.StatusBadRequest, "transaction_id tidak valid", err.Error())
+				return
+			}
+		case "frame_name":
+			raw, _ := io.ReadAll(io.LimitReader(part, 100))
+			req.FrameName = strings.TrimSpace(string(raw))
+		case "file":
+			// Part file langsung dialirkan, field setelahnya diabaikan
+			photo, err := h.usecase.UploadPhoto(c.Request.Context(), boothID, tenantID, req, part)
+			if err != nil {
+				writeUploadError(c, err)
+				return
+			}
+			response.Success(c, http.StatusCreated, "Foto berhasil diupload", photo)
+			return
+		}
+	}
+}
+
+// Download godoc
+// @Summary      Unduh foto lewat link bertanda tangan
+// @Description  Endpoint publik untuk tamu (dari QR). Link hanya berlaku untuk tenant pemilik foto dan sampai waktu exp.
+// @Tags         Media
+// @Produce      image/jpeg
+// @Param        id         path   string  true   "Photo ID"
+// @Param        tenant     query  string  true   "Tenant ID"
+// @Param        exp        query  int     true   "Unix timestamp kedaluwarsa"
+// @Param        sig        query  string  true   "Tanda tangan HMAC"
+// @Param        rendition  query  string  false  "thumbnail, web atau original"
+// @Param        format     query  string  false  "jpeg (default) atau webp"
+// @Success      200
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      410  {object}  response.ErrorResponse
+// @Router       /api/v1/files/photos/{id} [get]
+func (h *MediaHandler) Download(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+		return
+	}
+
+	content, err := h.usecase.OpenSignedPhoto(c.Request.Context(), id, c.Request.URL.Query())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrLinkExpired):
+			response.Error(c, http.StatusGone, "Link sudah kedaluwarsa", nil)
+		case errors.Is(err, auth.ErrSignatureInvalid):
+			response.Error(c, http.StatusForbidden, "Link tidak valid", nil)
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, storage.ErrNotFound):
+			response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+		case errors.Is(err, usecase.ErrRenditionNotFound):
+			response.Error(c, http.StatusNotFound, "Rendition belum tersedia", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Gagal membuka foto", err.Error())
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	if content.RedirectURL != "" {
+		c.Redirect(http.StatusFound, content.RedirectURL)
+		return
+	}
+	defer content.Body.Close()
+
+	c.DataFromReader(http.StatusOK, content.Size, content.ContentType, content.Body, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`inline; filename="%s"`, content.Filename),
+	})
+}
+
+// ListPhotos godoc
+// @Summary      Daftar foto milik tenant
+// @Tags         Media
+// @Security     BearerAuth
+// @Produce      json
+// @Param        booth_id        query  string  false  "Filter booth"
+// @Param        transaction_id  query  string  false  "Filter sesi"
+// @Param        kind            query  string  false  "raw, composite, animation"
+// @Param        limit           query  int     false  "Jumlah data"
+// @Param        offset          query  int     false  "Offset data"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/photos [get]
+func (h *MediaHandler) ListPhotos(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var filter domain.PhotoFilter
+	if filter.BoothID, err = optionalUUID(c.Query("booth_id")); err != nil {
+		response.Error(c, http.StatusBadRequest, "booth_id tidak valid", err.Error())
+		return
+	}
+	if filter.TransactionID, err = optionalUUID(c.Query("transaction_id")); err != nil {
+		response.Error(c, http.StatusBadRequest, "transaction_id tidak valid", err.Error())
+		return
+	}
+	filter.Kind = domain.PhotoKind(c.Query("kind"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+
+	photos, err := h.usecase.ListPhotos(tenantID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data foto", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil daftar foto", photos)
+}
+
+// ListSessionPhotos godoc
+// @Summary      Daftar foto dalam satu sesi
+// @Tags         Media
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/transactions/{id}/photos [get]
+func (h *MediaHandler) ListSessionPhotos(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "ID sesi tidak valid", err.Error())
+		return
+	}
+
+	photos, err := h.usecase.ListPhotos(tenantID, domain.PhotoFilter{TransactionID: &trxID})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data foto", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil foto sesi", photos)
+}
+
+// GetPhoto godoc
+// @Summary      Detail foto
+// @Tags         Media
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Photo ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/{id} [get]
+func (h *MediaHandler) GetPhoto(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "ID foto tidak valid", err.Error())
+		return
+	}
+
+	photo, err := h.usecase.GetPhoto(tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil foto", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil foto", photo)
+}
+
+// optionalUUID mem-parse query param opsional; string kosong berarti tanpa filter.
+func optionalUUID(raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// SessionGallery godoc
+// @Summary      Galeri publik satu sesi
+// @Description  Tujuan QR galeri sesi. Mengembalikan semua foto sesi beserta link download bertanda tangan.
+// @Tags         Media
+// @Produce      json
+// @Param        id      path   string  true  "Transaction ID"
+// @Param        tenant  query  string  true  "Tenant ID"
+// @Param        exp     query  int     true  "Unix timestamp kedaluwarsa"
+// @Param        sig     query  string  true  "Tanda tangan HMAC"
+// @Success      200  {object}  response.Response
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      410  {object}  response.ErrorResponse
+// @Router       /api/v1/files/sessions/{id} [get]
+func (h *MediaHandler) SessionGallery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Galeri tidak ditemukan", nil)
+		return
+	}
+
+	gallery, err := h.usecase.OpenSessionGallery(c.Request.Context(), id, c.Request.URL.Query())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrLinkExpired):
+			response.Error(c, http.StatusGone, "Link sudah kedaluwarsa", nil)
+		case errors.Is(err, auth.ErrSignatureInvalid):
+			response.Error(c, http.StatusForbidden, "Link tidak valid", nil)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.Error(c, http.StatusNotFound, "Galeri tidak ditemukan", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Gagal membuka galeri", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil membuka galeri", gallery)
+}
+
+// EventGallery godoc
+// @Summary      Galeri publik satu booth selama acara
+// @Tags         Media
+// @Produce      json
+// @Param        id      path   string  true  "Booth ID"
+// @Param        from    query  int     true  "Awal acara (unix)"
+// @Param        to      query  int     true  "Akhir acara (unix)"
+// @Param        tenant  query  string  true  "Tenant ID"
+// @Param        exp     query  int     true  "Unix timestamp kedaluwarsa"
+// @Param        sig     query  string  true  "Tanda tangan HMAC"
+// @Success      200  {object}  response.Response
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      410  {object}  response.ErrorResponse
+// @Router       /api/v1/files/booths/{id}/gallery [get]
+func (h *MediaHandler) EventGallery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Galeri tidak ditemukan", nil)
+		return
+	}
+
+	gallery, err := h.usecase.OpenEventGallery(c.Request.Context(), id, c.Request.URL.Query())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrLinkExpired):
+			response.Error(c, http.StatusGone, "Link sudah kedaluwarsa", nil)
+		case errors.Is(err, auth.ErrSignatureInvalid):
+			response.Error(c, http.StatusForbidden, "Link tidak valid", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Gagal membuka galeri", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil membuka galeri", gallery)
+}
+
+// writeUploadError memetakan error dari pipeline upload ke status HTTP.
+func writeUploadError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	var rejectErr *imaging.RejectError
+	switch {
+	case errors.As(err, &rejectErr):
+		response.Error(c, http.StatusUnprocessableEntity, "Gambar ditolak", rejectErr)
+	case errors.Is(err, usecase.ErrFileTooLarge), errors.As(err, &maxBytesErr):
+		response.Error(c, http.StatusRequestEntityTooLarge, "Ukuran file terlalu besar", err.Error())
+	case errors.Is(err, usecase.ErrTransactionNotFound):
+		response.Error(c, http.StatusNotFound, "Sesi tidak ditemukan", err.Error())
+	default:
+		slog.Error("Gagal menyimpan foto", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal menyimpan foto", err.Error())
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const tusVersion = "1.0.0"
+
+type UploadHandler struct {
+	usecase usecase.ResumableUploadUsecase
+}
+
+func NewUploadHandler(u usecase.ResumableUploadUsecase) *UploadHandler {
+	return &UploadHandler{u}
+}
+
+// Create godoc
+// @Summary      Mulai upload bertahap
+// @Description  Protokol gaya tus: create, PATCH potongan dengan Upload-Offset, HEAD untuk cek offset, lalu finalize.
+// @Tags         Uploads
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.CreateUploadRequest  true  "Info file"
+// @Success      201      {object}  response.Response
+// @Failure      413      {object}  response.ErrorResponse
+// @Router       /api/v1/uploads [post]
+func (h *UploadHandler) Create(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	var req domain.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	upload, err := h.usecase.Create(boothID, tenantID, req)
+	if err != nil {
+		writeResumableError(c, err)
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Location", "/api/v1/uploads/"+upload.ID.String())
+	setOffsetHeaders(c, upload)
+	response.Success(c, http.StatusCreated, "Upload dimulai", upload)
+}
+
+// Head godoc
+// @Summary      Cek offset upload bertahap
+// @Tags         Uploads
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Upload ID"
+// @Success      200
+// @Failure      404
+// @Router       /api/v1/uploads/{id} [head]
+func (h *UploadHandler) Head(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	upload, err := h.usecase.Status(boothID, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUploadNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Cache-Control", "no-store")
+	setOffsetHeaders(c, upload)
+	if upload.Status != domain.UploadInProgress {
+		c.Status(http.StatusGone)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Patch godoc
+// @Summary      Kirim potongan upload
+// @Description  Body adalah byte mentah (Content-Type application/offset+octet-stream) mulai dari Upload-Offset.
+// @Tags         Uploads
+// @Security     BearerAuth
+// @Accept       application/offset+octet-stream
+// @Param        id             path    string  true  "Upload ID"
+// @Param        Upload-Offset  header  int     true  "Offset awal potongan"
+// @Success      204
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /api/v1/uploads/{id} [patch]
+func (h *UploadHandler) Patch(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Upload tidak ditemukan", nil)
+		return
+	}
+
+	if c.ContentType() != "application/offset+octet-stream" {
+		response.Error(c, http.StatusUnsupportedMediaType, "Content-Type harus application/offset+octet-stream", nil)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.Error(c, http.StatusBadRequest, "Header Upload-Offset tidak valid", nil)
+		return
+	}
+
+	upload, err := h.usecase.WriteChunk(c.Request.Context(), boothID, id, offset, c.Request.Body)
+	c.Header("Tus-Resumable", tusVersion)
+	if upload != nil {
+		setOffsetHeaders(c, upload)
+	}
+	if err != nil {
+		writeResumableError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Finalize godoc
+// @Summary      Selesaikan upload bertahap
+// @Description  Menggabungkan semua potongan menjadi Photo. Aman dipanggil ulang; kalau finalize lain masih berjalan balas 409, coba lagi sebentar lagi.
+// @Tags         Uploads
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Upload ID"
+// @Success      201  {object}  response.Response
+// @Failure      409  {object}  response.ErrorResponse
+// @Failure      422  {object}  response.ErrorResponse
+// @Router       /api/v1/uploads/{id}/finalize [post]
+func (h *UploadHandler) Finalize(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Upload tidak ditemukan", nil)
+		return
+	}
+
+	photo, err := h.usecase.Finalize(c.Request.Context(), boothID, id)
+	if err != nil {
+		writeResumableError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Foto berhasil diupload", photo)
+}
+
+func setOffsetHeaders(c *gin.Context, upload *domain.Upload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+}
+
+func writeResumableError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUploadNotFound):
+		response.Error(c, http.StatusNotFound, "Upload tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrUploadClosed):
+		response.Error(c, http.StatusGone, "Upload sudah ditutup", err.Error())
+	case errors.Is(err, usecase.ErrOffsetMismatch), errors.Is(err, usecase.ErrUploadIncomplete):
+		response.Error(c, http.StatusConflict, "Offset upload tidak cocok", err.Error())
+	case errors.Is(err, usecase.ErrUploadFinalizing):
+		response.Error(c, http.StatusConflict, "Upload sedang diproses", err.Error())
+	default:
+		writeUploadError(c, err)
+	}
+}
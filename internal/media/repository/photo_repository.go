@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PhotoRepository interface {
+	Create(photo *domain.Photo) error
+	FindByID(tenantID, id uuid.UUID) (*domain.Photo, error)
+	FindByTenant(tenantID uuid.UUID, filter domain.PhotoFilter) ([]domain.Photo, error)
+	UpdateRenditionStatus(id uuid.UUID, status domain.RenditionStatus) error
+	FindPendingRenditions(createdBefore time.Time, limit int) ([]domain.Photo, error)
+	Scan(tenantID *uuid.UUID, after uuid.UUID, limit int) ([]domain.Photo, error)
+}
+
+type photoRepository struct {
+	db *gorm.DB
+}
+
+func NewPhotoRepository(db *gorm.DB) PhotoRepository {
+	return &photoRepository{db}
+}
+
+func (r *photoRepository) Create(photo *domain.Photo) error {
+	return r.db.Create(photo).Error
+}
+
+func (r *photoRepository) FindByID(tenantID, id uuid.UUID) (*domain.Photo, error) {
+	var photo domain.Photo
+	err := r.db.Preload("Renditions").Where("tenant_id = ? AND id = ?", tenantID, id).First(&photo).Error
+	return &photo, err
+}
+
+func (r *photoRepository) FindByTenant(tenantID uuid.UUID, filter domain.PhotoFilter) ([]domain.Photo, error) {
+	photos := []domain.Photo{}
+	query := r.db.Preload("Renditions").Where("tenant_id = ?", tenantID)
+
+	if filter.BoothID != nil {
+		query = query.Where("booth_id = ?", *filter.BoothID)
+	}
+	if filter.TransactionID != nil {
+		query = query.Where("transaction_id = ?", *filter.TransactionID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	err := query.Order("created_at DESC").Find(&photos).Error
+	return photos, err
+}
+
+func (r *photoRepository) UpdateRenditionStatus(id uuid.UUID, status domain.RenditionStatus) error {
+	return r.db.Model(&domain.Photo{}).Where("id = ?", id).Update("rendition_status", status).Error
+}
+
+// FindPendingRenditions mencari foto yang belum diproses worker, misalnya
+// karena antrean penuh atau server restart sebelum sempat diproses.
+func (r *photoRepository) FindPendingRenditions(createdBefore time.Time, limit int) ([]domain.Photo, error) {
+	photos := []domain.Photo{}
+	err := r.db.Where("rendition_status = ? AND created_at < ?", domain.RenditionPending, createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&photos).Error
+	return photos, err
+}
+
+// Scan membaca foto berurutan per ID (keyset pagination) untuk proses batch.
+// tenantID nil berarti semua tenant.
+func (r *photoRepository) Scan(tenantID *uuid.UUID, after uuid.UUID, limit int) ([]domain.Photo, error) {
+	photos := []domain.Photo{}
+	query := r.db.Preload("Renditions").Where("id > ?", after)
+	if tenantID != nil {
+		query = query.Where("tenant_id = ?", *tenantID)
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&photos).Error
+	return photos, err
+}
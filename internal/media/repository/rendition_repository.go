@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RenditionRepository interface {
+	Replace(photoID uuid.UUID, renditions []domain.PhotoRendition) error
+}
+
+type renditionRepository struct {
+	db *gorm.DB
+}
+
+func NewRenditionRepository(db *gorm.DB) RenditionRepository {
+	return &renditionRepository{db}
+}
+
+// Replace mengganti semua rendition satu foto sekaligus menandai fotonya
+// ready, dalam satu transaksi supaya galeri tidak pernah melihat set setengah jadi.
+func (r *renditionRepository) Replace(photoID uuid.UUID, renditions []domain.PhotoRendition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("photo_id = ?", photoID).Delete(&domain.PhotoRendition{}).Error; err != nil {
+			return err
+		}
+		if len(renditions) > 0 {
+			if err := tx.Create(&renditions).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.Photo{}).Where("id = ?", photoID).
+			Update("rendition_status", domain.RenditionReady).Error
+	})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UploadRepository interface {
+	Create(upload *domain.Upload) error
+	FindByID(boothID, id uuid.UUID) (*domain.Upload, error)
+	AdvanceOffset(id uuid.UUID, from, n int64, key string, expiresAt time.Time) (bool, error)
+	FindChunks(id uuid.UUID) ([]domain.UploadChunk, error)
+	DeleteChunks(id uuid.UUID) error
+	ClaimFinalize(id uuid.UUID, now, leaseUntil time.Time) (bool, error)
+	ReleaseFinalize(id uuid.UUID, expiresAt time.Time) error
+	MarkCompleted(id, photoID uuid.UUID) (bool, error)
+	FindExpired(now time.Time, limit int) ([]domain.Upload, error)
+	MarkExpired(id uuid.UUID, now time.Time) (bool, error)
+}
+
+type uploadRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadRepository(db *gorm.DB) UploadRepository {
+	return &uploadRepository{db}
+}
+
+func (r *uploadRepository) Create(upload *domain.Upload) error {
+	return r.db.Create(upload).Error
+}
+
+func (r *uploadRepository) FindByID(boothID, id uuid.UUID) (*domain.Upload, error) {
+	var upload domain.Upload
+	err := r.db.Where("booth_id = ? AND id = ?", boothID, id).First(&upload).Error
+	return &upload, err
+}
+
+// AdvanceOffset menggeser offset hanya kalau offset di DB masih sama dengan
+// from, lalu mencatat key potongan pemenangnya di transaksi yang sama.
+// Mengembalikan false kalau ada PATCH lain yang lebih dulu menulis.
+func (r *uploadRepository) AdvanceOffset(id uuid.UUID, from, n int64, key string, expiresAt time.Time) (bool, error) {
+	advanced := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Upload{}).
+			Where("id = ? AND \"offset\" = ? AND status = ?", id, from, domain.UploadInProgress).
+			Updates(map[string]interface{}{
+				"offset":     from + n,
+				"expires_at": expiresAt,
+			})
+		if res.Error != nil || res.RowsAffected != 1 {
+			return res.Error
+		}
+		advanced = true
+		return tx.Create(&domain.UploadChunk{
+			UploadID:  id,
+			Offset:    from,
+			Size:      n,
+			Key:       key,
+			CreatedAt: time.Now(),
+		}).Error
+	})
+	return advanced && err == nil, err
+}
+
+// FindChunks mengambil potongan yang sudah dicatat, urut sesuai offset.
+func (r *uploadRepository) FindChunks(id uuid.UUID) ([]domain.UploadChunk, error) {
+	var chunks []domain.UploadChunk
+	err := r.db.Where("upload_id = ?", id).Order("\"offset\" ASC").Find(&chunks).Error
+	return chunks, err
+}
+
+func (r *uploadRepository) DeleteChunks(id uuid.UUID) error {
+	return r.db.Where("upload_id = ?", id).Delete(&domain.UploadChunk{}).Error
+}
+
+// ClaimFinalize mengunci upload yang sudah lengkap untuk satu proses
+// finalize, sekaligus memperpanjang expires_at sebagai lease. Klaim yang
+// lease-nya habis (proses sebelumnya mati) boleh diambil alih.
+func (r *uploadRepository) ClaimFinalize(id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	res := r.db.Model(&domain.Upload{}).
+		Where("id = ? AND \"offset\" = length", id).
+		Where("status = ? OR (status = ? AND expires_at < ?)", domain.UploadInProgress, domain.UploadFinalizing, now).
+		Updates(map[string]interface{}{
+			"status":     domain.UploadFinalizing,
+			"expires_at": leaseUntil,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+// ReleaseFinalize mengembalikan upload ke status uploading kalau finalize
+// gagal, supaya booth bisa mencoba lagi.
+func (r *uploadRepository) ReleaseFinalize(id uuid.UUID, expiresAt time.Time) error {
+	return r.db.Model(&domain.Upload{}).
+		Where("id = ? AND status = ?", id, domain.UploadFinalizing).
+		Updates(map[string]interface{}{
+			"status":     domain.UploadInProgress,
+			"expires_at": expiresAt,
+		}).Error
+}
+
+// MarkCompleted hanya berlaku untuk upload yang sedang di-finalize.
+func (r *uploadRepository) MarkCompleted(id, photoID uuid.UUID) (bool, error) {
+	res := r.db.Model(&domain.Upload{}).
+		Where("id = ? AND status = ?", id, domain.UploadFinalizing).
+		Updates(map[string]interface{}{
+			"status":   domain.UploadCompleted,
+			"photo_id": photoID,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+// FindExpired hanya mengambil upload yang masih uploading. Upload yang
+// sedang di-finalize tidak ikut, potongannya masih dibaca.
+func (r *uploadRepository) FindExpired(now time.Time, limit int) ([]domain.Upload, error) {
+	var uploads []domain.Upload
+	err := r.db.Where("status = ? AND expires_at < ?", domain.UploadInProgress, now).
+		Limit(limit).Find(&uploads).Error
+	return uploads, err
+}
+
+// MarkExpired mengecek ulang status dan expires_at, jadi upload yang baru
+// saja di-klaim finalize atau menerima potongan baru tidak ikut ditutup.
+func (r *uploadRepository) MarkExpired(id uuid.UUID, now time.Time) (bool, error) {
+	res := r.db.Model(&domain.Upload{}).
+		Where("id = ? AND status = ? AND expires_at < ?", id, domain.UploadInProgress, now).
+		Update("status", domain.UploadExpired)
+	return res.RowsAffected == 1, res.Error
+}
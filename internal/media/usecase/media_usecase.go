@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/media/repository"
+	"photobooth-core/internal/platform/auth"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+	trRepo "photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFileTooLarge        = errors.New("ukuran file melebihi batas upload tenant")
+	ErrTransactionNotFound = errors.New("sesi transaksi tidak ditemukan untuk booth ini")
+	ErrRenditionNotFound   = errors.New("rendition foto tidak tersedia")
+)
+
+var frameNameSanitizer = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// redirectTTL adalah umur presigned URL storage setelah link publik lolos verifikasi.
+const redirectTTL = 5 * time.Minute
+
+type MediaUsecase interface {
+	SavePhoto(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SavePhotoRequest) (*domain.Photo, error)
+	UploadPhoto(ctx context.Context, boothID, tenantID uuid.UUID, req domain.UploadPhotoRequest, file io.Reader) (*domain.Photo, error)
+	SaveImage(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SaveImageRequest, img image.Image) (*domain.Photo, error)
+	SaveEncoded(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SaveImageRequest, file *imaging.Sanitized) (*domain.Photo, error)
+	UploadLimit(tenantID uuid.UUID) (int64, error)
+	GetPhoto(tenantID, id uuid.UUID) (*domain.Photo, error)
+	ListPhotos(tenantID uuid.UUID, filter domain.PhotoFilter) ([]domain.Photo, error)
+	OpenSignedPhoto(ctx context.Context, id uuid.UUID, query url.Values) (*PhotoContent, error)
+	OpenSessionGallery(ctx context.Context, trxID uuid.UUID, query url.Values) (*domain.SessionGallery, error)
+	OpenEventGallery(ctx context.Context, boothID uuid.UUID, query url.Values) (*domain.EventGallery, error)
+	SessionURL(tenantID, trxID uuid.UUID) (string, error)
+	EventGalleryURL(tenantID, boothID uuid.UUID, startsAt, endsAt time.Time) (string, error)
+}
+
+// Config mengatur link download publik untuk tamu dan batas upload default.
+type Config struct {
+	BaseURL        string
+	Retention      time.Duration
+	MaxUploadBytes int64 // dipakai kalau tenant tidak punya batas sendiri
+	ImageLimits    imaging.Limits
+	JPEGQuality    int
+}
+
+// PhotoContent adalah hasil verifikasi link publik: entah stream file
+// langsung, atau URL storage yang bisa dipakai untuk redirect.
+type PhotoContent struct {
+	Photo       *domain.Photo
+	Body        io.ReadCloser
+	RedirectURL string
+	ContentType string
+	Size        int64
+	Filename    string
+}
+
+type mediaUsecase struct {
+	repo       repository.PhotoRepository
+	trxRepo    trRepo.TransactionRepository
+	tenantRepo domain.TenantRepository
+	storage    storage.Storage
+	signer     *auth.URLSigner
+	renditions RenditionUsecase
+	cfg        Config
+}
+
+func NewMediaUsecase(repo repository.PhotoRepository, trxRepo trRepo.TransactionRepository, tenantRepo domain.TenantRepository,
+	store storage.Storage, signer *auth.URLSigner, renditions RenditionUsecase, cfg Config) MediaUsecase {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &mediaUsecase{repo, trxRepo, tenantRepo, store, signer, renditions, cfg}
+}
+
+// SavePhoto adalah jalur lama (data URL base64 di JSON). Masih didukung
+// untuk booth versi lama, tapi booth baru sebaiknya pakai UploadPhoto.
+func (u *mediaUsecase) SavePhoto(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SavePhotoRequest) (*domain.Photo, error) {
+	// Decode data URL: "data:image/jpeg;base64,...."
+	idx := strings.Index(req.Image, ",")
+	if idx == -1 {
+		return nil, &imaging.RejectError{Reason: imaging.ReasonInvalidEncoding, Detail: "data URL tidak valid"}
+	}
+	data, err := base64.StdEncoding.DecodeString(req.Image[idx+1:])
+	if err != nil {
+		return nil, &imaging.RejectError{Reason: imaging.ReasonInvalidEncoding, Detail: "base64 tidak valid"}
+	}
+
+	return u.UploadPhoto(ctx, boothID, tenantID, domain.UploadPhotoRequest{
+		TransactionID: req.TransactionID,
+		FrameName:     req.FrameName,
+	}, bytes.NewReader(data))
+}
+
+// UploadPhoto menampung file ke temp file (dengan batas ukuran tenant),
+// memvalidasi dan meng-encode ulang gambarnya, lalu menyimpan hasil yang
+// sudah bersih. Yang disimpan tidak pernah byte mentah dari client.
+func (u *mediaUsecase) UploadPhoto(ctx context.Context, boothID, tenantID uuid.UUID, req domain.UploadPhotoRequest, file io.Reader) (*domain.Photo, error) {
+	// Foto boleh nggak nempel ke sesi (client lama), tapi kalau dikirim
+	// harus benar-benar sesi milik booth ini.
+	if req.TransactionID != nil {
+		trx, err := u.trxRepo.FindByID(tenantID, *req.TransactionID)
+		if err != nil || trx.BoothID != boothID {
+			return nil, ErrTransactionNotFound
+		}
+	}
+
+	limit, err := u.UploadLimit(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "photo-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat file sementara: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	if _, err := io.Copy(spool, &limitedReader{r: file, limit: limit}); err != nil {
+		if errors.Is(err, ErrFileTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gagal menerima file: %w", err)
+	}
+
+	clean, err := imaging.Sanitize(spool, u.cfg.ImageLimits, u.cfg.JPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.store(ctx, boothID, tenantID, domain.SaveImageRequest{
+		TransactionID: req.TransactionID,
+		FrameName:     req.FrameName,
+		Kind:          domain.PhotoRaw,
+		PhotoID:       req.PhotoID,
+	}, clean)
+}
+
+// SaveImage menyimpan gambar hasil olahan server (mis. composite frame)
+// sebagai Photo baru. Sesi tidak divalidasi ulang, itu tugas pemanggil.
+func (u *mediaUsecase) SaveImage(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SaveImageRequest, img image.Image) (*domain.Photo, error) {
+	var buf bytes.Buffer
+	contentType, err := imaging.Encode(&buf, img, imaging.FormatJPEG, u.cfg.JPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.store(ctx, boothID, tenantID, req, &imaging.Sanitized{
+		Data:        buf.Bytes(),
+		Image:       img,
+		ContentType: contentType,
+		Ext:         ".jpg",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	})
+}
+
+// SaveEncoded menyimpan file yang sudah di-encode server (mis. GIF animasi)
+// apa adanya sebagai Photo baru.
+func (u *mediaUsecase) SaveEncoded(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SaveImageRequest, file *imaging.Sanitized) (*domain.Photo, error) {
+	return u.store(ctx, boothID, tenantID, req, file)
+}
+
+// store menulis gambar bersih ke storage lalu mencatatnya sebagai Photo.
+func (u *mediaUsecase) store(ctx context.Context, boothID, tenantID uuid.UUID, req domain.SaveImageRequest, clean *imaging.Sanitized) (*domain.Photo, error) {
+	photoID := uuid.New()
+	if req.PhotoID != nil {
+		photoID = *req.PhotoID
+	}
+	cleanFrameName := strings.ToLower(frameNameSanitizer.ReplaceAllString(req.FrameName, "_"))
+	key := fmt.Sprintf("history/%s/%s_%s%s", tenantID, photoID, cleanFrameName, clean.Ext)
+
+	if err := u.storage.Put(ctx, key, bytes.NewReader(clean.Data), int64(len(clean.Data)), clean.ContentType); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan file: %w", err)
+	}
+
+	checksum := sha256.Sum256(clean.Data)
+	photo := &domain.Photo{
+		ID:             photoID,
+		TenantID:       tenantID,
+		BoothID:        boothID,
+		TransactionID:  req.TransactionID,
+		FrameName:      req.FrameName,
+		Kind:           req.Kind,
+		FrameVersionID: req.FrameVersionID,
+		SourcePhotoID:  req.SourcePhotoID,
+		FilterID:       req.FilterID,
+		StorageKey:     key,
+		ContentType:    clean.ContentType,
+		Size:           int64(len(clean.Data)),
+		Width:          clean.Width,
+		Height:         clean.Height,
+		Checksum:       hex.EncodeToString(checksum[:]),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+
+		RenditionStatus: domain.RenditionPending,
+	}
+
+	if err := u.repo.Create(photo); err != nil {
+		// Jangan tinggalkan file yatim kalau insert gagal. Kalau ID-nya tetap,
+		// key yang sama bisa sudah dipakai Photo yang berhasil masuk duluan.
+		if req.PhotoID == nil {
+			_ = u.storage.Delete(ctx, key)
+		}
+		return nil, err
+	}
+
+	// Thumbnail dan ukuran web dibuat di belakang, booth nggak perlu menunggu
+	u.renditions.Enqueue(photo)
+
+	u.attachLinks(photo)
+	return photo, nil
+}
+
+// UploadLimit mengambil batas ukuran upload tenant, atau default dari config.
+func (u *mediaUsecase) UploadLimit(tenantID uuid.UUID) (int64, error) {
+	tenant, err := u.tenantRepo.FindByID(tenantID)
+	if err != nil {
+		return 0, err
+	}
+	if tenant.MaxUploadBytes > 0 {
+		return tenant.MaxUploadBytes, nil
+	}
+	return u.cfg.MaxUploadBytes, nil
+}
+
+func (u *mediaUsecase) GetPhoto(tenantID, id uuid.UUID) (*domain.Photo, error) {
+	photo, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	u.attachLinks(photo)
+	return photo, nil
+}
+
+func (u *mediaUsecase) ListPhotos(tenantID uuid.UUID, filter domain.PhotoFilter) ([]domain.Photo, error) {
+	photos, err := u.repo.FindByTenant(tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		u.attachLinks(&photos[i])
+	}
+	return photos, nil
+}
+
+// OpenSignedPhoto memverifikasi link publik lalu membuka file fotonya, atau
+// salah satu rendition-nya kalau query "rendition" (dan "format") diisi.
+// Kalau driver storage bisa membuat presigned URL, handler cukup redirect.
+func (u *mediaUsecase) OpenSignedPhoto(ctx context.Context, id uuid.UUID, query url.Values) (*PhotoContent, error) {
+	tenantID, err := u.signer.Verify(photoResource(id), query)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &PhotoContent{
+		Photo:       photo,
+		ContentType: photo.ContentType,
+		Size:        photo.Size,
+		Filename:    photo.ID.String() + extensionFor(photo.ContentType),
+	}
+	key := photo.StorageKey
+
+	if kind := query.Get("rendition"); kind != "" {
+		rendition := findRendition(photo, domain.RenditionKind(kind), query.Get("format"))
+		if rendition == nil {
+			return nil, ErrRenditionNotFound
+		}
+		key = rendition.StorageKey
+		content.ContentType = rendition.ContentType
+		content.Size = rendition.Size
+		content.Filename = fmt.Sprintf("%s_%s%s", photo.ID, rendition.Kind, extensionFor(rendition.ContentType))
+	}
+
+	if signed, err := u.storage.SignedURL(ctx, key, redirectTTL); err == nil {
+		content.RedirectURL = signed
+		return content, nil
+	} else if !errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return nil, err
+	}
+
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	content.Body = body
+	return content, nil
+}
+
+// findRendition mencari rendition sesuai jenis dan format (default jpeg).
+func findRendition(photo *domain.Photo, kind domain.RenditionKind, format string) *domain.PhotoRendition {
+	if format == "" {
+		format = imaging.FormatJPEG
+	}
+	for i := range photo.Renditions {
+		if photo.Renditions[i].Kind == kind && photo.Renditions[i].Format == format {
+			return &photo.Renditions[i]
+		}
+	}
+	return nil
+}
+
+// attachLinks mengisi link download bertanda tangan. Masa berlakunya dihitung
+// dari waktu foto dibuat, jadi QR di booth mati setelah jendela retensi lewat.
+func (u *mediaUsecase) attachLinks(photo *domain.Photo) {
+	expiresAt := photo.CreatedAt.Add(u.cfg.Retention)
+	resource := photoResource(photo.ID)
+	base := u.cfg.BaseURL + "/api/v1/files/" + resource + "?"
+	photo.DownloadURL = base + u.signer.Query(resource, photo.TenantID, expiresAt).Encode()
+
+	// Tanda tangan cuma mengikat foto, jadi semua rendition-nya ikut valid
+	for i := range photo.Renditions {
+		q := u.signer.Query(resource, photo.TenantID, expiresAt)
+		q.Set("rendition", string(photo.Renditions[i].Kind))
+		q.Set("format", photo.Renditions[i].Format)
+		photo.Renditions[i].URL = base + q.Encode()
+	}
+}
+
+func photoResource(id uuid.UUID) string {
+	return "photos/" + id.String()
+}
+
+// limitedReader berhenti dengan ErrFileTooLarge begitu jumlah byte yang
+// dibaca melewati limit.
+type limitedReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	n, err := lr.r.Read(p)
+	lr.n += int64(n)
+	if lr.limit > 0 && lr.n > lr.limit {
+		return n, ErrFileTooLarge
+	}
+	return n, err
+}
+
+func extensionFor(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".bin"
+	}
+}
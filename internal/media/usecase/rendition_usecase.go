@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+	"time"
+
+	_ "image/gif" // animasi: thumbnail diambil dari frame pertama
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/media/repository"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/storage"
+
+	"github.com/google/uuid"
+)
+
+// renditionSpecs diurutkan dari yang terbesar, karena tiap ukuran
+// di-resize dari hasil sebelumnya supaya lebih cepat.
+var renditionSpecs = []struct {
+	Kind    domain.RenditionKind
+	MaxSide int
+}{
+	{domain.RenditionOriginal, 0},
+	{domain.RenditionWeb, 1600},
+	{domain.RenditionThumbnail, 400},
+}
+
+// pendingGrace memberi waktu worker memproses antrean sebelum sweeper
+// menganggap foto pending tercecer.
+const pendingGrace = 10 * time.Minute
+
+// RenditionConfig mengatur worker pembuat rendition.
+type RenditionConfig struct {
+	WebP        bool // WebP lossless ikut dibuat di samping JPEG
+	JPEGQuality int
+	Workers     int
+	QueueSize   int
+}
+
+type RenditionUsecase interface {
+	Enqueue(photo *domain.Photo)
+	Start(ctx context.Context)
+	Generate(ctx context.Context, photo *domain.Photo) error
+	RequeuePending(ctx context.Context) (int, error)
+	Regenerate(ctx context.Context, tenantID *uuid.UUID, onlyMissing bool) (int, error)
+}
+
+type renditionUsecase struct {
+	photoRepo repository.PhotoRepository
+	repo      repository.RenditionRepository
+	storage   storage.Storage
+	cfg       RenditionConfig
+	jobs      chan *domain.Photo
+}
+
+func NewRenditionUsecase(photoRepo repository.PhotoRepository, repo repository.RenditionRepository, store storage.Storage, cfg RenditionConfig) RenditionUsecase {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.JPEGQuality <= 0 {
+		cfg.JPEGQuality = 85
+	}
+	return &renditionUsecase{photoRepo, repo, store, cfg, make(chan *domain.Photo, cfg.QueueSize)}
+}
+
+// Enqueue tidak pernah memblokir request upload. Kalau antrean penuh, foto
+// tetap pending dan akan diambil lagi oleh RequeuePending.
+func (u *renditionUsecase) Enqueue(photo *domain.Photo) {
+	select {
+	case u.jobs <- photo:
+	default:
+		slog.Warn("Antrean rendition penuh, foto menunggu sweeper", "photo_id", photo.ID)
+	}
+}
+
+// Start menjalankan worker sampai ctx selesai.
+func (u *renditionUsecase) Start(ctx context.Context) {
+	for i := 0; i < u.cfg.Workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case photo := <-u.jobs:
+					if err := u.Generate(ctx, photo); err != nil {
+						slog.Error("Gagal membuat rendition", "photo_id", photo.ID, "error", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// Generate membuat semua rendition satu foto secara sinkron. Key storage-nya
+// deterministik, jadi aman dipanggil ulang untuk foto yang sama.
+func (u *renditionUsecase) Generate(ctx context.Context, photo *domain.Photo) error {
+	renditions, err := u.render(ctx, photo)
+	if err != nil {
+		_ = u.photoRepo.UpdateRenditionStatus(photo.ID, domain.RenditionFailed)
+		return err
+	}
+	return u.repo.Replace(photo.ID, renditions)
+}
+
+func (u *renditionUsecase) render(ctx context.Context, photo *domain.Photo) ([]domain.PhotoRendition, error) {
+	body, _, err := u.storage.Get(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(body)
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode foto: %w", err)
+	}
+
+	formats := []string{imaging.FormatJPEG}
+	if u.cfg.WebP {
+		formats = append(formats, imaging.FormatWebP)
+	}
+
+	renditions := []domain.PhotoRendition{}
+	for _, spec := range renditionSpecs {
+		img = imaging.Fit(img, spec.MaxSide)
+		width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+		for _, format := range formats {
+			rendition := domain.PhotoRendition{
+				ID:        uuid.New(),
+				PhotoID:   photo.ID,
+				Kind:      spec.Kind,
+				Format:    format,
+				Width:     width,
+				Height:    height,
+				CreatedAt: time.Now(),
+			}
+
+			// Original JPEG nggak perlu disimpan dua kali
+			if spec.Kind == domain.RenditionOriginal && format == imaging.FormatJPEG && photo.ContentType == "image/jpeg" {
+				rendition.StorageKey = photo.StorageKey
+				rendition.ContentType = photo.ContentType
+				rendition.Size = photo.Size
+				renditions = append(renditions, rendition)
+				continue
+			}
+
+			var buf bytes.Buffer
+			contentType, err := imaging.Encode(&buf, img, format, u.cfg.JPEGQuality)
+			if err != nil {
+				return nil, err
+			}
+			rendition.StorageKey = renditionKey(photo, spec.Kind, format)
+			rendition.ContentType = contentType
+			rendition.Size = int64(buf.Len())
+
+			if err := u.storage.Put(ctx, rendition.StorageKey, &buf, rendition.Size, contentType); err != nil {
+				return nil, fmt.Errorf("gagal menyimpan rendition %s: %w", spec.Kind, err)
+			}
+			renditions = append(renditions, rendition)
+		}
+	}
+
+	return renditions, nil
+}
+
+// RequeuePending memasukkan lagi foto pending yang tercecer ke antrean.
+func (u *renditionUsecase) RequeuePending(ctx context.Context) (int, error) {
+	photos, err := u.photoRepo.FindPendingRenditions(time.Now().Add(-pendingGrace), u.cfg.QueueSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range photos {
+		u.Enqueue(&photos[i])
+	}
+	return len(photos), nil
+}
+
+// Regenerate memproses ulang rendition secara sinkron, dipakai command admin.
+// onlyMissing melewati foto yang set rendition-nya sudah lengkap.
+func (u *renditionUsecase) Regenerate(ctx context.Context, tenantID *uuid.UUID, onlyMissing bool) (int, error) {
+	const batchSize = 100
+	expected := len(renditionSpecs)
+	if u.cfg.WebP {
+		expected *= 2
+	}
+
+	processed := 0
+	after := uuid.Nil
+	for {
+		photos, err := u.photoRepo.Scan(tenantID, after, batchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(photos) == 0 {
+			return processed, nil
+		}
+
+		for i := range photos {
+			photo := &photos[i]
+			after = photo.ID
+			if ctx.Err() != nil {
+				return processed, ctx.Err()
+			}
+			if onlyMissing && photo.RenditionStatus == domain.RenditionReady && len(photo.Renditions) >= expected {
+				continue
+			}
+			if err := u.Generate(ctx, photo); err != nil {
+				slog.Error("Gagal membuat rendition", "photo_id", photo.ID, "error", err)
+				continue
+			}
+			processed++
+		}
+	}
+}
+
+func renditionKey(photo *domain.Photo, kind domain.RenditionKind, format string) string {
+	ext := ".jpg"
+	if format == imaging.FormatWebP {
+		ext = ".webp"
+	}
+	return fmt.Sprintf("renditions/%s/%s/%s%s", photo.TenantID, photo.ID, kind, ext)
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/auth"
+
+	"github.com/google/uuid"
+)
+
+// OpenSessionGallery memverifikasi link galeri sesi lalu mengembalikan
+// semua foto di sesi tersebut beserta link download masing-masing.
+func (u *mediaUsecase) OpenSessionGallery(ctx context.Context, trxID uuid.UUID, query url.Values) (*domain.SessionGallery, error) {
+	tenantID, err := u.signer.Verify(sessionResource(trxID), query)
+	if err != nil {
+		return nil, err
+	}
+
+	trx, err := u.trxRepo.FindByID(tenantID, trxID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := u.ListPhotos(tenantID, domain.PhotoFilter{TransactionID: &trx.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SessionGallery{
+		TransactionID: trx.ID,
+		ReferenceNo:   trx.ReferenceNo,
+		ExpiresAt:     trx.CreatedAt.Add(u.cfg.Retention),
+		Photos:        photos,
+	}, nil
+}
+
+// OpenEventGallery memverifikasi link galeri acara (satu booth dalam rentang
+// waktu tertentu). Rentang waktu ikut ditandatangani supaya tidak bisa diperlebar.
+func (u *mediaUsecase) OpenEventGallery(ctx context.Context, boothID uuid.UUID, query url.Values) (*domain.EventGallery, error) {
+	from, errFrom := strconv.ParseInt(query.Get("from"), 10, 64)
+	to, errTo := strconv.ParseInt(query.Get("to"), 10, 64)
+	if errFrom != nil || errTo != nil {
+		return nil, auth.ErrSignatureInvalid
+	}
+	startsAt, endsAt := time.Unix(from, 0), time.Unix(to, 0)
+
+	tenantID, err := u.signer.Verify(eventResource(boothID, startsAt, endsAt), query)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := u.ListPhotos(tenantID, domain.PhotoFilter{BoothID: &boothID, From: &startsAt, To: &endsAt})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.EventGallery{
+		BoothID:   boothID,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		ExpiresAt: endsAt.Add(u.cfg.Retention),
+		Photos:    photos,
+	}, nil
+}
+
+// SessionURL adalah link galeri publik satu sesi, berlaku selama jendela retensi.
+func (u *mediaUsecase) SessionURL(tenantID, trxID uuid.UUID) (string, error) {
+	trx, err := u.trxRepo.FindByID(tenantID, trxID)
+	if err != nil {
+		return "", err
+	}
+	resource := sessionResource(trx.ID)
+	return u.cfg.BaseURL + "/api/v1/files/" + resource + "?" +
+		u.signer.Query(resource, trx.TenantID, trx.CreatedAt.Add(u.cfg.Retention)).Encode(), nil
+}
+
+// EventGalleryURL adalah link galeri publik satu booth selama acara berlangsung.
+// Link tetap hidup sampai jendela retensi lewat setelah acara selesai.
+func (u *mediaUsecase) EventGalleryURL(tenantID, boothID uuid.UUID, startsAt, endsAt time.Time) (string, error) {
+	if !endsAt.After(startsAt) {
+		return "", errors.New("rentang waktu acara tidak valid")
+	}
+	q := u.signer.Query(eventResource(boothID, startsAt, endsAt), tenantID, endsAt.Add(u.cfg.Retention))
+	q.Set("from", strconv.FormatInt(startsAt.Unix(), 10))
+	q.Set("to", strconv.FormatInt(endsAt.Unix(), 10))
+	return u.cfg.BaseURL + "/api/v1/files/booths/" + boothID.String() + "/gallery?" + q.Encode(), nil
+}
+
+func sessionResource(id uuid.UUID) string {
+	return "sessions/" + id.String()
+}
+
+func eventResource(boothID uuid.UUID, startsAt, endsAt time.Time) string {
+	return fmt.Sprintf("booths/%s/gallery/%d-%d", boothID, startsAt.Unix(), endsAt.Unix())
+}
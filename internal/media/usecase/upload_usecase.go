@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/media/repository"
+	"photobooth-core/internal/platform/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUploadNotFound   = errors.New("upload tidak ditemukan")
+	ErrUploadClosed     = errors.New("upload sudah selesai atau kedaluwarsa")
+	ErrOffsetMismatch   = errors.New("Upload-Offset tidak sama dengan offset di server")
+	ErrUploadIncomplete = errors.New("upload belum lengkap")
+	ErrUploadFinalizing = errors.New("upload sedang di-finalize")
+)
+
+// finalizeLease adalah batas waktu satu proses finalize. Lewat dari ini
+// klaimnya dianggap mati dan boleh diambil alih finalize berikutnya.
+const finalizeLease = 10 * time.Minute
+
+// ResumableUploadUsecase menangani upload bertahap untuk booth dengan
+// koneksi yang putus-putus: create, kirim potongan per offset, cek status, finalize.
+type ResumableUploadUsecase interface {
+	Create(boothID, tenantID uuid.UUID, req domain.CreateUploadRequest) (*domain.Upload, error)
+	Status(boothID, id uuid.UUID) (*domain.Upload, error)
+	WriteChunk(ctx context.Context, boothID, id uuid.UUID, offset int64, chunk io.Reader) (*domain.Upload, error)
+	Finalize(ctx context.Context, boothID, id uuid.UUID) (*domain.Photo, error)
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type resumableUploadUsecase struct {
+	repo    repository.UploadRepository
+	media   MediaUsecase
+	storage storage.Storage
+	ttl     time.Duration
+}
+
+// NewResumableUploadUsecase membuat usecase upload bertahap. ttl adalah
+// berapa lama upload yang diam dibiarkan sebelum dibersihkan.
+func NewResumableUploadUsecase(repo repository.UploadRepository, media MediaUsecase, store storage.Storage, ttl time.Duration) ResumableUploadUsecase {
+	return &resumableUploadUsecase{repo, media, store, ttl}
+}
+
+func (u *resumableUploadUsecase) Create(boothID, tenantID uuid.UUID, req domain.CreateUploadRequest) (*domain.Upload, error) {
+	limit, err := u.media.UploadLimit(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Length > limit {
+		return nil, ErrFileTooLarge
+	}
+
+	upload := &domain.Upload{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		BoothID:       boothID,
+		TransactionID: req.TransactionID,
+		FrameName:     req.FrameName,
+		Length:        req.Length,
+		Status:        domain.UploadInProgress,
+		ExpiresAt:     time.Now().Add(u.ttl),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := u.repo.Create(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+func (u *resumableUploadUsecase) Status(boothID, id uuid.UUID) (*domain.Upload, error) {
+	upload, err := u.repo.FindByID(boothID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	return upload, nil
+}
+
+// WriteChunk menyimpan satu potongan sebagai object sendiri. Potongan yang
+// putus di tengah jalan tidak tersimpan sama sekali, jadi booth cukup HEAD
+// lalu kirim ulang dari offset terakhir.
+func (u *resumableUploadUsecase) WriteChunk(ctx context.Context, boothID, id uuid.UUID, offset int64, chunk io.Reader) (*domain.Upload, error) {
+	upload, err := u.Status(boothID, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != domain.UploadInProgress {
+		return nil, ErrUploadClosed
+	}
+	if offset != upload.Offset {
+		return upload, ErrOffsetMismatch
+	}
+
+	// Key unik per PATCH, jadi PATCH yang balapan di offset sama tidak saling
+	// menimpa atau menghapus potongan milik pemenangnya
+	key := chunkKey(upload.ID, offset, uuid.New())
+	counter := &countingReader{r: chunk, limit: upload.Length - offset}
+	if err := u.storage.Put(ctx, key, counter, -1, "application/octet-stream"); err != nil {
+		_ = u.storage.Delete(ctx, key)
+		return nil, err
+	}
+	if counter.n == 0 {
+		_ = u.storage.Delete(ctx, key)
+		return upload, nil
+	}
+
+	expiresAt := time.Now().Add(u.ttl)
+	ok, err := u.repo.AdvanceOffset(upload.ID, offset, counter.n, key, expiresAt)
+	if err != nil || !ok {
+		// PATCH lain menang duluan, potongan ini dibuang
+		_ = u.storage.Delete(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return upload, ErrOffsetMismatch
+	}
+
+	upload.Offset += counter.n
+	upload.ExpiresAt = expiresAt
+	return upload, nil
+}
+
+// Finalize menggabungkan semua potongan lewat pipeline upload biasa, jadi
+// hasilnya Photo yang sama persis dengan upload multipart. Upload di-klaim
+// dulu (uploading -> finalizing) supaya hanya satu proses yang membuat Photo
+// dan potongannya tidak dibersihkan di tengah jalan.
+func (u *resumableUploadUsecase) Finalize(ctx context.Context, boothID, id uuid.UUID) (*domain.Photo, error) {
+	upload, err := u.Status(boothID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch upload.Status {
+	case domain.UploadCompleted:
+		// Finalize ulang (misal respon pertama hilang) cukup kembalikan foto yang sama
+		return u.media.GetPhoto(upload.TenantID, *upload.PhotoID)
+	case domain.UploadExpired:
+		return nil, ErrUploadClosed
+	}
+	if upload.Offset != upload.Length {
+		return nil, ErrUploadIncomplete
+	}
+
+	now := time.Now()
+	claimed, err := u.repo.ClaimFinalize(upload.ID, now, now.Add(finalizeLease))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		current, err := u.Status(boothID, id)
+		if err != nil {
+			return nil, err
+		}
+		switch current.Status {
+		case domain.UploadCompleted:
+			return u.media.GetPhoto(current.TenantID, *current.PhotoID)
+		case domain.UploadFinalizing:
+			return nil, ErrUploadFinalizing
+		}
+		return nil, ErrUploadClosed
+	}
+
+	// ID foto sama dengan ID upload. Kalau proses sebelumnya mati setelah
+	// Photo tersimpan, cukup tandai selesai tanpa membuat Photo kedua.
+	if photo, err := u.media.GetPhoto(upload.TenantID, upload.ID); err == nil {
+		return u.complete(ctx, upload.ID, photo)
+	}
+
+	chunks, err := u.chunks(ctx, upload.ID)
+	if err != nil {
+		u.release(upload.ID)
+		return nil, err
+	}
+	var next int64
+	for _, chunk := range chunks {
+		if chunk.Offset != next {
+			u.release(upload.ID)
+			return nil, fmt.Errorf("%w: potongan di offset %d hilang", ErrUploadIncomplete, next)
+		}
+		next += chunk.Size
+	}
+
+	photo, err := u.media.UploadPhoto(ctx, upload.BoothID, upload.TenantID, domain.UploadPhotoRequest{
+		TransactionID: upload.TransactionID,
+		FrameName:     upload.FrameName,
+		PhotoID:       &upload.ID,
+	}, &chunkReader{ctx: ctx, storage: u.storage, chunks: chunks})
+	if err != nil {
+		u.release(upload.ID)
+		return nil, err
+	}
+
+	return u.complete(ctx, upload.ID, photo)
+}
+
+func (u *resumableUploadUsecase) complete(ctx context.Context, id uuid.UUID, photo *domain.Photo) (*domain.Photo, error) {
+	ok, err := u.repo.MarkCompleted(id, photo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Lease habis dan diambil alih; Photo-nya tetap sama karena ID-nya tetap
+		slog.Warn("Upload sudah tidak dalam status finalizing", "upload_id", id)
+	}
+	u.deleteChunks(ctx, id)
+	return photo, nil
+}
+
+func (u *resumableUploadUsecase) release(id uuid.UUID) {
+	if err := u.repo.ReleaseFinalize(id, time.Now().Add(u.ttl)); err != nil {
+		slog.Error("Gagal melepas klaim finalize", "upload_id", id, "error", err)
+	}
+}
+
+// PurgeExpired membersihkan upload yang ditinggal melewati TTL beserta
+// potongannya. Status ditutup dulu secara kondisional, baru potongan dihapus,
+// jadi upload yang sempat di-klaim finalize tidak ikut terhapus.
+func (u *resumableUploadUsecase) PurgeExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	uploads, err := u.repo.FindExpired(now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, upload := range uploads {
+		ok, err := u.repo.MarkExpired(upload.ID, now)
+		if err != nil {
+			return purged, err
+		}
+		if !ok {
+			continue
+		}
+		u.deleteChunks(ctx, upload.ID)
+		purged++
+	}
+	return purged, nil
+}
+
+// chunks mengambil potongan yang tercatat. Upload yang dimulai sebelum
+// potongan dicatat di DB dibaca langsung dari storage (key lama tanpa id
+// percobaan, satu object per offset).
+func (u *resumableUploadUsecase) chunks(ctx context.Context, id uuid.UUID) ([]domain.UploadChunk, error) {
+	chunks, err := u.repo.FindChunks(id)
+	if err != nil || len(chunks) > 0 {
+		return chunks, err
+	}
+
+	objects, err := u.storage.List(ctx, chunkPrefix(id))
+	if err != nil {
+		return nil, err
+	}
+	var offset int64
+	for _, obj := range objects {
+		chunks = append(chunks, domain.UploadChunk{UploadID: id, Offset: offset, Size: obj.Size, Key: obj.Key})
+		offset += obj.Size
+	}
+	return chunks, nil
+}
+
+// deleteChunks menghapus semua object di prefix upload, termasuk potongan
+// dari PATCH yang kalah balapan dan gagal dibuang saat itu.
+func (u *resumableUploadUsecase) deleteChunks(ctx context.Context, id uuid.UUID) {
+	if err := u.repo.DeleteChunks(id); err != nil {
+		slog.Error("Gagal menghapus catatan potongan upload", "upload_id", id, "error", err)
+	}
+	chunks, err := u.storage.List(ctx, chunkPrefix(id))
+	if err != nil {
+		slog.Error("Gagal membaca potongan upload", "upload_id", id, "error", err)
+		return
+	}
+	for _, chunk := range chunks {
+		if err := u.storage.Delete(ctx, chunk.Key); err != nil {
+			slog.Error("Gagal menghapus potongan upload", "key", chunk.Key, "error", err)
+		}
+	}
+}
+
+func chunkPrefix(id uuid.UUID) string {
+	return "uploads/" + id.String() + "/"
+}
+
+// chunkKey memakai offset ber-padding nol supaya mudah dibaca di storage,
+// ditambah id percobaan supaya setiap PATCH punya object sendiri.
+func chunkKey(id uuid.UUID, offset int64, attempt uuid.UUID) string {
+	return fmt.Sprintf("%s%020d-%s", chunkPrefix(id), offset, attempt)
+}
+
+// countingReader menghitung byte yang dibaca dan menolak data melewati sisa panjang upload.
+type countingReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	if cr.n > cr.limit {
+		return n, ErrFileTooLarge
+	}
+	return n, err
+}
+
+// chunkReader membaca potongan satu per satu dari storage, jadi hanya satu
+// object yang terbuka pada satu waktu.
+type chunkReader struct {
+	ctx     context.Context
+	storage storage.Storage
+	chunks  []domain.UploadChunk
+	current io.ReadCloser
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if cr.current == nil {
+			if len(cr.chunks) == 0 {
+				return 0, io.EOF
+			}
+			body, _, err := cr.storage.Get(cr.ctx, cr.chunks[0].Key)
+			if err != nil {
+				return 0, err
+			}
+			cr.current = body
+			cr.chunks = cr.chunks[1:]
+		}
+
+		n, err := cr.current.Read(p)
+		if err == io.EOF {
+			cr.current.Close()
+			cr.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit membatasi ukuran body semua request. Route di skipRoutes
+// (pakai pola route gin, contoh "/api/v1/photos/upload") mengatur batasnya sendiri,
+// misalnya upload streaming yang limitnya per tenant.
+func BodyLimit(limit int64, skipRoutes ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipRoutes))
+	for _, route := range skipRoutes {
+		skip[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if !skip[c.FullPath()] {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"photobooth-core/internal/domain"
+	iRepo "photobooth-core/internal/idempotency/repository"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	IdempotencyHeader = "Idempotency-Key"
+
+	maxIdempotencyKey = 255
+	// Respons lebih besar dari ini tidak disimpan; retry-nya dieksekusi ulang.
+	maxReplayBody = 1 << 20
+)
+
+// Idempotency membuat request mutasi dari mesin booth aman di-retry. Request
+// dengan header Idempotency-Key yang sama dan body yang sama mendapat respons
+// pertama tanpa dieksekusi ulang; body berbeda ditolak 409. Respons 5xx tidak
+// disimpan supaya booth bisa mencoba lagi. Route di skipRoutes (pola route
+// gin) dilewati, misalnya upload streaming yang body-nya tidak bisa dibaca
+// ke memori.
+func Idempotency(repo iRepo.IdempotencyRepository, ttl time.Duration, skipRoutes ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipRoutes))
+	for _, route := range skipRoutes {
+		skip[route] = true
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		boothID, err := utils.GetBoothID(c)
+		if key == "" || err != nil || !mutating(c.Request.Method) || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			response.Error(c, http.StatusBadRequest, "Idempotency-Key maksimal 255 karakter", nil)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, http.StatusRequestEntityTooLarge, "Body request terlalu besar", err.Error())
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := &domain.IdempotencyRecord{
+			ID:          uuid.New(),
+			BoothID:     boothID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.Path, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		claimed, err := repo.Begin(record)
+		if err != nil {
+			slog.Error("Gagal menyimpan idempotency key", "booth_id", boothID, "error", err)
+			response.Error(c, http.StatusInternalServerError, "Gagal memproses Idempotency-Key", err.Error())
+			c.Abort()
+			return
+		}
+		if !claimed {
+			replay(c, repo, record)
+			return
+		}
+
+		// Handler gagal (5xx, panic, respons terlalu besar): lepas key supaya
+		// retry berikutnya dieksekusi normal
+		completed := false
+		defer func() {
+			if !completed {
+				if err := repo.Delete(record.ID); err != nil {
+					slog.Error("Gagal melepas idempotency key", "booth_id", boothID, "error", err)
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || recorder.overflow {
+			return
+		}
+		if err := repo.Complete(record.ID, status, c.Writer.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			slog.Error("Gagal menyimpan respons idempotency", "booth_id", boothID, "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replay mengirim ulang respons pertama untuk key yang sudah dipakai.
+func replay(c *gin.Context, repo iRepo.IdempotencyRepository, record *domain.IdempotencyRecord) {
+	defer c.Abort()
+
+	existing, err := repo.Find(record.BoothID, record.Key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Request pertama baru saja gagal dan melepas key-nya
+		response.Error(c, http.StatusConflict, "Request dengan Idempotency-Key ini baru saja gagal, silakan coba lagi", nil)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses Idempotency-Key", err.Error())
+		return
+	}
+
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		response.Error(c, http.StatusConflict, "Idempotency-Key sudah dipakai untuk request yang berbeda", nil)
+	case existing.Pending():
+		response.Error(c, http.StatusConflict, "Request dengan Idempotency-Key ini masih diproses", nil)
+	default:
+		status := existing.StatusCode
+		if status == http.StatusCreated {
+			// Tidak ada yang dibuat di request ini, resource-nya sudah ada
+			status = http.StatusOK
+		}
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(status, existing.ContentType, existing.ResponseBody)
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, method)
+	h.Write([]byte{0})
+	io.WriteString(h, path)
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder menyalin body respons sambil tetap menulis ke client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) capture(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > maxReplayBody {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
@@ -17,15 +17,18 @@ func CORS() gin.HandlerFunc {
     return cors.New(cors.Config{
         // AllowAllOrigins:  true,
         AllowOrigins:     []string{"*"}, // sementara biar aman
-        AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+        AllowMethods:     []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
         AllowHeaders: []string{
             "Origin", 
             "Content-Type", 
             "Authorization", 
             "Content-Length",
             "X-Tunnel-Skip-Anti-Phishing-Scan", // Tambahkan header ini untuk Dev Tunnels
+            "Upload-Offset",
+            "Upload-Length",
+            "Tus-Resumable",
         },
-        ExposeHeaders:    []string{"Content-Length"},
+        ExposeHeaders:    []string{"Content-Length", "Location", "Upload-Offset", "Upload-Length", "Tus-Resumable"},
         AllowCredentials: true,
         MaxAge: 12 * time.Hour,
     })
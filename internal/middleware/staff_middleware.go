@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StaffOnly kebalikan DeviceOnly: token mesin booth tidak boleh lewat.
+func StaffOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		if role == "device" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Hanya user dashboard yang diizinkan mengakses resource ini"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"photobooth-core/internal/notification/usecase"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	usecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(u usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{u}
+}
+
+// List godoc
+// @Summary      Notifikasi tenant
+// @Description  Misalnya kertas printer booth yang hampir habis. Terbaru dulu.
+// @Tags         Notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Param        unread  query  bool  false  "Hanya yang belum dibaca"
+// @Param        limit   query  int   false  "Jumlah data"
+// @Param        offset  query  int   false  "Offset data"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	notifications, err := h.usecase.List(tenantID, unreadOnly, limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil notifikasi", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil notifikasi", notifications)
+}
+
+// MarkRead godoc
+// @Summary      Tandai notifikasi sudah dibaca
+// @Tags         Notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Notifikasi tidak ditemukan", nil)
+		return
+	}
+
+	if err := h.usecase.MarkRead(tenantID, id); err != nil {
+		response.Error(c, http.StatusNotFound, "Notifikasi tidak ditemukan", nil)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Notifikasi ditandai sudah dibaca", nil)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *domain.Notification) error
+	FindByTenant(tenantID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, error)
+	MarkRead(tenantID, id uuid.UUID, at time.Time) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+func (r *notificationRepository) Create(notification *domain.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepository) FindByTenant(tenantID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	err := query.Order("created_at DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) MarkRead(tenantID, id uuid.UUID, at time.Time) error {
+	res := r.db.Model(&domain.Notification{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
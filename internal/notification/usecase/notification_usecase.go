@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/notification/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotificationNotFound = errors.New("notifikasi tidak ditemukan")
+
+type NotificationUsecase interface {
+	Notify(tenantID uuid.UUID, boothID *uuid.UUID, kind domain.NotificationKind, title, message string) error
+	List(tenantID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, error)
+	MarkRead(tenantID, id uuid.UUID) error
+}
+
+type notificationUsecase struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationUsecase(repo repository.NotificationRepository) NotificationUsecase {
+	return &notificationUsecase{repo}
+}
+
+// Notify menyimpan notifikasi untuk dashboard tenant dan ikut menulis log,
+// supaya tetap kelihatan di server walau belum ada yang membuka dashboard.
+func (u *notificationUsecase) Notify(tenantID uuid.UUID, boothID *uuid.UUID, kind domain.NotificationKind, title, message string) error {
+	slog.Warn(title, "tenant_id", tenantID, "booth_id", boothID, "kind", kind, "message", message)
+	return u.repo.Create(&domain.Notification{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		BoothID:   boothID,
+		Kind:      kind,
+		Title:     title,
+		Message:   message,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (u *notificationUsecase) List(tenantID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, error) {
+	return u.repo.FindByTenant(tenantID, unreadOnly, limit, offset)
+}
+
+func (u *notificationUsecase) MarkRead(tenantID, id uuid.UUID) error {
+	if err := u.repo.MarkRead(tenantID, id, time.Now()); err != nil {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
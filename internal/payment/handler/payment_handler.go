@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/payment/usecase"
+	"photobooth-core/internal/platform/payment"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	trUcase "photobooth-core/internal/transaction/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxWebhookBody membatasi body webhook yang dibaca; payload gateway kecil.
+const maxWebhookBody = 64 << 10
+
+type PaymentHandler struct {
+	usecase usecase.PaymentUsecase
+}
+
+func NewPaymentHandler(u usecase.PaymentUsecase) *PaymentHandler {
+	return &PaymentHandler{u}
+}
+
+// CreateCharge godoc
+// @Summary      Buat tagihan QRIS untuk sesi
+// @Description  Dipanggil booth setelah sesi dibuat. Sesi pindah ke awaiting_payment; tampilkan qr_string sebagai QR di layar. Tagihan yang masih berlaku dikembalikan lagi kalau endpoint ini dipanggil ulang. Sesi pindah ke paid otomatis setelah gateway mengirim webhook lunas.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      201  {object}  response.Response{data=domain.Payment}
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Failure      502  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/payment [post]
+func (h *PaymentHandler) CreateCharge(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	boothID, _ := utils.GetBoothID(c)
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+		return
+	}
+
+	p, err := h.usecase.CreateCharge(c.Request.Context(), tenantID, boothID, trxID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Tagihan QRIS siap dibayar", p)
+}
+
+// GetPayment godoc
+// @Summary      Tagihan terbaru sesi
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  response.Response{data=domain.Payment}
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/payment [get]
+func (h *PaymentHandler) GetPayment(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+		return
+	}
+
+	var actor domain.TransitionActor
+	if boothID, err := utils.GetBoothID(c); err == nil {
+		actor.BoothID = &boothID
+	} else if userID, err := utils.GetUserID(c); err == nil {
+		actor.UserID = &userID
+	}
+
+	p, err := h.usecase.GetPayment(tenantID, trxID, actor)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil tagihan", p)
+}
+
+// Webhook godoc
+// @Summary      Webhook payment gateway QRIS
+// @Description  Dipanggil gateway, bukan client. Ditandatangani HMAC-SHA256 lewat header X-Timestamp dan X-Signature. Selain 2xx, gateway akan mengirim ulang.
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /api/v1/payments/qris/webhook [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Body webhook tidak bisa dibaca", err.Error())
+		return
+	}
+
+	if err := h.usecase.HandleWebhook(c.Request.Context(), c.Request.Header, body); err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook diterima", nil)
+}
+
+func writePaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSignature):
+		response.Error(c, http.StatusUnauthorized, "Tanda tangan webhook tidak valid", nil)
+	case errors.Is(err, usecase.ErrInvalidPayload):
+		response.Error(c, http.StatusBadRequest, "Isi webhook tidak valid", err.Error())
+	case errors.Is(err, trUcase.ErrTransactionNotFound):
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrPaymentNotFound):
+		response.Error(c, http.StatusNotFound, "Tagihan tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrNotPayable), errors.Is(err, usecase.ErrFreeSession):
+		response.Error(c, http.StatusConflict, "Sesi tidak bisa ditagih", err.Error())
+	case errors.Is(err, usecase.ErrAmountMismatch):
+		response.Error(c, http.StatusUnprocessableEntity, "Nominal pembayaran tidak cocok", err.Error())
+	case errors.Is(err, usecase.ErrPaymentDisabled):
+		response.Error(c, http.StatusServiceUnavailable, "Pembayaran QRIS tidak aktif", err.Error())
+	case errors.Is(err, payment.ErrProvider):
+		slog.Error("Payment gateway menolak permintaan", "error", err)
+		response.Error(c, http.StatusBadGateway, "Payment gateway bermasalah", err.Error())
+	default:
+		slog.Error("Gagal memproses pembayaran", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses pembayaran", err.Error())
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"photobooth-core/internal/payment/usecase"
+	"photobooth-core/internal/platform/payment"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newWebhookRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	provider, err := payment.NewQRISProvider("http://gateway.local", "", "whsec-test")
+	if err != nil {
+		t.Fatalf("NewQRISProvider: %v", err)
+	}
+	// Kasus di sini ditolak sebelum menyentuh repository atau sesi
+	h := NewPaymentHandler(usecase.NewPaymentUsecase(nil, nil, provider, 0))
+
+	r := gin.New()
+	r.POST("/webhook", h.Webhook)
+	return r
+}
+
+func TestWebhookStatusCodes(t *testing.T) {
+	r := newWebhookRouter(t)
+	ts := time.Now().Unix()
+	signed := func(secret, body string) http.Header {
+		h := http.Header{}
+		h.Set(payment.HeaderTimestamp, strconv.FormatInt(ts, 10))
+		h.Set(payment.HeaderSignature, payment.SignWebhook(secret, ts, []byte(body)))
+		return h
+	}
+
+	cases := []struct {
+		name   string
+		body   io.Reader
+		header http.Header
+		want   int
+	}{
+		{"body putus", iotest.ErrReader(errors.New("koneksi putus")), http.Header{}, http.StatusBadRequest},
+		{"bukan json", strings.NewReader(`{"id":`), signed("whsec-test", `{"id":`), http.StatusBadRequest},
+		{"hmac salah", strings.NewReader(`{"id":"qris_1"}`), signed("secret-lain", `{"id":"qris_1"}`), http.StatusUnauthorized},
+		{"tanpa tanda tangan", strings.NewReader(`{"id":"qris_1"}`), http.Header{}, http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", c.body)
+		req.Header = c.header
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != c.want {
+			t.Errorf("%s: HTTP %d, mau %d: %s", c.name, w.Code, c.want, w.Body.String())
+		}
+	}
+}
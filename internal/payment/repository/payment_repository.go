@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(payment *domain.Payment) error
+	FindByID(id uuid.UUID) (*domain.Payment, error)
+	FindLatest(tenantID, transactionID uuid.UUID) (*domain.Payment, error)
+	FindPending(tenantID, transactionID uuid.UUID, now time.Time) (*domain.Payment, error)
+	Settle(id uuid.UUID, status domain.PaymentStatus) (bool, error)
+	SettlePaid(id uuid.UUID, paidAt time.Time) (bool, error)
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db}
+}
+
+func (r *paymentRepository) Create(payment *domain.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) FindByID(id uuid.UUID) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Where("id = ?", id).First(&payment).Error
+	return &payment, err
+}
+
+func (r *paymentRepository) FindLatest(tenantID, transactionID uuid.UUID) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Where("tenant_id = ? AND transaction_id = ?", tenantID, transactionID).
+		Order("created_at DESC").
+		First(&payment).Error
+	return &payment, err
+}
+
+// FindPending mengambil tagihan yang QR-nya masih bisa dibayar.
+func (r *paymentRepository) FindPending(tenantID, transactionID uuid.UUID, now time.Time) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Where("tenant_id = ? AND transaction_id = ? AND status = ? AND expires_at > ?",
+		tenantID, transactionID, domain.PaymentPending, now).
+		Order("created_at DESC").
+		First(&payment).Error
+	return &payment, err
+}
+
+// Settle mengakhiri tagihan yang masih pending sebagai expired atau failed.
+// Webhook yang dikirim ulang gateway mendapat false dan tidak mengubah apa-apa.
+func (r *paymentRepository) Settle(id uuid.UUID, status domain.PaymentStatus) (bool, error) {
+	res := r.db.Model(&domain.Payment{}).
+		Where("id = ? AND status = ?", id, domain.PaymentPending).
+		Update("status", status)
+	return res.RowsAffected == 1, res.Error
+}
+
+// SettlePaid menandai tagihan lunas dari status apa pun selain paid. Gateway
+// bisa melaporkan pembayaran setelah tagihan sudah dianggap expired di sini.
+func (r *paymentRepository) SettlePaid(id uuid.UUID, paidAt time.Time) (bool, error) {
+	res := r.db.Model(&domain.Payment{}).
+		Where("id = ? AND status <> ?", id, domain.PaymentPaid).
+		Updates(map[string]interface{}{
+			"status":  domain.PaymentPaid,
+			"paid_at": paidAt,
+		})
+	return res.RowsAffected == 1, res.Error
+}
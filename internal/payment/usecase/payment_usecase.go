@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/payment/repository"
+	"photobooth-core/internal/platform/payment"
+	trUcase "photobooth-core/internal/transaction/usecase"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentDisabled  = errors.New("payment gateway belum diatur di server")
+	ErrPaymentNotFound  = errors.New("tagihan tidak ditemukan")
+	ErrNotPayable       = errors.New("sesi tidak sedang menunggu pembayaran")
+	ErrFreeSession      = errors.New("sesi gratis tidak perlu dibayar")
+	ErrAmountMismatch   = errors.New("nominal webhook tidak sama dengan tagihan")
+	ErrInvalidSignature = payment.ErrInvalidSignature
+	ErrInvalidPayload   = payment.ErrInvalidPayload
+)
+
+type PaymentUsecase interface {
+	CreateCharge(ctx context.Context, tenantID, boothID, trxID uuid.UUID) (*domain.Payment, error)
+	GetPayment(tenantID, trxID uuid.UUID, actor domain.TransitionActor) (*domain.Payment, error)
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) error
+}
+
+type paymentUsecase struct {
+	repo         repository.PaymentRepository
+	transactions trUcase.TransactionUsecase
+	provider     payment.Provider
+	qrTTL        time.Duration
+}
+
+// NewPaymentUsecase menerima provider nil kalau gateway belum diatur; semua
+// operasi pembayaran lalu ditolak dengan ErrPaymentDisabled.
+func NewPaymentUsecase(repo repository.PaymentRepository, transactions trUcase.TransactionUsecase, provider payment.Provider, qrTTL time.Duration) PaymentUsecase {
+	return &paymentUsecase{repo, transactions, provider, qrTTL}
+}
+
+// CreateCharge membuat tagihan QRIS untuk sesi dan memindahkan sesi ke
+// awaiting_payment. Tagihan yang masih berlaku dipakai ulang, jadi booth
+// aman memanggil ini lagi setelah reconnect.
+func (u *paymentUsecase) CreateCharge(ctx context.Context, tenantID, boothID, trxID uuid.UUID) (*domain.Payment, error) {
+	if u.provider == nil {
+		return nil, ErrPaymentDisabled
+	}
+
+	actor := domain.TransitionActor{BoothID: &boothID}
+	trx, err := u.transactions.GetSession(tenantID, trxID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if trx.Status != domain.TransCreated && trx.Status != domain.TransAwaitingPayment {
+		return nil, ErrNotPayable
+	}
+	if trx.Amount <= 0 {
+		return nil, ErrFreeSession
+	}
+
+	now := time.Now()
+	if existing, err := u.repo.FindPending(tenantID, trx.ID, now); err == nil {
+		return existing, nil
+	}
+
+	p := &domain.Payment{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		TransactionID: trx.ID,
+		Provider:      u.provider.Name(),
+		Amount:        trx.Amount,
+		Status:        domain.PaymentPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	charge, err := u.provider.CreateCharge(ctx, payment.ChargeRequest{
+		OrderID:   p.ID.String(),
+		Amount:    rupiah(trx.Amount),
+		ExpiresIn: u.qrTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.ProviderRef = charge.ID
+	p.QRString = charge.QRString
+	p.ExpiresAt = charge.ExpiresAt
+
+	if err := u.repo.Create(p); err != nil {
+		return nil, err
+	}
+
+	if trx.Status == domain.TransCreated {
+		_, err := u.transactions.Transition(tenantID, trx.ID, domain.TransitionRequest{
+			Status: domain.TransAwaitingPayment,
+			Reason: "tagihan " + u.provider.Name() + " " + charge.ID,
+		}, actor)
+		if err != nil && !errors.Is(err, trUcase.ErrInvalidTransition) {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// GetPayment mengambil tagihan terbaru sesi, dipakai booth untuk polling
+// kalau layar perlu tahu status tanpa menunggu perubahan status sesi.
+func (u *paymentUsecase) GetPayment(tenantID, trxID uuid.UUID, actor domain.TransitionActor) (*domain.Payment, error) {
+	trx, err := u.transactions.GetSession(tenantID, trxID, actor)
+	if err != nil {
+		return nil, err
+	}
+	p, err := u.repo.FindLatest(tenantID, trx.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	return p, err
+}
+
+// HandleWebhook memverifikasi webhook gateway lalu memperbarui tagihan.
+// Tagihan lunas memindahkan sesi ke paid. Webhook yang dikirim ulang aman
+// diproses lagi.
+func (u *paymentUsecase) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	if u.provider == nil {
+		return ErrPaymentDisabled
+	}
+
+	notif, err := u.provider.VerifyWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(notif.OrderID)
+	if err != nil {
+		return ErrPaymentNotFound
+	}
+	p, err := u.repo.FindByID(id)
+	if err != nil || p.ProviderRef != notif.ChargeID {
+		return ErrPaymentNotFound
+	}
+
+	switch notif.Status {
+	case payment.ChargePaid:
+		if notif.Amount != rupiah(p.Amount) {
+			slog.Error("Nominal webhook tidak cocok", "payment_id", p.ID, "expected", rupiah(p.Amount), "got", notif.Amount)
+			return ErrAmountMismatch
+		}
+		paidAt := time.Now()
+		if notif.PaidAt != nil {
+			paidAt = *notif.PaidAt
+		}
+		// Tagihan yang sudah expired/failed juga dipindah: uangnya benar-benar
+		// masuk, jadi tercatat paid. Setelah ini tagihan pasti paid.
+		if _, err := u.repo.SettlePaid(p.ID, paidAt); err != nil {
+			return err
+		}
+		// Tetap dicoba walau tagihan sudah paid: webhook sebelumnya mungkin
+		// gagal di langkah ini
+		return u.markPaid(p)
+
+	case payment.ChargeExpired, payment.ChargeFailed:
+		_, err := u.repo.Settle(p.ID, domain.PaymentStatus(notif.Status))
+		return err
+
+	default:
+		return nil
+	}
+}
+
+func (u *paymentUsecase) markPaid(p *domain.Payment) error {
+	trx, err := u.transactions.GetSession(p.TenantID, p.TransactionID, domain.TransitionActor{})
+	if err != nil {
+		return err
+	}
+	if trx.Status.Paid() {
+		return nil
+	}
+	if !trx.Status.CanTransitionTo(domain.TransPaid) {
+		// Pelanggan membayar setelah sesi berakhir; uangnya harus dikembalikan manual
+		slog.Warn("Pembayaran masuk untuk sesi yang sudah berakhir", "payment_id", p.ID, "transaction_id", trx.ID, "status", trx.Status)
+		return nil
+	}
+
+	_, err = u.transactions.Transition(p.TenantID, trx.ID, domain.TransitionRequest{
+		Status: domain.TransPaid,
+		Reason: fmt.Sprintf("pembayaran %s %s lunas", p.Provider, p.ProviderRef),
+	}, domain.TransitionActor{})
+	return err
+}
+
+// rupiah membulatkan nominal ke rupiah penuh, satuan yang dipakai QRIS.
+func rupiah(amount float64) int64 {
+	return int64(math.Round(amount))
+}
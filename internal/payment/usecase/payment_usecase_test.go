@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/payment"
+	trUcase "photobooth-core/internal/transaction/usecase"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "whsec-test"
+
+type fakePaymentRepo struct {
+	payments map[uuid.UUID]*domain.Payment
+}
+
+func (r *fakePaymentRepo) Create(p *domain.Payment) error {
+	r.payments[p.ID] = p
+	return nil
+}
+
+func (r *fakePaymentRepo) FindByID(id uuid.UUID) (*domain.Payment, error) {
+	p, ok := r.payments[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	out := *p
+	return &out, nil
+}
+
+func (r *fakePaymentRepo) FindLatest(tenantID, transactionID uuid.UUID) (*domain.Payment, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePaymentRepo) FindPending(tenantID, transactionID uuid.UUID, now time.Time) (*domain.Payment, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePaymentRepo) Settle(id uuid.UUID, status domain.PaymentStatus) (bool, error) {
+	p := r.payments[id]
+	if p == nil || p.Status != domain.PaymentPending {
+		return false, nil
+	}
+	p.Status = status
+	return true, nil
+}
+
+func (r *fakePaymentRepo) SettlePaid(id uuid.UUID, paidAt time.Time) (bool, error) {
+	p := r.payments[id]
+	if p == nil || p.Status == domain.PaymentPaid {
+		return false, nil
+	}
+	p.Status = domain.PaymentPaid
+	p.PaidAt = &paidAt
+	return true, nil
+}
+
+// fakeTransactions hanya menyimpan status sesi; cukup untuk markPaid.
+type fakeTransactions struct {
+	trUcase.TransactionUsecase
+	trx         *domain.Transaction
+	transitions []domain.TransactionStatus
+}
+
+func (f *fakeTransactions) GetSession(tenantID, id uuid.UUID, actor domain.TransitionActor) (*domain.Transaction, error) {
+	if id != f.trx.ID {
+		return nil, trUcase.ErrTransactionNotFound
+	}
+	out := *f.trx
+	return &out, nil
+}
+
+func (f *fakeTransactions) Transition(tenantID, id uuid.UUID, req domain.TransitionRequest, actor domain.TransitionActor) (*domain.Transaction, error) {
+	if !f.trx.Status.CanTransitionTo(req.Status) {
+		return nil, trUcase.ErrInvalidTransition
+	}
+	f.trx.Status = req.Status
+	f.transitions = append(f.transitions, req.Status)
+	return f.trx, nil
+}
+
+func newWebhookTest(t *testing.T, paymentStatus domain.PaymentStatus, trxStatus domain.TransactionStatus) (PaymentUsecase, *fakePaymentRepo, *fakeTransactions, *domain.Payment) {
+	t.Helper()
+	provider, err := payment.NewQRISProvider("http://gateway.local", "", testWebhookSecret)
+	if err != nil {
+		t.Fatalf("NewQRISProvider: %v", err)
+	}
+
+	trx := &domain.Transaction{ID: uuid.New(), TenantID: uuid.New(), Status: trxStatus, Amount: 35000}
+	p := &domain.Payment{
+		ID:            uuid.New(),
+		TenantID:      trx.TenantID,
+		TransactionID: trx.ID,
+		Provider:      payment.ProviderQRIS,
+		ProviderRef:   "qris_1",
+		Amount:        35000,
+		Status:        paymentStatus,
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	}
+	repo := &fakePaymentRepo{payments: map[uuid.UUID]*domain.Payment{p.ID: p}}
+	transactions := &fakeTransactions{trx: trx}
+	return NewPaymentUsecase(repo, transactions, provider, 15*time.Minute), repo, transactions, p
+}
+
+func signedWebhook(p *domain.Payment, status payment.ChargeStatus, amount int64) (http.Header, []byte) {
+	body := []byte(fmt.Sprintf(`{"id":%q,"order_id":%q,"amount":%d,"status":%q,"paid_at":"2026-01-02T03:04:05Z"}`,
+		p.ProviderRef, p.ID, amount, status))
+	ts := time.Now().Unix()
+	h := http.Header{}
+	h.Set(payment.HeaderTimestamp, strconv.FormatInt(ts, 10))
+	h.Set(payment.HeaderSignature, payment.SignWebhook(testWebhookSecret, ts, body))
+	return h, body
+}
+
+func TestWebhookLatePaymentSettledAsPaid(t *testing.T) {
+	u, repo, transactions, p := newWebhookTest(t, domain.PaymentExpired, domain.TransAwaitingPayment)
+
+	header, body := signedWebhook(p, payment.ChargePaid, 35000)
+	if err := u.HandleWebhook(context.Background(), header, body); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+
+	got := repo.payments[p.ID]
+	if got.Status != domain.PaymentPaid || got.PaidAt == nil || got.PaidAt.Year() != 2026 {
+		t.Fatalf("tagihan = %+v, mau paid dengan paid_at dari gateway", got)
+	}
+	if transactions.trx.Status != domain.TransPaid {
+		t.Fatalf("sesi = %s, mau paid", transactions.trx.Status)
+	}
+
+	// Webhook yang dikirim ulang tidak memindahkan sesi dua kali
+	header, body = signedWebhook(p, payment.ChargePaid, 35000)
+	if err := u.HandleWebhook(context.Background(), header, body); err != nil {
+		t.Fatalf("HandleWebhook ulang: %v", err)
+	}
+	if len(transactions.transitions) != 1 {
+		t.Fatalf("transisi = %v, mau sekali", transactions.transitions)
+	}
+}
+
+func TestWebhookPaidAfterSessionEnded(t *testing.T) {
+	u, repo, transactions, p := newWebhookTest(t, domain.PaymentExpired, domain.TransFailed)
+
+	header, body := signedWebhook(p, payment.ChargePaid, 35000)
+	if err := u.HandleWebhook(context.Background(), header, body); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	// Uang tetap tercatat masuk walau sesinya sudah berakhir
+	if repo.payments[p.ID].Status != domain.PaymentPaid {
+		t.Fatalf("tagihan = %s, mau paid", repo.payments[p.ID].Status)
+	}
+	if len(transactions.transitions) != 0 {
+		t.Fatalf("sesi berakhir tidak boleh berpindah: %v", transactions.transitions)
+	}
+}
+
+func TestWebhookRejected(t *testing.T) {
+	u, repo, transactions, p := newWebhookTest(t, domain.PaymentPending, domain.TransAwaitingPayment)
+
+	header, body := signedWebhook(p, payment.ChargePaid, 1000)
+	if err := u.HandleWebhook(context.Background(), header, body); !errors.Is(err, ErrAmountMismatch) {
+		t.Fatalf("nominal beda: err = %v, mau ErrAmountMismatch", err)
+	}
+
+	header, body = signedWebhook(p, payment.ChargePaid, 35000)
+	header.Set(payment.HeaderSignature, payment.SignWebhook("secret-lain", time.Now().Unix(), body))
+	if err := u.HandleWebhook(context.Background(), header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("HMAC salah: err = %v, mau ErrInvalidSignature", err)
+	}
+
+	if repo.payments[p.ID].Status != domain.PaymentPending || len(transactions.transitions) != 0 {
+		t.Fatal("webhook yang ditolak tidak boleh mengubah tagihan atau sesi")
+	}
+}
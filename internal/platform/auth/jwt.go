@@ -30,3 +30,30 @@ func ValidateToken(tokenString string) (*jwt.Token, error) {
 		return []byte(os.Getenv("JWT_SECRET")), nil
 	})
 }
+
+// ParseUserToken memvalidasi token user dashboard (bukan token mesin) dan
+// mengembalikan tenant serta user-nya. Dipakai booth untuk otorisasi staf
+// di tempat, misalnya cetak ulang.
+func ParseUserToken(tokenString string) (tenantID, userID uuid.UUID, err error) {
+	token, err := ValidateToken(tokenString)
+	if err != nil || !token.Valid {
+		return uuid.Nil, uuid.Nil, errors.New("token tidak valid")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, uuid.Nil, errors.New("payload token tidak valid")
+	}
+	if role, _ := claims["role"].(string); role == "device" {
+		return uuid.Nil, uuid.Nil, errors.New("token mesin bukan token staf")
+	}
+
+	rawTenant, _ := claims["tenant_id"].(string)
+	rawUser, _ := claims["user_id"].(string)
+	if tenantID, err = uuid.Parse(rawTenant); err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("tenant token tidak valid")
+	}
+	if userID, err = uuid.Parse(rawUser); err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("user token tidak valid")
+	}
+	return tenantID, userID, nil
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("tanda tangan link tidak valid")
+	ErrLinkExpired      = errors.New("link sudah kedaluwarsa")
+)
+
+// URLSigner membuat dan memverifikasi link publik (HMAC-SHA256) yang terikat
+// ke satu resource, satu tenant, dan punya waktu kedaluwarsa.
+type URLSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewURLSigner membuat signer dengan secret tertentu.
+func NewURLSigner(secret string) *URLSigner {
+	return &URLSigner{secret: []byte(secret), now: time.Now}
+}
+
+// Query menghasilkan parameter tenant, exp dan sig untuk ditempel ke URL resource.
+func (s *URLSigner) Query(resource string, tenantID uuid.UUID, expiresAt time.Time) url.Values {
+	exp := expiresAt.Unix()
+	q := url.Values{}
+	q.Set("tenant", tenantID.String())
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", s.sign(resource, tenantID, exp))
+	return q
+}
+
+// Verify mengecek parameter link dan mengembalikan tenant yang di-scope oleh link.
+func (s *URLSigner) Verify(resource string, q url.Values) (uuid.UUID, error) {
+	tenantID, err := uuid.Parse(q.Get("tenant"))
+	if err != nil {
+		return uuid.Nil, ErrSignatureInvalid
+	}
+	exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err != nil {
+		return uuid.Nil, ErrSignatureInvalid
+	}
+
+	expected := s.sign(resource, tenantID, exp)
+	if !hmac.Equal([]byte(expected), []byte(q.Get("sig"))) {
+		return uuid.Nil, ErrSignatureInvalid
+	}
+	if s.now().Unix() > exp {
+		return uuid.Nil, ErrLinkExpired
+	}
+	return tenantID, nil
+}
+
+func (s *URLSigner) sign(resource string, tenantID uuid.UUID, exp int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s|%s|%d", resource, tenantID, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
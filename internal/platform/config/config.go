@@ -1,25 +1,121 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"log/slog"
 	"os"
+	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBDSN     string
-	AppPort   string
-	JWTSecret string
+	DBDSN         string
+	AppPort       string
+	JWTSecret     string
+	PublicBaseURL string
+
+	// Link download foto untuk tamu (QR). Tanpa SIGNED_URL_SECRET, kuncinya
+	// diturunkan (HKDF) dari JWT_SECRET supaya token JWT tidak bisa dipakai
+	// sebagai tanda tangan link, dan sebaliknya.
+	SignedURLSecret string
+	PhotoRetention  time.Duration
+
+	// Batas default ukuran satu file upload foto (bisa di-override per tenant)
+	UploadMaxBytes int64
+	// Upload bertahap yang diam lebih lama dari ini akan dibersihkan
+	UploadTTL time.Duration
+	// Sesi foto yang statusnya tidak berubah lebih lama dari ini ditandai
+	// failed. Sesi yang sudah dibayar memakai SessionPaidTimeout.
+	SessionTimeout     time.Duration
+	SessionPaidTimeout time.Duration
+
+	// Pagar gambar upload: dimensi maksimum (anti decompression bomb) dan
+	// kualitas JPEG saat di-encode ulang
+	ImageMaxWidth    int
+	ImageMaxHeight   int
+	ImageMaxPixels   int64
+	ImageJPEGQuality int
+
+	// Rendition (thumbnail/web) dibuat worker di belakang setelah upload
+	RenditionWorkers int
+	RenditionWebP    bool
+
+	// Printer default untuk booth yang belum punya pengaturan printer sendiri.
+	// Driver: "ipp" (CUPS/printer jaringan) atau "sumatra" (Windows).
+	PrintDriver string
+	PrintIPPURI string
+	PrintQueue  string
+	SumatraPath string
+
+	// Object storage untuk foto. Driver: "local" (default) atau "s3".
+	StorageDriver    string
+	StorageLocalPath string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3UseSSL         bool
+	S3PathStyle      bool
+
+	// Payment gateway untuk sesi berbayar. Provider: "qris". Tanpa webhook
+	// secret pembayaran dimatikan (sesi hanya bisa ditandai lunas oleh staff).
+	PaymentProvider      string
+	PaymentBaseURL       string
+	PaymentServerKey     string
+	PaymentWebhookSecret string
+	PaymentQRTTL         time.Duration
 }
 
 func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	cfg := &Config{
-		DBDSN:     os.Getenv("DATABASE_URL"),
-		AppPort:   os.Getenv("APP_PORT"),
-		JWTSecret: os.Getenv("JWT_SECRET"),
+		DBDSN:         os.Getenv("DATABASE_URL"),
+		AppPort:       os.Getenv("APP_PORT"),
+		JWTSecret:     os.Getenv("JWT_SECRET"),
+		PublicBaseURL: os.Getenv("PUBLIC_BASE_URL"),
+
+		SignedURLSecret:    os.Getenv("SIGNED_URL_SECRET"),
+		PhotoRetention:     getEnvDuration("PHOTO_RETENTION", 7*24*time.Hour),
+		UploadMaxBytes:     getEnvInt64("UPLOAD_MAX_BYTES", 25<<20),
+		UploadTTL:          getEnvDuration("UPLOAD_TTL", 24*time.Hour),
+		SessionTimeout:     getEnvDuration("SESSION_TIMEOUT", 30*time.Minute),
+		SessionPaidTimeout: getEnvDuration("SESSION_PAID_TIMEOUT", 3*time.Hour),
+
+		ImageMaxWidth:    int(getEnvInt64("IMAGE_MAX_WIDTH", 12000)),
+		ImageMaxHeight:   int(getEnvInt64("IMAGE_MAX_HEIGHT", 12000)),
+		ImageMaxPixels:   getEnvInt64("IMAGE_MAX_PIXELS", 50_000_000),
+		ImageJPEGQuality: int(getEnvInt64("IMAGE_JPEG_QUALITY", 92)),
+		RenditionWorkers: int(getEnvInt64("RENDITION_WORKERS", 2)),
+		RenditionWebP:    getEnvBool("RENDITION_WEBP", false),
+
+		PrintDriver: getEnv("PRINT_DRIVER", defaultPrintDriver()),
+		PrintIPPURI: getEnv("PRINT_IPP_URI", "ipp://localhost:631/printers/photobooth"),
+		PrintQueue:  getEnv("PRINT_QUEUE", "Brother HL-L5100DN series"),
+		SumatraPath: getEnv("SUMATRA_PATH", "./SumatraPDF.exe"),
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath: getEnv("STORAGE_LOCAL_PATH", "./storage"),
+		S3Endpoint:       os.Getenv("S3_ENDPOINT"),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         os.Getenv("S3_BUCKET"),
+		S3AccessKey:      os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:      os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:         getEnvBool("S3_USE_SSL", true),
+		// MinIO dan kebanyakan S3-compatible lokal cuma jalan dengan path-style
+		S3PathStyle: getEnvBool("S3_PATH_STYLE", true),
+
+		PaymentProvider:      getEnv("PAYMENT_PROVIDER", "qris"),
+		PaymentBaseURL:       getEnv("PAYMENT_BASE_URL", "http://localhost:8787"),
+		PaymentServerKey:     os.Getenv("PAYMENT_SERVER_KEY"),
+		PaymentWebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		PaymentQRTTL:         getEnvDuration("PAYMENT_QR_TTL", 15*time.Minute),
 	}
 
 	// VALIDATOR: Langsung hentikan aplikasi jika config krusial kosong
@@ -31,6 +127,60 @@ func LoadConfig() *Config {
 	if cfg.AppPort == "" {
 		cfg.AppPort = "8080"
 	}
+	if cfg.SignedURLSecret == "" {
+		slog.Warn("SIGNED_URL_SECRET kosong, kunci link foto diturunkan dari JWT_SECRET. Isi secret terpisah di production")
+		key, err := hkdf.Key(sha256.New, []byte(cfg.JWTSecret), nil, "photobooth-core signed url", 32)
+		if err != nil {
+			slog.Error("Gagal menurunkan kunci link foto", "error", err)
+			os.Exit(1)
+		}
+		cfg.SignedURLSecret = hex.EncodeToString(key)
+	} else if cfg.SignedURLSecret == cfg.JWTSecret {
+		slog.Warn("SIGNED_URL_SECRET sama dengan JWT_SECRET, sebaiknya dibedakan")
+	}
+	if cfg.PublicBaseURL == "" {
+		cfg.PublicBaseURL = "http://localhost:" + cfg.AppPort
+	}
 
 	return cfg
 }
+
+// defaultPrintDriver mempertahankan perilaku lama (SumatraPDF) di Windows,
+// selain itu lewat CUPS.
+func defaultPrintDriver() string {
+	if runtime.GOOS == "windows" {
+		return "sumatra"
+	}
+	return "ipp"
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	val, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return val
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	val, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	val, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
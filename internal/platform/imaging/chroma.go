@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// maxFeather membatasi radius blur tepi supaya tetap murah di CPU booth.
+const maxFeather = 50
+
+// ChromaKey adalah pengaturan green/blue screen. Jarak warna diukur di
+// bidang chroma (Cb/Cr) sehingga bayangan di latar tetap ikut terhapus.
+//
+// Tolerance: jarak yang dianggap latar penuh (0..1).
+// Softness: lebar transisi setelah Tolerance, untuk tepi rambut (0..1).
+// Spill: seberapa kuat pantulan warna latar di subjek dibuang (0..1).
+// Feather: radius blur alpha di tepi, dalam pixel.
+type ChromaKey struct {
+	Key       color.RGBA
+	Tolerance float64
+	Softness  float64
+	Spill     float64
+	Feather   int
+}
+
+// Apply menghasilkan salinan gambar dengan latar dibuat transparan.
+func (k *ChromaKey) Apply(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+
+	_, kcb, kcr := ycbcr(float64(k.Key.R), float64(k.Key.G), float64(k.Key.B))
+	klen := math.Hypot(kcb, kcr)
+	var ucb, ucr float64
+	if klen > 0 {
+		ucb, ucr = kcb/klen, kcr/klen
+	}
+
+	// pix diubah jadi warna non-premultiplied dulu, alpha dikalikan lagi di akhir
+	alpha := make([]float64, w*h)
+	pix := dst.Pix
+	for i := range alpha {
+		p := i * 4
+		sa := float64(pix[p+3])
+		if sa == 0 {
+			continue
+		}
+		r, g, bl := float64(pix[p])*255/sa, float64(pix[p+1])*255/sa, float64(pix[p+2])*255/sa
+		y, cb, cr := ycbcr(r, g, bl)
+
+		// Skala 2 supaya jarak chroma kira-kira 0..1 untuk warna yang umum
+		dist := math.Hypot(cb-kcb, cr-kcr) * 2
+		a := 1.0
+		switch {
+		case dist <= k.Tolerance:
+			a = 0
+		case k.Softness > 0 && dist < k.Tolerance+k.Softness:
+			a = (dist - k.Tolerance) / k.Softness
+		}
+		alpha[i] = a * sa / 255
+
+		// Buang komponen chroma yang searah warna latar (pantulan hijau di kulit/rambut)
+		if k.Spill > 0 && klen > 0 {
+			if proj := cb*ucb + cr*ucr; proj > 0 {
+				cb -= ucb * proj * k.Spill
+				cr -= ucr * proj * k.Spill
+				pix[p], pix[p+1], pix[p+2] = rgb(y, cb, cr)
+				continue
+			}
+		}
+		pix[p], pix[p+1], pix[p+2] = clamp8(r), clamp8(g), clamp8(bl)
+	}
+
+	if k.Feather > 0 {
+		boxBlur(alpha, w, h, min(k.Feather, maxFeather))
+	}
+
+	for i, a := range alpha {
+		p := i * 4
+		pix[p] = clamp8(float64(pix[p]) * a)
+		pix[p+1] = clamp8(float64(pix[p+1]) * a)
+		pix[p+2] = clamp8(float64(pix[p+2]) * a)
+		pix[p+3] = clamp8(a * 255)
+	}
+	return dst
+}
+
+// ycbcr mengubah RGB 0..255 ke Y 0..255 dan Cb/Cr -0.5..0.5 (BT.601).
+func ycbcr(r, g, b float64) (float64, float64, float64) {
+	y := 0.299*r + 0.587*g + 0.114*b
+	cb := (-0.168736*r - 0.331264*g + 0.5*b) / 255
+	cr := (0.5*r - 0.418688*g - 0.081312*b) / 255
+	return y, cb, cr
+}
+
+func rgb(y, cb, cr float64) (uint8, uint8, uint8) {
+	cb, cr = cb*255, cr*255
+	return clamp8(y + 1.402*cr), clamp8(y - 0.344136*cb - 0.714136*cr), clamp8(y + 1.772*cb)
+}
+
+// boxBlur mengaburkan kanal alpha secara horizontal lalu vertikal.
+func boxBlur(values []float64, w, h, radius int) {
+	tmp := make([]float64, len(values))
+	blurLine := func(src, dst []float64, n, stride, offset int) {
+		var sum float64
+		count := 0
+		for i := 0; i <= radius && i < n; i++ {
+			sum += src[offset+i*stride]
+			count++
+		}
+		for i := 0; i < n; i++ {
+			dst[offset+i*stride] = sum / float64(count)
+			if out := i - radius; out >= 0 {
+				sum -= src[offset+out*stride]
+				count--
+			}
+			if in := i + radius + 1; in < n {
+				sum += src[offset+in*stride]
+				count++
+			}
+		}
+	}
+	for y := 0; y < h; y++ {
+		blurLine(values, tmp, w, 1, y*w)
+	}
+	for x := 0; x < w; x++ {
+		blurLine(tmp, values, h, w, x)
+	}
+}
+
+// Composite menempelkan hasil chroma key di atas latar yang di-crop
+// seukuran foreground. Latar *image.Uniform dipakai sebagai warna polos.
+func Composite(fg *image.RGBA, bg image.Image) *image.RGBA {
+	var dst *image.RGBA
+	if solid, ok := bg.(*image.Uniform); ok {
+		dst = image.NewRGBA(image.Rect(0, 0, fg.Bounds().Dx(), fg.Bounds().Dy()))
+		draw.Draw(dst, dst.Bounds(), solid, image.Point{}, draw.Src)
+	} else {
+		dst = Cover(bg, fg.Bounds().Dx(), fg.Bounds().Dy())
+	}
+	draw.Draw(dst, dst.Bounds(), fg, fg.Bounds().Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+// Cover mengisi kotak w×h penuh dengan gambar, memotong sisi yang kelebihan
+// (seperti CSS object-fit: cover).
+func Cover(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	scale := math.Max(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+	cropW := int(math.Round(float64(w) / scale))
+	cropH := int(math.Round(float64(h) / scale))
+	x0 := b.Min.X + (b.Dx()-cropW)/2
+	y0 := b.Min.Y + (b.Dy()-cropH)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, image.Rect(x0, y0, x0+cropW, y0+cropH), draw.Src, nil)
+	return dst
+}
+
+// Contain memasukkan seluruh gambar ke kotak w×h tanpa dipotong; sisa
+// ruangnya transparan (seperti CSS object-fit: contain).
+func Contain(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	scale := math.Min(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+	fitW := int(math.Round(float64(b.Dx()) * scale))
+	fitH := int(math.Round(float64(b.Dy()) * scale))
+	x0 := (w - fitW) / 2
+	y0 := (h - fitH) / 2
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, image.Rect(x0, y0, x0+fitW, y0+fitH), src, b, draw.Src, nil)
+	return dst
+}
+
+// DrawRotated menggambar src di atas dst dengan titik tengahnya di (cx, cy),
+// diputar deg derajat searah jarum jam.
+func DrawRotated(dst draw.Image, src image.Image, cx, cy, deg float64) {
+	b := src.Bounds()
+	if deg == 0 {
+		x0 := int(math.Round(cx - float64(b.Dx())/2))
+		y0 := int(math.Round(cy - float64(b.Dy())/2))
+		draw.Draw(dst, image.Rect(x0, y0, x0+b.Dx(), y0+b.Dy()), src, b.Min, draw.Over)
+		return
+	}
+
+	rad := deg * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	// Geser ke pusat src, putar, lalu geser ke (cx, cy)
+	hx := float64(b.Min.X) + float64(b.Dx())/2
+	hy := float64(b.Min.Y) + float64(b.Dy())/2
+	m := f64.Aff3{
+		cos, -sin, cx - cos*hx + sin*hy,
+		sin, cos, cy - sin*hx - cos*hy,
+	}
+	draw.BiLinear.Transform(dst, m, src, b, draw.Over, nil)
+}
+
+// ParseHexColor membaca warna "#rgb", "#rrggbb" atau "#rrggbbaa".
+func ParseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("warna tidak valid: %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("warna tidak valid: %q", s)
+	}
+
+	// image/color memakai alpha premultiplied
+	a := uint32(v & 0xff)
+	return color.RGBA{
+		R: uint8(uint32(v>>24&0xff) * a / 0xff),
+		G: uint8(uint32(v>>16&0xff) * a / 0xff),
+		B: uint8(uint32(v>>8&0xff) * a / 0xff),
+		A: uint8(a),
+	}, nil
+}
@@ -0,0 +1,251 @@
+package imaging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// ErrInvalidLUT dikembalikan kalau file .cube tidak bisa dibaca.
+var ErrInvalidLUT = errors.New("file LUT .cube tidak valid")
+
+// maxLUTSize membatasi LUT 3D (65^3 sudah ukuran terbesar yang umum dipakai).
+const maxLUTSize = 65
+
+// Tone adalah perubahan warna dasar sebelum penyesuaian.
+type Tone string
+
+const (
+	ToneNone  Tone = ""
+	ToneBW    Tone = "bw"
+	ToneSepia Tone = "sepia"
+)
+
+// Filter adalah satu resep warna: tone atau LUT, lalu brightness/contrast/
+// saturation (masing-masing -1..1, 0 = tidak berubah), dicampur dengan
+// gambar asli sesuai Intensity (0..1).
+type Filter struct {
+	Tone       Tone
+	LUT        *LUT3D
+	Brightness float64
+	Contrast   float64
+	Saturation float64
+	Intensity  float64
+}
+
+// Apply menerapkan filter ke salinan gambar. Gambar asli tidak diubah.
+func (f *Filter) Apply(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+
+	intensity := f.Intensity
+	if intensity <= 0 || intensity > 1 {
+		intensity = 1
+	}
+	contrast := 1 + f.Contrast
+	brightness := f.Brightness * 255
+	saturation := 1 + f.Saturation
+
+	pix := dst.Pix
+	for i := 0; i < len(pix); i += 4 {
+		a := float64(pix[i+3])
+		if a == 0 {
+			continue
+		}
+		// Pix di image.RGBA premultiplied, kembalikan dulu ke warna asli
+		r0 := float64(pix[i]) * 255 / a
+		g0 := float64(pix[i+1]) * 255 / a
+		b0 := float64(pix[i+2]) * 255 / a
+		r, g, bl := r0, g0, b0
+
+		switch {
+		case f.LUT != nil:
+			r, g, bl = f.LUT.lookup(r/255, g/255, bl/255)
+			r, g, bl = r*255, g*255, bl*255
+		case f.Tone == ToneBW:
+			l := luma(r, g, bl)
+			r, g, bl = l, l, l
+		case f.Tone == ToneSepia:
+			r, g, bl = 0.393*r+0.769*g+0.189*bl, 0.349*r+0.686*g+0.168*bl, 0.272*r+0.534*g+0.131*bl
+		}
+
+		if saturation != 1 {
+			l := luma(r, g, bl)
+			r, g, bl = l+(r-l)*saturation, l+(g-l)*saturation, l+(bl-l)*saturation
+		}
+		if contrast != 1 {
+			r, g, bl = (r-128)*contrast+128, (g-128)*contrast+128, (bl-128)*contrast+128
+		}
+		r, g, bl = r+brightness, g+brightness, bl+brightness
+
+		if intensity < 1 {
+			r = r0 + (r-r0)*intensity
+			g = g0 + (g-g0)*intensity
+			bl = b0 + (bl-b0)*intensity
+		}
+
+		pix[i] = clamp8(r * a / 255)
+		pix[i+1] = clamp8(g * a / 255)
+		pix[i+2] = clamp8(bl * a / 255)
+	}
+	return dst
+}
+
+func luma(r, g, b float64) float64 {
+	return 0.299*r + 0.587*g + 0.114*b
+}
+
+func clamp8(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
+
+// LUT3D adalah lookup table warna dari file .cube (format Adobe/Resolve).
+// Data berisi Size^3 triplet RGB dengan indeks merah berubah paling cepat.
+type LUT3D struct {
+	Size      int
+	DomainMin [3]float64
+	DomainMax [3]float64
+	Data      []float32
+}
+
+// ParseCube membaca LUT 3D dari file .cube.
+func ParseCube(r io.Reader) (*LUT3D, error) {
+	lut := &LUT3D{DomainMax: [3]float64{1, 1, 1}}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 64<<10)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+
+		switch strings.ToUpper(fields[0]) {
+		case "TITLE":
+			continue
+		case "LUT_1D_SIZE":
+			return nil, fmt.Errorf("%w: LUT 1D belum didukung", ErrInvalidLUT)
+		case "LUT_3D_SIZE":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%w: baris %d", ErrInvalidLUT, line)
+			}
+			size, err := strconv.Atoi(fields[1])
+			if err != nil || size < 2 || size > maxLUTSize {
+				return nil, fmt.Errorf("%w: LUT_3D_SIZE harus 2-%d", ErrInvalidLUT, maxLUTSize)
+			}
+			lut.Size = size
+			lut.Data = make([]float32, 0, size*size*size*3)
+			continue
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			values, err := parseTriplet(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: baris %d", ErrInvalidLUT, line)
+			}
+			if strings.ToUpper(fields[0]) == "DOMAIN_MIN" {
+				lut.DomainMin = values
+			} else {
+				lut.DomainMax = values
+			}
+			continue
+		}
+
+		if lut.Size == 0 {
+			return nil, fmt.Errorf("%w: LUT_3D_SIZE harus ada sebelum data", ErrInvalidLUT)
+		}
+		values, err := parseTriplet(fields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: baris %d", ErrInvalidLUT, line)
+		}
+		if len(lut.Data) >= cap(lut.Data) {
+			return nil, fmt.Errorf("%w: data melebihi LUT_3D_SIZE", ErrInvalidLUT)
+		}
+		lut.Data = append(lut.Data, float32(values[0]), float32(values[1]), float32(values[2]))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if lut.Size == 0 || len(lut.Data) != lut.Size*lut.Size*lut.Size*3 {
+		return nil, fmt.Errorf("%w: jumlah data tidak sesuai LUT_3D_SIZE", ErrInvalidLUT)
+	}
+	for ch := 0; ch < 3; ch++ {
+		if lut.DomainMax[ch] <= lut.DomainMin[ch] {
+			return nil, fmt.Errorf("%w: DOMAIN_MAX harus lebih besar dari DOMAIN_MIN", ErrInvalidLUT)
+		}
+	}
+	return lut, nil
+}
+
+func parseTriplet(fields []string) ([3]float64, error) {
+	var out [3]float64
+	if len(fields) != 3 {
+		return out, errors.New("butuh tiga angka")
+	}
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return out, errors.New("angka tidak valid")
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// lookup mengambil warna hasil LUT dengan interpolasi trilinear. Input dan
+// output dalam rentang 0..1.
+func (l *LUT3D) lookup(r, g, b float64) (float64, float64, float64) {
+	n := float64(l.Size - 1)
+	pos := [3]float64{r, g, b}
+	var i0, i1 [3]int
+	var t [3]float64
+	for ch := 0; ch < 3; ch++ {
+		v := (pos[ch] - l.DomainMin[ch]) / (l.DomainMax[ch] - l.DomainMin[ch]) * n
+		v = math.Max(0, math.Min(n, v))
+		i0[ch] = int(v)
+		i1[ch] = min(i0[ch]+1, l.Size-1)
+		t[ch] = v - float64(i0[ch])
+	}
+
+	var out [3]float64
+	for ch := 0; ch < 3; ch++ {
+		c000 := l.at(i0[0], i0[1], i0[2], ch)
+		c100 := l.at(i1[0], i0[1], i0[2], ch)
+		c010 := l.at(i0[0], i1[1], i0[2], ch)
+		c110 := l.at(i1[0], i1[1], i0[2], ch)
+		c001 := l.at(i0[0], i0[1], i1[2], ch)
+		c101 := l.at(i1[0], i0[1], i1[2], ch)
+		c011 := l.at(i0[0], i1[1], i1[2], ch)
+		c111 := l.at(i1[0], i1[1], i1[2], ch)
+
+		c00 := c000 + (c100-c000)*t[0]
+		c10 := c010 + (c110-c010)*t[0]
+		c01 := c001 + (c101-c001)*t[0]
+		c11 := c011 + (c111-c011)*t[0]
+		c0 := c00 + (c10-c00)*t[1]
+		c1 := c01 + (c11-c01)*t[1]
+		out[ch] = c0 + (c1-c0)*t[2]
+	}
+	return out[0], out[1], out[2]
+}
+
+func (l *LUT3D) at(r, g, b, ch int) float64 {
+	return float64(l.Data[((b*l.Size+g)*l.Size+r)*3+ch])
+}
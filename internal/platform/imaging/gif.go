@@ -0,0 +1,168 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sort"
+	"time"
+)
+
+// AnimationOptions mengatur encode GIF animasi.
+type AnimationOptions struct {
+	Delay  time.Duration // jeda antar frame, dibulatkan ke 10ms (satuan GIF)
+	Colors int           // jumlah warna palet, 2-256
+	Dither bool          // Floyd-Steinberg, lebih halus tapi file lebih besar
+}
+
+// EncodeGIF menyusun frame-frame menjadi GIF yang berulang terus. Palet
+// dibuat sekali dari semua frame (median cut) supaya warna tidak berkedip
+// antar frame.
+func EncodeGIF(w io.Writer, frames []image.Image, opts AnimationOptions) error {
+	colors := opts.Colors
+	if colors < 2 || colors > 256 {
+		colors = 256
+	}
+	delay := int(opts.Delay / (10 * time.Millisecond))
+	if delay < 2 {
+		// Kebanyakan browser memperlambat jeda < 20ms jadi 100ms
+		delay = 2
+	}
+
+	palette := MedianCutPalette(frames, colors)
+	lookup := newPaletteLookup(palette)
+
+	anim := &gif.GIF{LoopCount: 0}
+	for _, frame := range frames {
+		b := frame.Bounds()
+		paletted := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette)
+		if opts.Dither {
+			draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), frame, b.Min)
+		} else {
+			for y := 0; y < b.Dy(); y++ {
+				for x := 0; x < b.Dx(); x++ {
+					paletted.SetColorIndex(x, y, lookup.index(frame.At(b.Min.X+x, b.Min.Y+y)))
+				}
+			}
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+// MedianCutPalette membuat palet n warna dari sampel pixel semua gambar.
+func MedianCutPalette(images []image.Image, n int) color.Palette {
+	const maxSamples = 200_000
+
+	total := 0
+	for _, img := range images {
+		total += img.Bounds().Dx() * img.Bounds().Dy()
+	}
+	step := 1
+	if total > maxSamples {
+		step = total / maxSamples
+	}
+
+	samples := make([][3]uint8, 0, min(total, maxSamples)+len(images))
+	for _, img := range images {
+		b := img.Bounds()
+		i := 0
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if i%step == 0 {
+					r, g, bl, _ := img.At(x, y).RGBA()
+					samples = append(samples, [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8)})
+				}
+				i++
+			}
+		}
+	}
+	if len(samples) == 0 {
+		return color.Palette{color.Black, color.White}
+	}
+
+	boxes := [][][3]uint8{samples}
+	for len(boxes) < n {
+		// Belah kotak dengan rentang warna terlebar di channel terlebarnya
+		best, bestChannel, bestRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			channel, rng := widestChannel(box)
+			if rng > bestRange {
+				best, bestChannel, bestRange = i, channel, rng
+			}
+		}
+		if best == -1 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(a, b int) bool { return box[a][bestChannel] < box[b][bestChannel] })
+		mid := len(box) / 2
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, box := range boxes {
+		var r, g, b int
+		for _, c := range box {
+			r += int(c[0])
+			g += int(c[1])
+			b += int(c[2])
+		}
+		n := len(box)
+		palette = append(palette, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), 255})
+	}
+	return palette
+}
+
+func widestChannel(box [][3]uint8) (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	hi := [3]uint8{}
+	for _, c := range box {
+		for ch := 0; ch < 3; ch++ {
+			lo[ch] = min(lo[ch], c[ch])
+			hi[ch] = max(hi[ch], c[ch])
+		}
+	}
+	channel, rng := 0, 0
+	for ch := 0; ch < 3; ch++ {
+		if r := int(hi[ch]) - int(lo[ch]); r > rng {
+			channel, rng = ch, r
+		}
+	}
+	return channel, rng
+}
+
+// paletteLookup menyimpan hasil pencarian warna terdekat per warna 15-bit,
+// karena Palette.Index linear terhadap jumlah warna.
+type paletteLookup struct {
+	palette color.Palette
+	cache   []int16
+}
+
+func newPaletteLookup(palette color.Palette) *paletteLookup {
+	cache := make([]int16, 1<<15)
+	for i := range cache {
+		cache[i] = -1
+	}
+	return &paletteLookup{palette, cache}
+}
+
+func (l *paletteLookup) index(c color.Color) uint8 {
+	r, g, b, _ := c.RGBA()
+	key := (r>>11)<<10 | (g>>11)<<5 | b>>11
+	if idx := l.cache[key]; idx >= 0 {
+		return uint8(idx)
+	}
+	idx := l.palette.Index(c)
+	l.cache[key] = int16(idx)
+	return uint8(idx)
+}
@@ -0,0 +1,150 @@
+// Package imaging memvalidasi dan membersihkan gambar yang diupload booth
+// sebelum disimpan: cek magic bytes, batas dimensi, decode penuh, lalu
+// encode ulang supaya metadata (EXIF/GPS) ikut terbuang.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Reason adalah kode alasan penolakan yang dikirim ke client.
+type Reason string
+
+const (
+	ReasonEmpty              Reason = "empty_file"
+	ReasonInvalidEncoding    Reason = "invalid_encoding"
+	ReasonUnsupportedFormat  Reason = "unsupported_format"
+	ReasonCorrupt            Reason = "corrupt_image"
+	ReasonDimensionsExceeded Reason = "dimensions_exceeded"
+)
+
+// RejectError dikembalikan kalau file bukan gambar yang boleh disimpan.
+type RejectError struct {
+	Reason Reason `json:"reason"`
+	Detail string `json:"detail"`
+}
+
+func (e *RejectError) Error() string {
+	return fmt.Sprintf("gambar ditolak (%s): %s", e.Reason, e.Detail)
+}
+
+func reject(reason Reason, format string, args ...interface{}) *RejectError {
+	return &RejectError{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Format gambar yang diterima.
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// Limits adalah pagar terhadap decompression bomb. Dicek dari header
+// sebelum gambar di-decode penuh.
+type Limits struct {
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int64
+}
+
+// Sanitized adalah gambar bersih yang siap disimpan.
+type Sanitized struct {
+	Data        []byte
+	Image       image.Image
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Sniff mengenali format dari magic bytes, bukan dari nama file atau header client.
+func Sniff(head []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG, true
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, true
+	case len(head) >= 12 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		return FormatWebP, true
+	default:
+		return "", false
+	}
+}
+
+// Sanitize memvalidasi lalu meng-encode ulang gambar. JPEG dan WebP disimpan
+// sebagai JPEG, PNG tetap PNG supaya transparansi frame tidak hilang.
+// Orientasi EXIF diterapkan ke pixel sebelum metadata dibuang.
+func Sanitize(src io.ReadSeeker, limits Limits, jpegQuality int) (*Sanitized, error) {
+	head := make([]byte, 16)
+	n, _ := io.ReadFull(src, head)
+	if n == 0 {
+		return nil, reject(ReasonEmpty, "file kosong")
+	}
+	format, ok := Sniff(head[:n])
+	if !ok {
+		return nil, reject(ReasonUnsupportedFormat, "hanya JPEG, PNG dan WebP yang diterima")
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return nil, reject(ReasonCorrupt, "header gambar tidak bisa dibaca")
+	}
+	if err := checkLimits(cfg, limits); err != nil {
+		return nil, err
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return nil, reject(ReasonCorrupt, "gambar rusak atau terpotong: %v", err)
+	}
+
+	if format == FormatJPEG {
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		img = applyOrientation(img, readOrientation(src))
+	}
+
+	var buf bytes.Buffer
+	out := &Sanitized{Image: img, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if format == FormatPNG {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		out.ContentType, out.Ext = "image/png", ".png"
+	} else {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		out.ContentType, out.Ext = "image/jpeg", ".jpg"
+	}
+	out.Data = buf.Bytes()
+
+	return out, nil
+}
+
+func checkLimits(cfg image.Config, limits Limits) error {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return reject(ReasonCorrupt, "dimensi gambar tidak valid")
+	}
+	if (limits.MaxWidth > 0 && cfg.Width > limits.MaxWidth) || (limits.MaxHeight > 0 && cfg.Height > limits.MaxHeight) {
+		return reject(ReasonDimensionsExceeded, "dimensi %dx%d melebihi batas %dx%d", cfg.Width, cfg.Height, limits.MaxWidth, limits.MaxHeight)
+	}
+	if limits.MaxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > limits.MaxPixels {
+		return reject(ReasonDimensionsExceeded, "jumlah pixel %d melebihi batas %d", int64(cfg.Width)*int64(cfg.Height), limits.MaxPixels)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package imaging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// readOrientation membaca tag Orientation (0x0112) dari segmen APP1 EXIF.
+// Kalau tidak ada atau tidak terbaca, dianggap 1 (normal).
+func readOrientation(r io.Reader) int {
+	br := bufio.NewReader(r)
+	marker := make([]byte, 2)
+	if _, err := io.ReadFull(br, marker); err != nil || marker[0] != 0xFF || marker[1] != 0xD8 {
+		return 1
+	}
+
+	for {
+		if _, err := io.ReadFull(br, marker); err != nil || marker[0] != 0xFF {
+			return 1
+		}
+		// SOS atau EOI: metadata sudah lewat
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return 1
+		}
+
+		var size uint16
+		if err := binary.Read(br, binary.BigEndian, &size); err != nil || size < 2 {
+			return 1
+		}
+		segment := make([]byte, size-2)
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return 1
+		}
+
+		if marker[1] == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return orientationFromTIFF(segment[6:])
+		}
+	}
+}
+
+func orientationFromTIFF(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation memutar/membalik pixel sesuai nilai orientasi EXIF (1-8).
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // cermin horizontal
+				dx, dy = w-1-x, y
+			case 3: // putar 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // cermin vertikal
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // putar 90 searah jarum jam
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // putar 90 berlawanan jarum jam
+				dx, dy = y, w-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,48 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+// Fit mengecilkan gambar supaya sisi terpanjangnya maksimal maxSide.
+// Gambar yang sudah lebih kecil (atau maxSide <= 0) dikembalikan apa adanya,
+// tidak pernah diperbesar.
+func Fit(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if maxSide <= 0 || (w <= maxSide && h <= maxSide) {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*maxSide/w)
+		w = maxSide
+	} else {
+		w = max(1, w*maxSide/h)
+		h = maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// Encode menulis gambar dalam format jpeg atau webp dan mengembalikan content type-nya.
+// Encoder WebP yang dipakai pure Go dan hanya lossless, jadi ukurannya lebih
+// besar dari JPEG untuk foto; cocok untuk thumbnail dan ukuran web.
+func Encode(w io.Writer, img image.Image, format string, jpegQuality int) (string, error) {
+	switch format {
+	case FormatJPEG:
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatWebP:
+		return "image/webp", nativewebp.Encode(w, img, nil)
+	default:
+		return "", fmt.Errorf("format encode tidak didukung: %s", format)
+	}
+}
@@ -0,0 +1,97 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextStyle mengatur tampilan teks di atas gambar.
+type TextStyle struct {
+	Size  float64 // tinggi font dalam pixel
+	Bold  bool
+	Color color.Color
+	Align string // left (default), center, right
+}
+
+var (
+	fontsOnce sync.Once
+	fonts     map[bool]*opentype.Font
+	fontsErr  error
+	faces     sync.Map // faceKey -> font.Face
+	textMu    sync.Mutex
+)
+
+type faceKey struct {
+	bold bool
+	size float64
+}
+
+func face(bold bool, size float64) (font.Face, error) {
+	fontsOnce.Do(func() {
+		fonts = map[bool]*opentype.Font{}
+		for bold, ttf := range map[bool][]byte{false: goregular.TTF, true: gobold.TTF} {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				fontsErr = err
+				return
+			}
+			fonts[bold] = f
+		}
+	})
+	if fontsErr != nil {
+		return nil, fontsErr
+	}
+
+	key := faceKey{bold, size}
+	if cached, ok := faces.Load(key); ok {
+		return cached.(font.Face), nil
+	}
+	f, err := opentype.NewFace(fonts[bold], &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := faces.LoadOrStore(key, f)
+	return actual.(font.Face), nil
+}
+
+// DrawText menulis satu baris teks dengan sisi atas di y. x dan width
+// menentukan kotak horizontal untuk perataan center/right.
+//
+// font.Face tidak aman dipakai bersamaan, jadi penggambaran diserialkan.
+func DrawText(dst draw.Image, text string, x, y, width int, style TextStyle) error {
+	if style.Size <= 0 {
+		return fmt.Errorf("ukuran font harus lebih dari 0")
+	}
+	f, err := face(style.Bold, style.Size)
+	if err != nil {
+		return err
+	}
+	col := style.Color
+	if col == nil {
+		col = color.Black
+	}
+
+	textMu.Lock()
+	defer textMu.Unlock()
+
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(col), Face: f}
+	advance := d.MeasureString(text).Round()
+	switch style.Align {
+	case "center":
+		x += (width - advance) / 2
+	case "right":
+		x += width - advance
+	}
+	d.Dot = fixed.P(x, y+f.Metrics().Ascent.Round())
+	d.DrawString(text)
+	return nil
+}
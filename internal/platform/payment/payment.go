@@ -0,0 +1,89 @@
+// Package payment adalah penghubung ke payment gateway. Booth menampilkan QR
+// dari CreateCharge, lalu gateway memberi tahu hasil pembayaran lewat webhook
+// yang diverifikasi VerifyWebhook sebelum sesi ditandai lunas.
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("payment provider tidak dikenal")
+	ErrInvalidSignature = errors.New("tanda tangan webhook tidak valid")
+	ErrInvalidPayload   = errors.New("isi webhook tidak bisa dibaca")
+	ErrProvider         = errors.New("payment gateway menolak permintaan")
+)
+
+// Provider yang tersedia.
+const (
+	ProviderQRIS = "qris"
+)
+
+// ChargeStatus adalah status tagihan di sisi gateway.
+type ChargeStatus string
+
+const (
+	ChargePending ChargeStatus = "pending"
+	ChargePaid    ChargeStatus = "paid"
+	ChargeExpired ChargeStatus = "expired"
+	ChargeFailed  ChargeStatus = "failed"
+)
+
+// ChargeRequest adalah tagihan untuk satu sesi. Amount dalam rupiah bulat
+// karena QRIS tidak mengenal sen.
+type ChargeRequest struct {
+	OrderID   string
+	Amount    int64
+	ExpiresIn time.Duration
+}
+
+// Charge adalah tagihan yang sudah dibuat gateway. QRString adalah payload
+// QRIS yang dirender booth menjadi QR di layar.
+type Charge struct {
+	ID        string
+	OrderID   string
+	Amount    int64
+	Status    ChargeStatus
+	QRString  string
+	ExpiresAt time.Time
+}
+
+// Notification adalah isi webhook yang sudah lolos verifikasi tanda tangan.
+type Notification struct {
+	ChargeID string
+	OrderID  string
+	Amount   int64
+	Status   ChargeStatus
+	PaidAt   *time.Time
+}
+
+type Provider interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	// VerifyWebhook memeriksa tanda tangan lalu membaca isi webhook. Webhook
+	// yang tidak valid dikembalikan sebagai ErrInvalidSignature, isi yang
+	// tidak bisa dibaca sebagai ErrInvalidPayload.
+	VerifyWebhook(header http.Header, body []byte) (*Notification, error)
+}
+
+// Config memilih dan mengatur provider. ServerKey dipakai saat memanggil
+// API gateway, WebhookSecret untuk memverifikasi webhook dari gateway.
+type Config struct {
+	Provider      string
+	BaseURL       string
+	ServerKey     string
+	WebhookSecret string
+}
+
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderQRIS:
+		return NewQRISProvider(cfg.BaseURL, cfg.ServerKey, cfg.WebhookSecret)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, cfg.Provider)
+	}
+}
@@ -0,0 +1,157 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header webhook QRIS. Tanda tangannya HMAC-SHA256 dari "<timestamp>.<body>"
+// dengan webhook secret, jadi body yang diubah atau dikirim ulang lama
+// setelahnya akan ditolak.
+const (
+	HeaderSignature = "X-Signature"
+	HeaderTimestamp = "X-Timestamp"
+
+	// webhookTolerance adalah selisih waktu maksimum webhook dengan jam server.
+	webhookTolerance = 5 * time.Minute
+)
+
+// qrisProvider bicara ke API QRIS dinamis gateway lewat JSON: POST
+// /v1/qris/charges membuat tagihan, hasil bayar datang lewat webhook.
+type qrisProvider struct {
+	baseURL       string
+	serverKey     string
+	webhookSecret string
+	client        *http.Client
+}
+
+// NewQRISProvider membuat provider QRIS. baseURL adalah alamat API gateway,
+// tanpa path /v1.
+func NewQRISProvider(baseURL, serverKey, webhookSecret string) (Provider, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("URL payment gateway tidak valid: %q", baseURL)
+	}
+	if webhookSecret == "" {
+		return nil, fmt.Errorf("webhook secret QRIS wajib diisi")
+	}
+	return &qrisProvider{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		serverKey:     serverKey,
+		webhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (p *qrisProvider) Name() string {
+	return ProviderQRIS
+}
+
+// qrisCharge adalah bentuk tagihan di API gateway, dipakai juga oleh stub di test.
+type qrisCharge struct {
+	ID        string       `json:"id"`
+	OrderID   string       `json:"order_id"`
+	Amount    int64        `json:"amount"`
+	Status    ChargeStatus `json:"status"`
+	QRString  string       `json:"qr_string,omitempty"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	PaidAt    *time.Time   `json:"paid_at,omitempty"`
+}
+
+type qrisChargeRequest struct {
+	OrderID   string `json:"order_id"`
+	Amount    int64  `json:"amount"`
+	ExpiresIn int    `json:"expires_in"` // detik
+}
+
+func (p *qrisProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	body, err := json.Marshal(qrisChargeRequest{
+		OrderID:   req.OrderID,
+		Amount:    req.Amount,
+		ExpiresIn: int(req.ExpiresIn / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/qris/charges", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Idempotency-Key", req.OrderID)
+	if p.serverKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.serverKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghubungi payment gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%w: HTTP %d: %s", ErrProvider, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var charge qrisCharge
+	if err := json.Unmarshal(raw, &charge); err != nil {
+		return nil, fmt.Errorf("%w: respons tidak bisa dibaca: %w", ErrProvider, err)
+	}
+	return &Charge{
+		ID:        charge.ID,
+		OrderID:   charge.OrderID,
+		Amount:    charge.Amount,
+		Status:    charge.Status,
+		QRString:  charge.QRString,
+		ExpiresAt: charge.ExpiresAt,
+	}, nil
+}
+
+func (p *qrisProvider) VerifyWebhook(header http.Header, body []byte) (*Notification, error) {
+	ts, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: timestamp tidak ada", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return nil, fmt.Errorf("%w: timestamp di luar toleransi", ErrInvalidSignature)
+	}
+
+	expected := SignWebhook(p.webhookSecret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(HeaderSignature))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var charge qrisCharge
+	if err := json.Unmarshal(body, &charge); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	return &Notification{
+		ChargeID: charge.ID,
+		OrderID:  charge.OrderID,
+		Amount:   charge.Amount,
+		Status:   charge.Status,
+		PaidAt:   charge.PaidAt,
+	}, nil
+}
+
+// SignWebhook menghitung tanda tangan webhook QRIS (hex HMAC-SHA256).
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,258 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// qrisStub adalah payment gateway QRIS palsu untuk menguji alur bayar tanpa
+// gateway sungguhan. Tagihan disimpan di memori; pembayaran
+// disimulasikan lewat POST /v1/qris/charges/{id}/pay, lalu webhook bertanda
+// tangan dikirim ke WebhookURL.
+type qrisStub struct {
+	ServerKey     string // kosong = tanpa autentikasi
+	WebhookURL    string
+	WebhookSecret string
+	MerchantName  string
+	MerchantCity  string
+
+	once    sync.Once
+	mux     *http.ServeMux
+	mu      sync.Mutex
+	charges map[string]*qrisCharge
+	orders  map[string]string // order_id -> charge id
+	client  *http.Client
+}
+
+func (s *qrisStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.once.Do(func() {
+		s.charges = map[string]*qrisCharge{}
+		s.orders = map[string]string{}
+		s.client = &http.Client{Timeout: 10 * time.Second}
+		s.mux = http.NewServeMux()
+		s.mux.HandleFunc("POST /v1/qris/charges", s.create)
+		s.mux.HandleFunc("GET /v1/qris/charges/{id}", s.get)
+		s.mux.HandleFunc("POST /v1/qris/charges/{id}/pay", s.settle(ChargePaid))
+		s.mux.HandleFunc("POST /v1/qris/charges/{id}/expire", s.settle(ChargeExpired))
+		s.mux.HandleFunc("POST /v1/qris/charges/{id}/fail", s.settle(ChargeFailed))
+	})
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized mengecek server key untuk API merchant. Endpoint simulasi
+// (pay/expire/fail) mewakili pelanggan, jadi tidak butuh server key.
+func (s *qrisStub) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if s.ServerKey != "" && r.Header.Get("Authorization") != "Bearer "+s.ServerKey {
+		writeStubJSON(w, http.StatusUnauthorized, map[string]string{"error": "server key salah"})
+		return false
+	}
+	return true
+}
+
+func (s *qrisStub) create(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	var req qrisChargeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OrderID == "" || req.Amount <= 0 {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "order_id dan amount wajib diisi"})
+		return
+	}
+	if req.ExpiresIn <= 0 {
+		req.ExpiresIn = 900
+	}
+
+	s.mu.Lock()
+	if id, ok := s.orders[req.OrderID]; ok {
+		// order_id yang sama dianggap retry, tagihan lama dikembalikan
+		charge := *s.charges[id]
+		s.mu.Unlock()
+		writeStubJSON(w, http.StatusOK, charge)
+		return
+	}
+
+	charge := &qrisCharge{
+		ID:        "qris_" + randomHex(8),
+		OrderID:   req.OrderID,
+		Amount:    req.Amount,
+		Status:    ChargePending,
+		ExpiresAt: time.Now().Add(time.Duration(req.ExpiresIn) * time.Second).UTC().Truncate(time.Second),
+	}
+	charge.QRString = emvPayload(s.merchantName(), s.merchantCity(), req.OrderID, req.Amount)
+	s.charges[charge.ID] = charge
+	s.orders[req.OrderID] = charge.ID
+	created := *charge
+	s.mu.Unlock()
+
+	slog.Info("Tagihan QRIS dibuat", "id", created.ID, "order_id", created.OrderID, "amount", created.Amount)
+	time.AfterFunc(time.Until(created.ExpiresAt), func() { s.finish(created.ID, ChargeExpired) })
+	writeStubJSON(w, http.StatusCreated, created)
+}
+
+func (s *qrisStub) get(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	s.mu.Lock()
+	charge, ok := s.charges[r.PathValue("id")]
+	var out qrisCharge
+	if ok {
+		out = *charge
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeStubJSON(w, http.StatusNotFound, map[string]string{"error": "tagihan tidak ditemukan"})
+		return
+	}
+	writeStubJSON(w, http.StatusOK, out)
+}
+
+// settle mensimulasikan pelanggan membayar (atau tagihan kedaluwarsa/gagal).
+func (s *qrisStub) settle(status ChargeStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		charge, err := s.finish(r.PathValue("id"), status)
+		if err != nil {
+			writeStubJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		writeStubJSON(w, http.StatusOK, charge)
+	}
+}
+
+// finish mengakhiri tagihan pending lalu mengirim webhook-nya.
+func (s *qrisStub) finish(id string, status ChargeStatus) (*qrisCharge, error) {
+	s.mu.Lock()
+	charge, ok := s.charges[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("tagihan %s tidak ditemukan", id)
+	}
+	if charge.Status != ChargePending {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("tagihan %s sudah %s", id, charge.Status)
+	}
+	charge.Status = status
+	if status == ChargePaid {
+		now := time.Now().UTC().Truncate(time.Second)
+		charge.PaidAt = &now
+	}
+	out := *charge
+	s.mu.Unlock()
+
+	slog.Info("Tagihan QRIS selesai", "id", out.ID, "status", out.Status)
+	go s.sendWebhook(out)
+	return &out, nil
+}
+
+// sendWebhook mengirim webhook bertanda tangan, dicoba ulang beberapa kali
+// seperti gateway sungguhan kalau server belum membalas 2xx.
+func (s *qrisStub) sendWebhook(charge qrisCharge) {
+	if s.WebhookURL == "" {
+		return
+	}
+	body, _ := json.Marshal(charge)
+
+	for attempt, delay := 1, time.Second; attempt <= 5; attempt, delay = attempt+1, delay*2 {
+		ts := time.Now().Unix()
+		req, _ := http.NewRequest(http.MethodPost, s.WebhookURL, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, SignWebhook(s.WebhookSecret, ts, body))
+
+		resp, err := s.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode/100 == 2 {
+				slog.Info("Webhook QRIS terkirim", "id", charge.ID, "status", charge.Status)
+				return
+			}
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		slog.Warn("Webhook QRIS gagal, dicoba lagi", "id", charge.ID, "attempt", attempt, "error", err)
+		time.Sleep(delay)
+	}
+	slog.Error("Webhook QRIS menyerah", "id", charge.ID)
+}
+
+func (s *qrisStub) merchantName() string {
+	if s.MerchantName != "" {
+		return s.MerchantName
+	}
+	return "PHOTOBOOTH DEV"
+}
+
+func (s *qrisStub) merchantCity() string {
+	if s.MerchantCity != "" {
+		return s.MerchantCity
+	}
+	return "JAKARTA"
+}
+
+// emvPayload menyusun payload QR dinamis berformat EMVCo seperti QRIS: field
+// TLV dengan nominal (54) dan nomor tagihan (62/01), ditutup CRC16 (63).
+func emvPayload(merchant, city, orderID string, amount int64) string {
+	tlv := func(id, value string) string {
+		return fmt.Sprintf("%s%02d%s", id, len(value), value)
+	}
+	if len(orderID) > 25 {
+		orderID = orderID[:25]
+	}
+
+	payload := tlv("00", "01") +
+		tlv("01", "12") + // 12 = QR dinamis, sekali pakai
+		tlv("26", tlv("00", "ID.CO.FAKEQRIS.WWW")+tlv("01", "9360000000000000001")) +
+		tlv("52", "7221") + // MCC studio foto
+		tlv("53", "360") + // IDR
+		tlv("54", strconv.FormatInt(amount, 10)) +
+		tlv("58", "ID") +
+		tlv("59", truncateField(merchant, 25)) +
+		tlv("60", truncateField(city, 15)) +
+		tlv("62", tlv("01", orderID)) +
+		"6304"
+	return payload + fmt.Sprintf("%04X", crc16CCITT([]byte(payload)))
+}
+
+func truncateField(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// crc16CCITT adalah CRC16/CCITT-FALSE (poly 0x1021, init 0xFFFF) sesuai EMVCo.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,147 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec-test"
+
+func TestQRISChargeAndWebhook(t *testing.T) {
+	// Server kita: menerima webhook dari stub dan memverifikasinya
+	var provider Provider
+	notifs := make(chan *Notification, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		notif, err := provider.VerifyWebhook(r.Header, body)
+		if err != nil {
+			t.Errorf("VerifyWebhook: %v", err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		notifs <- notif
+	}))
+	defer receiver.Close()
+
+	gateway := httptest.NewServer(&qrisStub{ServerKey: "sk-test", WebhookURL: receiver.URL, WebhookSecret: testWebhookSecret})
+	defer gateway.Close()
+
+	provider, err := NewQRISProvider(gateway.URL, "sk-test", testWebhookSecret)
+	if err != nil {
+		t.Fatalf("NewQRISProvider: %v", err)
+	}
+	ctx := context.Background()
+
+	charge, err := provider.CreateCharge(ctx, ChargeRequest{OrderID: "order-1", Amount: 35000, ExpiresIn: time.Minute})
+	if err != nil {
+		t.Fatalf("CreateCharge: %v", err)
+	}
+	if charge.Status != ChargePending || charge.Amount != 35000 || charge.OrderID != "order-1" {
+		t.Fatalf("charge = %+v", charge)
+	}
+	if !strings.Contains(charge.QRString, "540535000") {
+		t.Fatalf("QR tanpa nominal: %q", charge.QRString)
+	}
+	payload, crc := charge.QRString[:len(charge.QRString)-4], charge.QRString[len(charge.QRString)-4:]
+	if want := fmt.Sprintf("%04X", crc16CCITT([]byte(payload))); crc != want {
+		t.Fatalf("CRC QR = %s, mau %s", crc, want)
+	}
+
+	// order_id yang sama adalah retry, bukan tagihan baru
+	again, err := provider.CreateCharge(ctx, ChargeRequest{OrderID: "order-1", Amount: 35000, ExpiresIn: time.Minute})
+	if err != nil {
+		t.Fatalf("CreateCharge retry: %v", err)
+	}
+	if again.ID != charge.ID {
+		t.Fatalf("retry membuat tagihan baru: %s != %s", again.ID, charge.ID)
+	}
+
+	resp, err := http.Post(gateway.URL+"/v1/qris/charges/"+charge.ID+"/pay", "application/json", nil)
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("pay HTTP %d", resp.StatusCode)
+	}
+
+	select {
+	case notif := <-notifs:
+		if notif.ChargeID != charge.ID || notif.OrderID != "order-1" || notif.Amount != 35000 {
+			t.Fatalf("notifikasi = %+v", notif)
+		}
+		if notif.Status != ChargePaid || notif.PaidAt == nil {
+			t.Fatalf("notifikasi belum lunas: %+v", notif)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook tidak datang")
+	}
+}
+
+func TestQRISCreateChargeRejected(t *testing.T) {
+	gateway := httptest.NewServer(&qrisStub{ServerKey: "sk-test", WebhookSecret: testWebhookSecret})
+	defer gateway.Close()
+
+	provider, _ := NewQRISProvider(gateway.URL, "sk-salah", testWebhookSecret)
+	_, err := provider.CreateCharge(context.Background(), ChargeRequest{OrderID: "order-1", Amount: 35000})
+	if !errors.Is(err, ErrProvider) || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Fatalf("err = %v, mau ErrProvider HTTP 401", err)
+	}
+}
+
+func TestQRISWebhookInvalidSignature(t *testing.T) {
+	provider, _ := NewQRISProvider("http://gateway.local", "", testWebhookSecret)
+	body := []byte(`{"id":"qris_1","order_id":"order-1","amount":35000,"status":"paid"}`)
+	now := time.Now().Unix()
+
+	signed := func(secret string, ts int64, body []byte) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		h.Set(HeaderSignature, SignWebhook(secret, ts, body))
+		return h
+	}
+
+	if _, err := provider.VerifyWebhook(signed(testWebhookSecret, now, body), body); err != nil {
+		t.Fatalf("webhook valid ditolak: %v", err)
+	}
+
+	tampered := []byte(strings.Replace(string(body), "35000", "1000", 1))
+	retimed := signed(testWebhookSecret, now, body)
+	retimed.Set(HeaderTimestamp, strconv.FormatInt(now-1, 10))
+	cases := map[string]struct {
+		header http.Header
+		body   []byte
+	}{
+		"secret salah":     {signed("secret-lain", now, body), body},
+		"body diubah":      {signed(testWebhookSecret, now, body), tampered},
+		"timestamp lama":   {signed(testWebhookSecret, now-3600, body), body},
+		"timestamp diubah": {retimed, body},
+		"tanpa header":     {http.Header{}, body},
+	}
+	for name, c := range cases {
+		if _, err := provider.VerifyWebhook(c.header, c.body); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, mau ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestQRISWebhookUnreadablePayload(t *testing.T) {
+	provider, _ := NewQRISProvider("http://gateway.local", "", testWebhookSecret)
+	body := []byte(`{"id":"qris_1",`)
+	ts := time.Now().Unix()
+
+	h := http.Header{}
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	h.Set(HeaderSignature, SignWebhook(testWebhookSecret, ts, body))
+	if _, err := provider.VerifyWebhook(h, body); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("err = %v, mau ErrInvalidPayload", err)
+	}
+}
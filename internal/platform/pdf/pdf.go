@@ -0,0 +1,216 @@
+// Package pdf adalah penulis PDF minimal untuk dokumen cetak: halaman
+// berukuran bebas berisi gambar JPEG dan teks. Tidak ada kompresi stream dan
+// tidak ada font embed (hanya font standar PDF), cukup untuk lembar cetak
+// yang dibaca driver printer dan struk sederhana.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PointsPerInch adalah satuan ukuran halaman PDF.
+const PointsPerInch = 72.0
+
+// Font adalah salah satu font standar PDF yang pasti ada di semua viewer.
+type Font string
+
+const (
+	Courier       Font = "Courier"
+	CourierBold   Font = "Courier-Bold"
+	Helvetica     Font = "Helvetica"
+	HelveticaBold Font = "Helvetica-Bold"
+)
+
+// CourierAdvance adalah lebar satu karakter Courier relatif terhadap ukuran font.
+const CourierAdvance = 0.6
+
+// Document adalah kumpulan halaman yang ditulis sekaligus lewat WriteTo.
+type Document struct {
+	pages []*Page
+}
+
+// Page adalah satu halaman, ukuran dalam point (1/72 inch). Titik (0,0)
+// ada di kiri bawah sesuai koordinat PDF.
+type Page struct {
+	Width   float64
+	Height  float64
+	content bytes.Buffer
+	images  []jpegImage
+	fonts   []Font
+}
+
+type jpegImage struct {
+	data          []byte
+	width, height int
+	gray          bool
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+func (d *Document) AddPage(width, height float64) *Page {
+	p := &Page{Width: width, Height: height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// DrawJPEG menaruh JPEG (RGB atau grayscale) di kotak x,y,w,h dalam point.
+// Data JPEG disimpan apa adanya (DCTDecode), tidak di-encode ulang.
+func (p *Page) DrawJPEG(data []byte, pxWidth, pxHeight int, gray bool, x, y, w, h float64) {
+	name := fmt.Sprintf("Im%d", len(p.images))
+	p.images = append(p.images, jpegImage{data, pxWidth, pxHeight, gray})
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n", num(w), num(h), num(x), num(y), name)
+}
+
+// Text menulis satu baris teks dengan baseline di x,y. Karakter di luar
+// Latin-1 diganti '?' karena font standar memakai WinAnsiEncoding.
+func (p *Page) Text(font Font, size, x, y float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", p.fontName(font), num(size), num(x), num(y), escapeText(text))
+}
+
+func (p *Page) fontName(font Font) string {
+	for i, f := range p.fonts {
+		if f == font {
+			return fmt.Sprintf("F%d", i)
+		}
+	}
+	p.fonts = append(p.fonts, font)
+	return fmt.Sprintf("F%d", len(p.fonts)-1)
+}
+
+func escapeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 sama dengan WinAnsi di rentang ini, ditulis sebagai oktal
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// WriteTo menulis dokumen lengkap dengan tabel xref.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	offsets := []int64{0} // objek 0 selalu kosong di xref
+
+	// Nomor objek: 1 catalog, 2 pages, lalu per halaman: page, content, gambar...
+	pageIDs := make([]int, len(d.pages))
+	next := 3
+	for i, p := range d.pages {
+		pageIDs[i] = next
+		next += 2 + len(p.images)
+	}
+
+	begin := func(id int) {
+		for len(offsets) <= id {
+			offsets = append(offsets, 0)
+		}
+		offsets[id] = cw.n
+		fmt.Fprintf(cw, "%d 0 obj\n", id)
+	}
+	end := func() { io.WriteString(cw, "\nendobj\n") }
+
+	io.WriteString(cw, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	begin(1)
+	io.WriteString(cw, "<< /Type /Catalog /Pages 2 0 R >>")
+	end()
+
+	begin(2)
+	io.WriteString(cw, "<< /Type /Pages /Kids [")
+	for _, id := range pageIDs {
+		fmt.Fprintf(cw, "%d 0 R ", id)
+	}
+	fmt.Fprintf(cw, "] /Count %d >>", len(d.pages))
+	end()
+
+	for i, p := range d.pages {
+		id := pageIDs[i]
+		begin(id)
+		fmt.Fprintf(cw, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Contents %d 0 R /Resources << /XObject <<",
+			num(p.Width), num(p.Height), id+1)
+		for j := range p.images {
+			fmt.Fprintf(cw, " /Im%d %d 0 R", j, id+2+j)
+		}
+		io.WriteString(cw, " >>")
+		if len(p.fonts) > 0 {
+			io.WriteString(cw, " /Font <<")
+			for j, f := range p.fonts {
+				fmt.Fprintf(cw, " /F%d << /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", j, f)
+			}
+			io.WriteString(cw, " >>")
+		}
+		io.WriteString(cw, " >> >>")
+		end()
+
+		begin(id + 1)
+		fmt.Fprintf(cw, "<< /Length %d >>\nstream\n", p.content.Len())
+		cw.Write(p.content.Bytes())
+		io.WriteString(cw, "\nendstream")
+		end()
+
+		for j, img := range p.images {
+			begin(id + 2 + j)
+			colorSpace := "/DeviceRGB"
+			if img.gray {
+				colorSpace = "/DeviceGray"
+			}
+			fmt.Fprintf(cw, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n",
+				img.width, img.height, colorSpace, len(img.data))
+			cw.Write(img.data)
+			io.WriteString(cw, "\nendstream")
+			end()
+		}
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(cw, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// num menulis angka tanpa nol berlebih, PDF tidak menerima notasi eksponen.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"log/slog"
+	"photobooth-core/internal/domain"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MigrateTransactionStatus memindahkan sesi lama dari kolom payment_status
+// ke status sesi. Dulu semua sesi langsung dicatat "completed", jadi sesi
+// itu dianggap sudah selesai. Kolom lama dihapus supaya ini cuma jalan sekali.
+func MigrateTransactionStatus(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&domain.Transaction{}, "payment_status") {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Transaction{}).
+			Where("payment_status = ?", "completed").
+			Updates(map[string]interface{}{
+				"status":       domain.TransCompleted,
+				"paid_at":      gorm.Expr("created_at"),
+				"completed_at": gorm.Expr("updated_at"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		slog.Info("Status sesi lama dimigrasikan", "count", res.RowsAffected)
+		return tx.Migrator().DropColumn(&domain.Transaction{}, "payment_status")
+	})
+	if err != nil {
+		slog.Error("Gagal memigrasikan status sesi lama", "error", err)
+	}
+}
+
+// DropGlobalReferenceUnique melepas unique constraint lama di reference_no.
+// Sekarang reference_no unik per tenant (index idx_transactions_tenant_reference
+// dari AutoMigrate). Harus jalan sebelum AutoMigrate, supaya AutoMigrate tidak
+// mencoba menghapus constraint dengan nama yang berbeda dari versi gorm lama.
+func DropGlobalReferenceUnique(db *gorm.DB) {
+	for _, constraint := range []string{"uni_transactions_reference_no", "transactions_reference_no_key"} {
+		if err := db.Exec("ALTER TABLE IF EXISTS transactions DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			slog.Error("Gagal melepas unique reference_no lama", "constraint", constraint, "error", err)
+		}
+	}
+}
+
+// EnsureActiveSessionIndex memasang unique index parsial supaya satu booth
+// hanya punya satu sesi yang belum berakhir, termasuk saat dua request
+// mulai sesi datang bersamaan. Harus jalan setelah MigrateTransactionStatus.
+func EnsureActiveSessionIndex(db *gorm.DB) {
+	// DDL tidak menerima parameter, status ditulis langsung (semuanya konstanta)
+	statuses := make([]string, len(domain.ActiveStatuses))
+	for i, status := range domain.ActiveStatuses {
+		statuses[i] = "'" + string(status) + "'"
+	}
+	err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_active_booth ON transactions (booth_id) WHERE status IN (" +
+		strings.Join(statuses, ", ") + ")").Error
+	if err != nil {
+		slog.Error("Gagal membuat index sesi aktif per booth, tutup dulu sesi ganda yang masih terbuka", "error", err)
+	}
+}
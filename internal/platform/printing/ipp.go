@@ -0,0 +1,402 @@
+package printing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Operasi dan tag IPP/1.1 (RFC 8011) yang dipakai driver ini.
+const (
+	opPrintJob             = 0x0002
+	opGetJobAttributes     = 0x0009
+	opGetPrinterAttributes = 0x000B
+
+	tagOperation   = 0x01
+	tagJob         = 0x02
+	tagEnd         = 0x03
+	tagPrinter     = 0x04
+	tagUnsupported = 0x05
+
+	valInteger  = 0x21
+	valBoolean  = 0x22
+	valEnum     = 0x23
+	valText     = 0x41
+	valName     = 0x42
+	valKeyword  = 0x44
+	valURI      = 0x45
+	valCharset  = 0x47
+	valLanguage = 0x48
+	valMimeType = 0x49
+)
+
+var ErrIPP = errors.New("printer IPP menolak permintaan")
+
+// ippPrinter bicara IPP langsung lewat HTTP, jadi jalan dengan CUPS maupun
+// printer jaringan yang mendukung IPP Everywhere tanpa library tambahan.
+type ippPrinter struct {
+	printerURI string
+	endpoint   string
+	userName   string
+	client     *http.Client
+	requestID  atomic.Int32
+}
+
+// NewIPPPrinter membuat driver IPP. uri boleh ipp://, ipps://, http:// atau https://.
+func NewIPPPrinter(uri, userName string) (Printer, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("URI printer IPP tidak valid: %q", uri)
+	}
+
+	endpoint := *u
+	switch u.Scheme {
+	case "ipp":
+		endpoint.Scheme = "http"
+		if u.Port() == "" {
+			endpoint.Host = u.Hostname() + ":631"
+		}
+	case "ipps":
+		endpoint.Scheme = "https"
+		if u.Port() == "" {
+			endpoint.Host = u.Hostname() + ":443"
+		}
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("skema URI printer IPP tidak didukung: %s", u.Scheme)
+	}
+	if userName == "" {
+		userName = "photobooth"
+	}
+
+	return &ippPrinter{
+		printerURI: uri,
+		endpoint:   endpoint.String(),
+		userName:   userName,
+		client:     &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (p *ippPrinter) Submit(ctx context.Context, job Job) (string, error) {
+	req := p.newRequest(opPrintJob)
+	req.operation("job-name", valName, job.Name)
+	req.operation("document-format", valMimeType, job.ContentType)
+	if job.Copies > 1 {
+		req.job("copies", valInteger, job.Copies)
+	}
+	if job.Media != "" {
+		req.job("media", valKeyword, job.Media)
+	}
+
+	resp, err := p.do(ctx, req, job.Document)
+	if err != nil {
+		return "", err
+	}
+	id, ok := resp.integer(tagJob, "job-id")
+	if !ok {
+		return "", fmt.Errorf("%w: respons Print-Job tanpa job-id", ErrIPP)
+	}
+	return strconv.Itoa(id), nil
+}
+
+func (p *ippPrinter) JobStatus(ctx context.Context, id string) (*JobStatus, error) {
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	req := p.newRequest(opGetJobAttributes)
+	req.operation("job-id", valInteger, jobID)
+	req.operation("requested-attributes", valKeyword, "job-state", "job-state-reasons")
+
+	resp, err := p.do(ctx, req, nil)
+	if err != nil {
+		// client-error-not-found
+		if resp != nil && resp.status == 0x0406 {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	state, _ := resp.integer(tagJob, "job-state")
+	return &JobStatus{ID: id, State: jobStates[state], Reasons: resp.strings(tagJob, "job-state-reasons")}, nil
+}
+
+func (p *ippPrinter) Status(ctx context.Context) (*Status, error) {
+	req := p.newRequest(opGetPrinterAttributes)
+	req.operation("requested-attributes", valKeyword,
+		"printer-state", "printer-state-reasons", "printer-is-accepting-jobs", "media-ready", "marker-names", "marker-levels")
+
+	resp, err := p.do(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	state, _ := resp.integer(tagPrinter, "printer-state")
+	status := &Status{
+		State:     printerStates[state],
+		Accepting: resp.boolean(tagPrinter, "printer-is-accepting-jobs"),
+		Reasons:   resp.strings(tagPrinter, "printer-state-reasons"),
+		Media:     resp.strings(tagPrinter, "media-ready"),
+	}
+	if status.State == "" {
+		status.State = "unknown"
+	}
+
+	names := resp.strings(tagPrinter, "marker-names")
+	levels := resp.integers(tagPrinter, "marker-levels")
+	if len(names) > 0 && len(names) == len(levels) {
+		status.Markers = make(map[string]int, len(names))
+		for i, name := range names {
+			status.Markers[name] = levels[i]
+		}
+	}
+	return status, nil
+}
+
+var jobStates = map[int]JobState{
+	3: JobPending, 4: JobHeld, 5: JobProcessing, 6: JobStopped,
+	7: JobCanceled, 8: JobAborted, 9: JobCompleted,
+}
+
+var printerStates = map[int]string{3: "idle", 4: "processing", 5: "stopped"}
+
+func (p *ippPrinter) newRequest(op uint16) *ippMessage {
+	m := &ippMessage{code: op, requestID: p.requestID.Add(1)}
+	m.operation("attributes-charset", valCharset, "utf-8")
+	m.operation("attributes-natural-language", valLanguage, "en")
+	m.operation("printer-uri", valURI, p.printerURI)
+	m.operation("requesting-user-name", valName, p.userName)
+	return m
+}
+
+// do mengirim request IPP (plus dokumen kalau ada) dan mem-parse responsnya.
+// Respons tetap dikembalikan bersama error status supaya pemanggil bisa cek kodenya.
+func (p *ippPrinter) do(ctx context.Context, req *ippMessage, document io.Reader) (*ippMessage, error) {
+	var body io.Reader = bytes.NewReader(req.encode())
+	if document != nil {
+		body = io.MultiReader(body, document)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ipp")
+
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("printer IPP tidak bisa dihubungi: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %d", ErrIPP, httpResp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := decodeIPP(raw)
+	if err != nil {
+		return nil, err
+	}
+	// 0x0000-0x00FF adalah successful-ok dan variasinya
+	if resp.status > 0x00FF {
+		msg, _ := resp.first(tagOperation, "status-message")
+		return resp, fmt.Errorf("%w: status 0x%04x %s", ErrIPP, resp.status, msg)
+	}
+	return resp, nil
+}
+
+// ippMessage adalah request atau response IPP. Di request, code adalah
+// operation-id; di response, code adalah status-code.
+type ippMessage struct {
+	code      uint16
+	status    uint16
+	requestID int32
+	attrs     []ippAttribute
+}
+
+type ippAttribute struct {
+	group  byte
+	name   string
+	tag    byte
+	values [][]byte
+}
+
+func (m *ippMessage) operation(name string, tag byte, values ...any) {
+	m.add(tagOperation, name, tag, values)
+}
+
+func (m *ippMessage) job(name string, tag byte, values ...any) {
+	m.add(tagJob, name, tag, values)
+}
+
+func (m *ippMessage) add(group byte, name string, tag byte, values []any) {
+	attr := ippAttribute{group: group, name: name, tag: tag}
+	for _, v := range values {
+		switch v := v.(type) {
+		case int:
+			b := make([]byte, 4)
+			binary.BigEndian.PutUint32(b, uint32(int32(v)))
+			attr.values = append(attr.values, b)
+		case bool:
+			b := []byte{0}
+			if v {
+				b[0] = 1
+			}
+			attr.values = append(attr.values, b)
+		case string:
+			attr.values = append(attr.values, []byte(v))
+		}
+	}
+	m.attrs = append(m.attrs, attr)
+}
+
+func (m *ippMessage) encode() []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{1, 1}) // IPP/1.1, paling luas didukung
+	binary.Write(&buf, binary.BigEndian, m.code)
+	binary.Write(&buf, binary.BigEndian, m.requestID)
+
+	var group byte
+	for _, attr := range m.attrs {
+		if attr.group != group {
+			buf.WriteByte(attr.group)
+			group = attr.group
+		}
+		for i, v := range attr.values {
+			name := attr.name
+			if i > 0 {
+				name = "" // nilai tambahan (1setOf) ditulis tanpa nama
+			}
+			buf.WriteByte(attr.tag)
+			binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+			buf.WriteString(name)
+			binary.Write(&buf, binary.BigEndian, uint16(len(v)))
+			buf.Write(v)
+		}
+	}
+	buf.WriteByte(tagEnd)
+	return buf.Bytes()
+}
+
+// decodeIPP mem-parse response IPP. Koleksi (media-col dsb.) tidak diurai,
+// anggotanya ikut tersimpan sebagai nilai tanpa nama dan diabaikan.
+func decodeIPP(raw []byte) (*ippMessage, error) {
+	if len(raw) < 8 {
+		return nil, fmt.Errorf("%w: respons terlalu pendek", ErrIPP)
+	}
+	m := &ippMessage{
+		status:    binary.BigEndian.Uint16(raw[2:4]),
+		requestID: int32(binary.BigEndian.Uint32(raw[4:8])),
+	}
+
+	pos := 8
+	var group byte
+	var current *ippAttribute
+	for pos < len(raw) {
+		tag := raw[pos]
+		pos++
+		if tag == tagEnd {
+			return m, nil
+		}
+		if tag < 0x10 {
+			group = tag
+			current = nil
+			continue
+		}
+
+		if pos+2 > len(raw) {
+			break
+		}
+		nameLen := int(binary.BigEndian.Uint16(raw[pos:]))
+		pos += 2
+		if pos+nameLen+2 > len(raw) {
+			break
+		}
+		name := string(raw[pos : pos+nameLen])
+		pos += nameLen
+		valueLen := int(binary.BigEndian.Uint16(raw[pos:]))
+		pos += 2
+		if pos+valueLen > len(raw) {
+			break
+		}
+		value := raw[pos : pos+valueLen]
+		pos += valueLen
+
+		if name != "" {
+			m.attrs = append(m.attrs, ippAttribute{group: group, name: name, tag: tag})
+			current = &m.attrs[len(m.attrs)-1]
+		}
+		if current != nil {
+			current.values = append(current.values, value)
+		}
+	}
+	return nil, fmt.Errorf("%w: respons terpotong", ErrIPP)
+}
+
+func (m *ippMessage) find(group byte, name string) *ippAttribute {
+	for i := range m.attrs {
+		if m.attrs[i].group == group && m.attrs[i].name == name {
+			return &m.attrs[i]
+		}
+	}
+	return nil
+}
+
+func (m *ippMessage) first(group byte, name string) (string, bool) {
+	values := m.strings(group, name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (m *ippMessage) strings(group byte, name string) []string {
+	attr := m.find(group, name)
+	if attr == nil {
+		return nil
+	}
+	out := make([]string, 0, len(attr.values))
+	for _, v := range attr.values {
+		out = append(out, string(v))
+	}
+	return out
+}
+
+func (m *ippMessage) integers(group byte, name string) []int {
+	attr := m.find(group, name)
+	if attr == nil {
+		return nil
+	}
+	out := make([]int, 0, len(attr.values))
+	for _, v := range attr.values {
+		if len(v) == 4 {
+			out = append(out, int(int32(binary.BigEndian.Uint32(v))))
+		}
+	}
+	return out
+}
+
+func (m *ippMessage) integer(group byte, name string) (int, bool) {
+	values := m.integers(group, name)
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[0], true
+}
+
+func (m *ippMessage) boolean(group byte, name string) bool {
+	attr := m.find(group, name)
+	return attr != nil && len(attr.values) > 0 && len(attr.values[0]) == 1 && attr.values[0][0] == 1
+}
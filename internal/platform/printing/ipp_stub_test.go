@@ -0,0 +1,163 @@
+package printing
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ippStub adalah printer IPP palsu untuk menguji driver tanpa CUPS.
+// Dokumen yang diterima disimpan di Dir; job dianggap selesai setelah
+// JobDuration. Sisa media berkurang satu per salinan.
+type ippStub struct {
+	Dir         string
+	JobDuration time.Duration
+	Media       string
+	MediaLeft   int
+
+	mu     sync.Mutex
+	nextID int
+	jobs   map[int]time.Time
+}
+
+func (s *ippStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/ipp" {
+		http.Error(w, "IPP only", http.StatusBadRequest)
+		return
+	}
+	raw, err := io.ReadAll(r.Body)
+	if err != nil || len(raw) < 8 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Atribut diakhiri tag end; sisanya adalah isi dokumen
+	end := attributesEnd(raw)
+	req, err := decodeIPP(raw[:end])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	op := binary.BigEndian.Uint16(raw[2:4])
+
+	resp := &ippMessage{requestID: req.requestID}
+	resp.operation("attributes-charset", valCharset, "utf-8")
+	resp.operation("attributes-natural-language", valLanguage, "en")
+
+	switch op {
+	case opPrintJob:
+		resp.code = s.printJob(req, raw[end:], resp)
+	case opGetJobAttributes:
+		resp.code = s.jobAttributes(req, resp)
+	case opGetPrinterAttributes:
+		resp.code = s.printerAttributes(resp)
+	default:
+		resp.code = 0x0501 // server-error-operation-not-supported
+	}
+
+	w.Header().Set("Content-Type", "application/ipp")
+	w.Write(resp.encode())
+}
+
+func (s *ippStub) printJob(req *ippMessage, document []byte, resp *ippMessage) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = map[int]time.Time{}
+	}
+
+	copies, ok := req.integer(tagJob, "copies")
+	if !ok {
+		copies = 1
+	}
+	if s.MediaLeft < copies {
+		resp.operation("status-message", valText, "media-empty")
+		return 0x0507 // server-error-not-accepting-jobs
+	}
+
+	s.nextID++
+	id := s.nextID
+	if s.Dir != "" {
+		path := filepath.Join(s.Dir, "job-"+strconv.Itoa(id))
+		if err := os.WriteFile(path, document, 0o644); err != nil {
+			slog.Error("Gagal menyimpan dokumen job", "error", err)
+			return 0x0500 // server-error-internal-error
+		}
+	}
+	s.jobs[id] = time.Now()
+	s.MediaLeft -= copies
+
+	name, _ := req.first(tagOperation, "job-name")
+	media, _ := req.first(tagJob, "media")
+	slog.Info("Job cetak diterima", "job_id", id, "name", name, "media", media, "copies", copies, "bytes", len(document))
+
+	resp.job("job-id", valInteger, id)
+	resp.job("job-state", valEnum, 3)
+	return 0
+}
+
+func (s *ippStub) jobAttributes(req *ippMessage, resp *ippMessage) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := req.integer(tagOperation, "job-id")
+	created, ok := s.jobs[id]
+	if !ok {
+		return 0x0406 // client-error-not-found
+	}
+	state := 5
+	if time.Since(created) >= s.JobDuration {
+		state = 9
+	}
+	resp.job("job-id", valInteger, id)
+	resp.job("job-state", valEnum, state)
+	resp.job("job-state-reasons", valKeyword, "none")
+	return 0
+}
+
+func (s *ippStub) printerAttributes(resp *ippMessage) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reason := "none"
+	if s.MediaLeft <= 0 {
+		reason = "media-empty-error"
+	}
+	resp.add(tagPrinter, "printer-state", valEnum, []any{3})
+	resp.add(tagPrinter, "printer-state-reasons", valKeyword, []any{reason})
+	resp.add(tagPrinter, "printer-is-accepting-jobs", valBoolean, []any{s.MediaLeft > 0})
+	resp.add(tagPrinter, "media-ready", valKeyword, []any{s.Media})
+	resp.add(tagPrinter, "marker-names", valName, []any{"ribbon"})
+	resp.add(tagPrinter, "marker-levels", valInteger, []any{min(100, s.MediaLeft*100/max(1, 700))})
+	return 0
+}
+
+// attributesEnd mencari posisi setelah tag end di request IPP.
+func attributesEnd(raw []byte) int {
+	pos := 8
+	for pos < len(raw) {
+		tag := raw[pos]
+		pos++
+		if tag == tagEnd {
+			return pos
+		}
+		if tag < 0x10 {
+			continue
+		}
+		if pos+2 > len(raw) {
+			break
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(raw[pos:]))
+		if pos+2 > len(raw) {
+			break
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(raw[pos:]))
+	}
+	return len(raw)
+}
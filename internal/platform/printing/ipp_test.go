@@ -0,0 +1,203 @@
+package printing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newStubPrinter(t *testing.T, stub *ippStub) Printer {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	p, err := NewIPPPrinter(srv.URL+"/printers/stub", "")
+	if err != nil {
+		t.Fatalf("NewIPPPrinter: %v", err)
+	}
+	return p
+}
+
+func TestIPPSubmitAndJobStatus(t *testing.T) {
+	dir := t.TempDir()
+	stub := &ippStub{Dir: dir, JobDuration: time.Hour, Media: "na_index-4x6_4x6in", MediaLeft: 10}
+	p := newStubPrinter(t, stub)
+	ctx := context.Background()
+
+	id, err := p.Submit(ctx, Job{
+		Name:        "session-1",
+		Document:    strings.NewReader("%PDF-1.4 foto"),
+		ContentType: "application/pdf",
+		Copies:      2,
+		Media:       "na_index-4x6_4x6in",
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if id != "1" {
+		t.Fatalf("job id = %q, mau 1", id)
+	}
+
+	// Dokumen dikirim utuh setelah atribut, salinan mengurangi media
+	doc, err := os.ReadFile(filepath.Join(dir, "job-1"))
+	if err != nil {
+		t.Fatalf("dokumen tidak tersimpan: %v", err)
+	}
+	if string(doc) != "%PDF-1.4 foto" {
+		t.Fatalf("isi dokumen = %q", doc)
+	}
+	if stub.MediaLeft != 8 {
+		t.Fatalf("sisa media = %d, mau 8", stub.MediaLeft)
+	}
+
+	status, err := p.JobStatus(ctx, id)
+	if err != nil {
+		t.Fatalf("JobStatus: %v", err)
+	}
+	if status.State != JobProcessing || status.State.Done() {
+		t.Fatalf("state = %q, mau processing", status.State)
+	}
+
+	stub.JobDuration = 0
+	status, err = p.JobStatus(ctx, id)
+	if err != nil {
+		t.Fatalf("JobStatus: %v", err)
+	}
+	if status.State != JobCompleted || !status.State.Done() {
+		t.Fatalf("state = %q, mau completed", status.State)
+	}
+	if len(status.Reasons) != 1 || status.Reasons[0] != "none" {
+		t.Fatalf("reasons = %v", status.Reasons)
+	}
+}
+
+func TestIPPPrinterStatus(t *testing.T) {
+	stub := &ippStub{Media: "na_5x7_5x7in", MediaLeft: 350}
+	p := newStubPrinter(t, stub)
+
+	status, err := p.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.State != "idle" || !status.Accepting {
+		t.Fatalf("status = %+v, mau idle dan menerima job", status)
+	}
+	if len(status.Media) != 1 || status.Media[0] != "na_5x7_5x7in" {
+		t.Fatalf("media = %v", status.Media)
+	}
+	if status.Markers["ribbon"] != 50 {
+		t.Fatalf("markers = %v, mau ribbon 50", status.Markers)
+	}
+
+	stub.MediaLeft = 0
+	status, err = p.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Accepting {
+		t.Fatal("printer tanpa media masih menerima job")
+	}
+	if len(status.Reasons) != 1 || status.Reasons[0] != "media-empty-error" {
+		t.Fatalf("reasons = %v", status.Reasons)
+	}
+}
+
+func TestIPPSubmitRejected(t *testing.T) {
+	p := newStubPrinter(t, &ippStub{MediaLeft: 1})
+
+	_, err := p.Submit(context.Background(), Job{
+		Name:        "session-2",
+		Document:    strings.NewReader("doc"),
+		ContentType: "application/pdf",
+		Copies:      3,
+	})
+	if !errors.Is(err, ErrIPP) {
+		t.Fatalf("err = %v, mau ErrIPP", err)
+	}
+	if !strings.Contains(err.Error(), "0x0507") || !strings.Contains(err.Error(), "media-empty") {
+		t.Fatalf("err = %v, mau status 0x0507 dengan pesan media-empty", err)
+	}
+}
+
+func TestIPPJobNotFound(t *testing.T) {
+	p := newStubPrinter(t, &ippStub{MediaLeft: 1})
+
+	for _, id := range []string{"42", "bukan-angka"} {
+		if _, err := p.JobStatus(context.Background(), id); !errors.Is(err, ErrJobNotFound) {
+			t.Fatalf("JobStatus(%q) err = %v, mau ErrJobNotFound", id, err)
+		}
+	}
+}
+
+func TestIPPTransportErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("http error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		p, _ := NewIPPPrinter(srv.URL, "")
+		if _, err := p.Status(ctx); !errors.Is(err, ErrIPP) || !strings.Contains(err.Error(), "HTTP 403") {
+			t.Fatalf("err = %v, mau ErrIPP HTTP 403", err)
+		}
+	})
+
+	t.Run("truncated response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/ipp")
+			w.Write([]byte{1, 1, 0, 0})
+		}))
+		defer srv.Close()
+
+		p, _ := NewIPPPrinter(srv.URL, "")
+		if _, err := p.Status(ctx); !errors.Is(err, ErrIPP) {
+			t.Fatalf("err = %v, mau ErrIPP", err)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		url := srv.URL
+		srv.Close()
+
+		p, _ := NewIPPPrinter(url, "")
+		if _, err := p.Status(ctx); err == nil || errors.Is(err, ErrIPP) {
+			t.Fatalf("err = %v, mau error koneksi", err)
+		}
+	})
+}
+
+func TestNewIPPPrinterURI(t *testing.T) {
+	cases := []struct {
+		uri      string
+		endpoint string
+	}{
+		{"ipp://cups.local/printers/dnp", "http://cups.local:631/printers/dnp"},
+		{"ipp://cups.local:8631/printers/dnp", "http://cups.local:8631/printers/dnp"},
+		{"ipps://printer.local/ipp/print", "https://printer.local:443/ipp/print"},
+		{"http://127.0.0.1:631/printers/dnp", "http://127.0.0.1:631/printers/dnp"},
+	}
+	for _, c := range cases {
+		p, err := NewIPPPrinter(c.uri, "")
+		if err != nil {
+			t.Fatalf("NewIPPPrinter(%q): %v", c.uri, err)
+		}
+		if got := p.(*ippPrinter).endpoint; got != c.endpoint {
+			t.Fatalf("endpoint %q = %q, mau %q", c.uri, got, c.endpoint)
+		}
+	}
+
+	for _, uri := range []string{"lpd://cups.local/queue", "/printers/dnp", "::"} {
+		if _, err := NewIPPPrinter(uri, ""); err == nil {
+			t.Fatalf("NewIPPPrinter(%q) harus gagal", uri)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package printing menyiapkan lembar cetak dan mengirimnya ke printer.
+package printing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/pdf"
+
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrUnknownLayout  = errors.New("layout cetak tidak dikenal")
+	ErrInvalidOptions = errors.New("pengaturan lembar cetak tidak valid")
+)
+
+// Layout adalah ukuran media cetak photobooth.
+type Layout string
+
+const (
+	Layout4x6 Layout = "4x6"
+	Layout2x6 Layout = "2x6" // dua strip berdampingan di kertas 4x6, dipotong di tengah
+	Layout5x7 Layout = "5x7"
+	Layout6x8 Layout = "6x8"
+)
+
+const (
+	DefaultDPI = 300
+	MaxDPI     = 600
+	mmPerInch  = 25.4
+)
+
+// sheetSpec adalah ukuran kertas (inch, posisi portrait) dan jumlah salinan per lembar.
+type sheetSpec struct {
+	width, height float64
+	cells         int
+}
+
+var sheets = map[Layout]sheetSpec{
+	Layout4x6: {4, 6, 1},
+	Layout2x6: {4, 6, 2},
+	Layout5x7: {5, 7, 1},
+	Layout6x8: {6, 8, 1},
+}
+
+// Options mengatur penempatan foto di lembar.
+//
+// Bleed ditambahkan di setiap sisi di luar ukuran kertas, untuk printer dye-sub
+// yang memotong sedikit melewati tepi. Margin 0 berarti foto full-bleed;
+// di atas 0, foto diberi bingkai putih selebar margin dari garis potong.
+type Options struct {
+	Layout   Layout
+	DPI      int
+	BleedMM  float64
+	MarginMM float64
+	CutLine  bool // garis bantu potong untuk layout 2x6
+}
+
+// Sheet adalah lembar siap cetak. Width dan Height dalam inch, termasuk bleed.
+type Sheet struct {
+	Image  *image.RGBA
+	Width  float64
+	Height float64
+	DPI    int
+}
+
+// ValidLayout memberi tahu apakah nama layout dikenal.
+func ValidLayout(l Layout) bool {
+	_, ok := sheets[l]
+	return ok
+}
+
+// Compose menaruh foto di lembar cetak. Lembar tunggal mengikuti orientasi
+// foto (foto landscape menghasilkan lembar landscape); strip 2x6 selalu
+// lembar portrait dengan strip diputar kalau perlu.
+func Compose(img image.Image, opts Options) (*Sheet, error) {
+	spec, ok := sheets[opts.Layout]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLayout, opts.Layout)
+	}
+	dpi := opts.DPI
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	if dpi > MaxDPI {
+		return nil, fmt.Errorf("%w: DPI maksimal %d", ErrInvalidOptions, MaxDPI)
+	}
+
+	paperW, paperH := spec.width, spec.height
+	b := img.Bounds()
+	if spec.cells == 1 && b.Dx() > b.Dy() {
+		paperW, paperH = paperH, paperW
+	}
+
+	bleed := opts.BleedMM / mmPerInch
+	margin := opts.MarginMM / mmPerInch
+	cellW := paperW / float64(spec.cells)
+	if margin*2 >= cellW || margin*2 >= paperH {
+		return nil, fmt.Errorf("%w: margin terlalu besar untuk ukuran kertas", ErrInvalidOptions)
+	}
+
+	px := func(inch float64) int { return int(math.Round(inch * float64(dpi))) }
+	sheet := &Sheet{Width: paperW + 2*bleed, Height: paperH + 2*bleed, DPI: dpi}
+	canvas := image.NewRGBA(image.Rect(0, 0, px(sheet.Width), px(sheet.Height)))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	for i := 0; i < spec.cells; i++ {
+		// Kotak foto dalam inch dari kiri atas kanvas. Full-bleed melebar sampai
+		// tepi bleed di sisi luar, tapi tidak melewati garis potong antar strip.
+		x0 := bleed + cellW*float64(i) + margin
+		x1 := bleed + cellW*float64(i+1) - margin
+		y0 := bleed + margin
+		y1 := bleed + paperH - margin
+		if margin == 0 {
+			y0, y1 = 0, sheet.Height
+			if i == 0 {
+				x0 = 0
+			}
+			if i == spec.cells-1 {
+				x1 = sheet.Width
+			}
+		}
+
+		rect := image.Rect(px(x0), px(y0), px(x1), px(y1))
+		src := img
+		if (b.Dx() > b.Dy()) != (rect.Dx() > rect.Dy()) {
+			src = rotate90(img)
+		}
+		draw.Draw(canvas, rect, imaging.Cover(src, rect.Dx(), rect.Dy()), image.Point{}, draw.Over)
+	}
+
+	if opts.CutLine && spec.cells > 1 {
+		for i := 1; i < spec.cells; i++ {
+			drawCutLine(canvas, px(bleed+cellW*float64(i)), max(1, dpi/150))
+		}
+	}
+
+	sheet.Image = canvas
+	return sheet, nil
+}
+
+// rotate90 memutar gambar 90 derajat searah jarum jam.
+func rotate90(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	in := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+
+	out := image.NewRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			si := in.PixOffset(x, y)
+			di := out.PixOffset(h-1-y, x)
+			copy(out.Pix[di:di+4], in.Pix[si:si+4])
+		}
+	}
+	return out
+}
+
+// drawCutLine menggambar garis putus-putus vertikal di x.
+func drawCutLine(canvas *image.RGBA, x, width int) {
+	h := canvas.Bounds().Dy()
+	dash := width * 12
+	lineColor := image.NewUniform(color.RGBA{150, 150, 150, 255})
+	for y := 0; y < h; y += dash * 2 {
+		r := image.Rect(x-width/2, y, x-width/2+width, min(y+dash, h))
+		draw.Draw(canvas, r, lineColor, image.Point{}, draw.Src)
+	}
+}
+
+// JPEG menulis lembar sebagai JPEG.
+func (s *Sheet) JPEG(w io.Writer, quality int) error {
+	return jpeg.Encode(w, s.Image, &jpeg.Options{Quality: quality})
+}
+
+// PDF menulis lembar sebagai PDF satu halaman seukuran kertas (termasuk bleed),
+// supaya driver printer tidak perlu menskalakan ulang.
+func (s *Sheet) PDF(w io.Writer, quality int) error {
+	var buf bytes.Buffer
+	if err := s.JPEG(&buf, quality); err != nil {
+		return err
+	}
+
+	doc := pdf.New()
+	pw, ph := s.Width*pdf.PointsPerInch, s.Height*pdf.PointsPerInch
+	page := doc.AddPage(pw, ph)
+	page.DrawJPEG(buf.Bytes(), s.Image.Bounds().Dx(), s.Image.Bounds().Dy(), false, 0, 0, pw, ph)
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,108 @@
+package printing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrUnknownDriver = errors.New("driver printer tidak dikenal")
+	ErrUnsupported   = errors.New("driver printer tidak didukung di sistem ini")
+	ErrJobNotFound   = errors.New("job cetak tidak ditemukan di printer")
+)
+
+// Driver printer yang tersedia.
+const (
+	DriverIPP     = "ipp"     // CUPS atau printer jaringan yang bicara IPP
+	DriverSumatra = "sumatra" // SumatraPDF di Windows
+)
+
+// JobState mengikuti nilai job-state IPP supaya semua driver sama.
+type JobState string
+
+const (
+	JobPending    JobState = "pending"
+	JobHeld       JobState = "held"
+	JobProcessing JobState = "processing"
+	JobStopped    JobState = "stopped"
+	JobCanceled   JobState = "canceled"
+	JobAborted    JobState = "aborted"
+	JobCompleted  JobState = "completed"
+	JobUnknown    JobState = "unknown"
+)
+
+// Done memberi tahu apakah job sudah tidak akan berubah lagi.
+func (s JobState) Done() bool {
+	return s == JobCanceled || s == JobAborted || s == JobCompleted
+}
+
+// Job adalah dokumen yang dikirim ke printer. Media memakai nama media PWG
+// (mis. na_index-4x6_4x6in); driver yang tidak mengenalnya memakai media default printer.
+type Job struct {
+	Name        string
+	Document    io.Reader
+	ContentType string
+	Copies      int
+	Media       string
+}
+
+type JobStatus struct {
+	ID      string   `json:"id"`
+	State   JobState `json:"state"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Status adalah kondisi printer. Media berisi media yang sedang terpasang,
+// Markers sisa consumable (ribbon/tinta) dalam persen kalau printer melaporkannya.
+type Status struct {
+	State     string         `json:"state"` // idle, processing, stopped, unknown
+	Accepting bool           `json:"accepting"`
+	Reasons   []string       `json:"reasons,omitempty"`
+	Media     []string       `json:"media,omitempty"`
+	Markers   map[string]int `json:"markers,omitempty"`
+}
+
+// Printer adalah backend cetak. Submit mengembalikan ID job dari printer
+// yang bisa dipakai untuk cek status.
+type Printer interface {
+	Submit(ctx context.Context, job Job) (string, error)
+	JobStatus(ctx context.Context, id string) (*JobStatus, error)
+	Status(ctx context.Context) (*Status, error)
+}
+
+// Config memilih dan mengatur driver. URI dipakai IPP
+// (ipp://host:631/printers/<antrian>), Queue dipakai SumatraPDF (nama printer Windows).
+type Config struct {
+	Driver      string
+	URI         string
+	Queue       string
+	SumatraPath string
+	UserName    string
+}
+
+func New(cfg Config) (Printer, error) {
+	switch cfg.Driver {
+	case DriverIPP:
+		return NewIPPPrinter(cfg.URI, cfg.UserName)
+	case DriverSumatra:
+		return NewSumatraPrinter(cfg.SumatraPath, cfg.Queue)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// mediaByLayout adalah nama media PWG untuk tiap layout. Strip 2x6 dicetak
+// di media 4x6; printer dye-sub yang mendukung auto-cut memotongnya sendiri.
+var mediaByLayout = map[Layout]string{
+	Layout4x6: "na_index-4x6_4x6in",
+	Layout2x6: "na_index-4x6_4x6in",
+	Layout5x7: "na_5x7_5x7in",
+	Layout6x8: "oe_photo-6x8_6x8in",
+}
+
+// MediaFor mengembalikan nama media PWG untuk layout.
+func MediaFor(l Layout) string {
+	return mediaByLayout[l]
+}
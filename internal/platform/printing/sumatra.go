@@ -0,0 +1,86 @@
+package printing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// sumatraPrinter mencetak lewat SumatraPDF di Windows. SumatraPDF selesai
+// setelah dokumen masuk spooler, jadi job dianggap selesai begitu Submit
+// berhasil dan status printer tidak bisa ditanyakan.
+type sumatraPrinter struct {
+	exePath string
+	queue   string
+}
+
+func NewSumatraPrinter(exePath, queue string) (Printer, error) {
+	if runtime.GOOS != "windows" {
+		return nil, ErrUnsupported
+	}
+	if queue == "" {
+		return nil, fmt.Errorf("nama printer SumatraPDF wajib diisi")
+	}
+	if exePath == "" {
+		exePath = "./SumatraPDF.exe"
+	}
+	abs, err := filepath.Abs(exePath)
+	if err != nil {
+		return nil, err
+	}
+	return &sumatraPrinter{exePath: abs, queue: queue}, nil
+}
+
+func (p *sumatraPrinter) Submit(ctx context.Context, job Job) (string, error) {
+	// SumatraPDF butuh file fisik, jadi dokumen ditulis dulu ke temp
+	ext := ".jpg"
+	if job.ContentType == "application/pdf" {
+		ext = ".pdf"
+	}
+	tmp, err := os.CreateTemp("", "print-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, job.Document); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	// Lembar dari layout engine (Media terisi) sudah seukuran kertas, jadi tidak
+	// diskalakan ulang; gambar lepas dipaskan ke kertas printer seperti dulu
+	settings := "fit"
+	if job.Media != "" {
+		settings = "noscale"
+	}
+	if job.Copies > 1 {
+		settings += "," + strconv.Itoa(job.Copies) + "x"
+	}
+
+	cmd := exec.CommandContext(ctx, p.exePath, "-print-to", p.queue, "-print-settings", settings, "-silent", tmp.Name())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("SumatraPDF Error: %s, Output: %s", err, string(output))
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id), nil
+}
+
+func (p *sumatraPrinter) JobStatus(ctx context.Context, id string) (*JobStatus, error) {
+	return &JobStatus{ID: id, State: JobCompleted}, nil
+}
+
+func (p *sumatraPrinter) Status(ctx context.Context) (*Status, error) {
+	return &Status{State: "unknown", Accepting: true}, nil
+}
@@ -0,0 +1,195 @@
+// Package qrcode membungkus encoder QR supaya bisa menghasilkan PNG atau SVG
+// dengan ukuran, level koreksi error, dan logo di tengah.
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	goqr "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize = 512
+	MinSize     = 128
+	MaxSize     = 2048
+
+	// logoRatio adalah lebar logo relatif terhadap lebar QR. Di atas ~25%
+	// modul yang tertutup mulai melebihi kapasitas koreksi error level H.
+	logoRatio = 0.22
+)
+
+var ErrInvalidOptions = errors.New("opsi QR code tidak valid")
+
+// Options mengatur tampilan QR code.
+type Options struct {
+	Format string // "png" atau "svg"
+	Size   int    // lebar/tinggi dalam pixel
+	Level  string // L, M, Q, atau H
+	Logo   image.Image
+}
+
+// Generate mengembalikan isi file QR beserta content type-nya.
+// Kalau ada logo dan level yang diminta di bawah Q, level dinaikkan ke H
+// supaya QR tetap terbaca walau bagian tengahnya tertutup.
+func Generate(content string, opts Options) ([]byte, string, error) {
+	if opts.Format == "" {
+		opts.Format = FormatPNG
+	}
+	if opts.Size == 0 {
+		opts.Size = DefaultSize
+	}
+	if opts.Size < MinSize || opts.Size > MaxSize {
+		return nil, "", fmt.Errorf("%w: ukuran harus antara %d dan %d", ErrInvalidOptions, MinSize, MaxSize)
+	}
+
+	level, err := parseLevel(opts.Level)
+	if err != nil {
+		return nil, "", err
+	}
+	if opts.Logo != nil && level < goqr.High {
+		level = goqr.Highest
+	}
+
+	qr, err := goqr.New(content, level)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch opts.Format {
+	case FormatPNG:
+		data, err := renderPNG(qr, opts)
+		return data, "image/png", err
+	case FormatSVG:
+		data, err := renderSVG(qr, opts)
+		return data, "image/svg+xml", err
+	default:
+		return nil, "", fmt.Errorf("%w: format harus png atau svg", ErrInvalidOptions)
+	}
+}
+
+func parseLevel(level string) (goqr.RecoveryLevel, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return goqr.Low, nil
+	case "", "M":
+		return goqr.Medium, nil
+	case "Q":
+		return goqr.High, nil
+	case "H":
+		return goqr.Highest, nil
+	default:
+		return 0, fmt.Errorf("%w: level harus L, M, Q atau H", ErrInvalidOptions)
+	}
+}
+
+func renderPNG(qr *goqr.QRCode, opts Options) ([]byte, error) {
+	src := qr.Image(opts.Size)
+	canvas := image.NewRGBA(src.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), src, image.Point{}, draw.Src)
+
+	if opts.Logo != nil {
+		drawLogo(canvas, opts.Logo)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLogo menempel logo di tengah dengan latar putih kecil di sekelilingnya.
+func drawLogo(canvas *image.RGBA, logo image.Image) {
+	size := canvas.Bounds().Dx()
+	box := int(float64(size) * logoRatio)
+	pad := box / 10
+
+	logoRect := fitRect(logo.Bounds(), box)
+	origin := image.Pt((size-logoRect.Dx())/2, (size-logoRect.Dy())/2)
+	dst := logoRect.Add(origin)
+
+	draw.Draw(canvas, dst.Inset(-pad), image.NewUniform(color.White), image.Point{}, draw.Src)
+	scaleInto(canvas, dst, logo)
+}
+
+// fitRect menghitung ukuran logo yang muat di kotak max x max tanpa merusak rasio.
+func fitRect(b image.Rectangle, max int) image.Rectangle {
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return image.Rect(0, 0, max, max)
+	}
+	if w >= h {
+		return image.Rect(0, 0, max, max*h/w)
+	}
+	return image.Rect(0, 0, max*w/h, max)
+}
+
+// scaleInto menggambar src ke dst dengan nearest-neighbour; cukup untuk logo kecil.
+func scaleInto(canvas *image.RGBA, dst image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	for y := dst.Min.Y; y < dst.Max.Y; y++ {
+		sy := sb.Min.Y + (y-dst.Min.Y)*sb.Dy()/dst.Dy()
+		for x := dst.Min.X; x < dst.Max.X; x++ {
+			sx := sb.Min.X + (x-dst.Min.X)*sb.Dx()/dst.Dx()
+			c := color.RGBAModel.Convert(src.At(sx, sy)).(color.RGBA)
+			if c.A == 0xff {
+				canvas.SetRGBA(x, y, c)
+				continue
+			}
+			// Alpha blend di atas latar putih
+			bg := canvas.RGBAAt(x, y)
+			a := uint32(c.A)
+			canvas.SetRGBA(x, y, color.RGBA{
+				R: uint8((uint32(c.R)*255 + uint32(bg.R)*(255-a)) / 255),
+				G: uint8((uint32(c.G)*255 + uint32(bg.G)*(255-a)) / 255),
+				B: uint8((uint32(c.B)*255 + uint32(bg.B)*(255-a)) / 255),
+				A: 0xff,
+			})
+		}
+	}
+}
+
+func renderSVG(qr *goqr.QRCode, opts Options) ([]byte, error) {
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, modules, modules)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, opts.Logo); err != nil {
+			return nil, err
+		}
+		box := float64(modules) * logoRatio
+		pos := (float64(modules) - box) / 2
+		pad := box / 10
+		fmt.Fprintf(&b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#ffffff"/>`, pos-pad, pos-pad, box+2*pad, box+2*pad)
+		fmt.Fprintf(&b, `<image x="%.2f" y="%.2f" width="%.2f" height="%.2f" preserveAspectRatio="xMidYMid meet" href="data:image/png;base64,%s"/>`,
+			pos, pos, box, box, base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+
+	b.WriteString(`</svg>`)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,408 @@
+// Package receipt menyusun struk sebagai daftar baris monospace lalu
+// merendernya ke ESC/POS untuk printer thermal, teks polos, atau PDF.
+// Semua format memakai lebar karakter yang sama supaya tampilannya sama.
+package receipt
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"photobooth-core/internal/platform/pdf"
+
+	goqr "github.com/skip2/go-qrcode"
+)
+
+// Lebar kertas thermal yang umum beserta jumlah karakter font A per baris.
+const (
+	Paper58mm = 58
+	Paper80mm = 80
+)
+
+var ErrInvalidPaper = errors.New("lebar kertas struk harus 58 atau 80 mm")
+
+type Align byte
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+type blockKind int
+
+const (
+	kindText blockKind = iota
+	kindTitle
+	kindRule
+	kindQR
+	kindFeed
+)
+
+type block struct {
+	kind  blockKind
+	text  string
+	align Align
+	bold  bool
+	lines int
+}
+
+// Receipt adalah struk yang sedang disusun. Baris panjang dipotong per kata
+// sesuai lebar kertas.
+type Receipt struct {
+	paperMM int
+	columns int
+	blocks  []block
+}
+
+// New membuat struk untuk kertas 58 mm (32 kolom) atau 80 mm (48 kolom).
+func New(paperMM int) (*Receipt, error) {
+	switch paperMM {
+	case Paper58mm:
+		return &Receipt{paperMM: paperMM, columns: 32}, nil
+	case Paper80mm:
+		return &Receipt{paperMM: paperMM, columns: 48}, nil
+	default:
+		return nil, ErrInvalidPaper
+	}
+}
+
+// Columns adalah jumlah karakter per baris.
+func (r *Receipt) Columns() int {
+	return r.columns
+}
+
+// Title menulis teks besar (lebar dan tinggi ganda) di tengah.
+func (r *Receipt) Title(text string) {
+	for _, line := range wrap(text, r.columns/2) {
+		r.blocks = append(r.blocks, block{kind: kindTitle, text: line, align: AlignCenter, bold: true})
+	}
+}
+
+// Text menulis teks biasa. Baris baru di dalam text dipertahankan.
+func (r *Receipt) Text(text string, align Align, bold bool) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(paragraph, r.columns) {
+			r.blocks = append(r.blocks, block{kind: kindText, text: line, align: align, bold: bold})
+		}
+	}
+}
+
+// Row menulis label di kiri dan nilai di kanan pada baris yang sama. Label
+// yang terlalu panjang dipotong supaya nilai (biasanya nominal) tetap utuh.
+func (r *Receipt) Row(label, value string, bold bool) {
+	space := r.columns - utf8.RuneCountInString(value) - 1
+	if space < 1 {
+		r.Text(label, AlignLeft, bold)
+		r.Text(value, AlignRight, bold)
+		return
+	}
+	label = truncate(label, space)
+	pad := r.columns - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	r.blocks = append(r.blocks, block{kind: kindText, text: label + strings.Repeat(" ", pad) + value, bold: bold})
+}
+
+// Rule menulis garis pemisah selebar kertas.
+func (r *Receipt) Rule() {
+	r.blocks = append(r.blocks, block{kind: kindRule, text: strings.Repeat("-", r.columns)})
+}
+
+// QR menaruh QR code di tengah. Di teks polos QR diganti isinya.
+func (r *Receipt) QR(content string) {
+	r.blocks = append(r.blocks, block{kind: kindQR, text: content, align: AlignCenter})
+}
+
+// Feed menambah baris kosong.
+func (r *Receipt) Feed(lines int) {
+	r.blocks = append(r.blocks, block{kind: kindFeed, lines: lines})
+}
+
+// ESC/POS: perintah dasar yang didukung printer thermal Epson-kompatibel.
+var (
+	escInit      = []byte{0x1b, 0x40}
+	escCodepage  = []byte{0x1b, 0x74, 0x00} // PC437, cukup untuk ASCII
+	escAlign     = []byte{0x1b, 0x61}
+	escBold      = []byte{0x1b, 0x45}
+	gsSize       = []byte{0x1d, 0x21}
+	escFeed      = []byte{0x1b, 0x64}
+	gsPartialCut = []byte{0x1d, 0x56, 0x42, 0x00}
+)
+
+// EscPos menulis byte stream ESC/POS siap kirim ke printer thermal, diakhiri
+// potong kertas. QR memakai perintah GS ( k model 2 sehingga dirender oleh
+// printer sendiri, bukan raster.
+func (r *Receipt) EscPos(w io.Writer) error {
+	var b bytes.Buffer
+	b.Write(escInit)
+	b.Write(escCodepage)
+
+	for _, blk := range r.blocks {
+		switch blk.kind {
+		case kindFeed:
+			b.Write(escFeed)
+			b.WriteByte(byte(blk.lines))
+			continue
+		case kindQR:
+			b.Write(escAlign)
+			b.WriteByte(byte(AlignCenter))
+			writeEscPosQR(&b, blk.text, r.qrModule())
+			b.WriteByte('\n')
+			continue
+		}
+
+		b.Write(escAlign)
+		b.WriteByte(byte(blk.align))
+		b.Write(escBold)
+		b.WriteByte(boolByte(blk.bold))
+		if blk.kind == kindTitle {
+			b.Write(gsSize)
+			b.WriteByte(0x11)
+		}
+		b.WriteString(ascii(blk.text))
+		b.WriteByte('\n')
+		if blk.kind == kindTitle {
+			b.Write(gsSize)
+			b.WriteByte(0x00)
+		}
+	}
+
+	b.Write(escBold)
+	b.WriteByte(0)
+	b.Write(escAlign)
+	b.WriteByte(byte(AlignLeft))
+	b.Write(escFeed)
+	b.WriteByte(3)
+	b.Write(gsPartialCut)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// qrModule adalah ukuran satu modul QR dalam dot printer.
+func (r *Receipt) qrModule() byte {
+	if r.paperMM == Paper80mm {
+		return 6
+	}
+	return 5
+}
+
+func writeEscPosQR(b *bytes.Buffer, content string, module byte) {
+	fn := func(params ...byte) {
+		b.Write([]byte{0x1d, 0x28, 0x6b, byte(len(params)), byte(len(params) >> 8)})
+		b.Write(params)
+	}
+	fn(0x31, 0x41, 0x32, 0x00) // model 2
+	fn(0x31, 0x43, module)     // ukuran modul
+	fn(0x31, 0x45, 0x31)       // koreksi error M
+
+	data := []byte(ascii(content))
+	n := len(data) + 3
+	b.Write([]byte{0x1d, 0x28, 0x6b, byte(n), byte(n >> 8), 0x31, 0x50, 0x30})
+	b.Write(data)
+
+	fn(0x31, 0x51, 0x30) // cetak
+}
+
+// PlainText menulis struk sebagai teks monospace, misalnya untuk isi email.
+func (r *Receipt) PlainText(w io.Writer) error {
+	var b strings.Builder
+	for _, blk := range r.blocks {
+		switch blk.kind {
+		case kindFeed:
+			b.WriteString(strings.Repeat("\n", blk.lines))
+		case kindQR:
+			for _, line := range wrap(blk.text, r.columns) {
+				b.WriteString(align(line, AlignCenter, r.columns))
+				b.WriteByte('\n')
+			}
+		case kindTitle:
+			b.WriteString(align(strings.ToUpper(blk.text), AlignCenter, r.columns))
+			b.WriteByte('\n')
+		default:
+			b.WriteString(strings.TrimRight(align(blk.text, blk.align, r.columns), " "))
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Ukuran PDF: margin kiri-kanan dan ukuran QR relatif terhadap lebar kertas.
+const (
+	pdfMarginMM  = 3.0
+	pdfLineRatio = 1.3
+	pdfQRRatio   = 0.55
+	pointsPerMM  = pdf.PointsPerInch / 25.4
+)
+
+// PDF menulis struk sebagai satu halaman PDF selebar kertas thermal dengan
+// tinggi mengikuti isi, memakai Courier supaya kolomnya sama dengan ESC/POS.
+func (r *Receipt) PDF(w io.Writer) error {
+	width := float64(r.paperMM) * pointsPerMM
+	margin := pdfMarginMM * pointsPerMM
+	size := (width - 2*margin) / (float64(r.columns) * pdf.CourierAdvance)
+	lineHeight := size * pdfLineRatio
+	qrSide := width * pdfQRRatio
+
+	height := 2 * margin
+	for _, blk := range r.blocks {
+		height += r.pdfHeight(blk, lineHeight, qrSide)
+	}
+
+	doc := pdf.New()
+	page := doc.AddPage(width, height)
+	y := height - margin
+	for _, blk := range r.blocks {
+		h := r.pdfHeight(blk, lineHeight, qrSide)
+		switch blk.kind {
+		case kindQR:
+			data, px, err := qrJPEG(blk.text)
+			if err != nil {
+				return err
+			}
+			page.DrawJPEG(data, px, px, true, (width-qrSide)/2, y-qrSide, qrSide, qrSide)
+		case kindTitle:
+			font, fontSize := pdf.CourierBold, size*2
+			text := strings.TrimSpace(blk.text)
+			textWidth := float64(utf8.RuneCountInString(text)) * fontSize * pdf.CourierAdvance
+			page.Text(font, fontSize, (width-textWidth)/2, y-fontSize, text)
+		case kindText, kindRule:
+			font := pdf.Courier
+			if blk.bold {
+				font = pdf.CourierBold
+			}
+			page.Text(font, size, margin, y-size, align(blk.text, blk.align, r.columns))
+		}
+		y -= h
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func (r *Receipt) pdfHeight(blk block, lineHeight, qrSide float64) float64 {
+	switch blk.kind {
+	case kindTitle:
+		return 2 * lineHeight
+	case kindQR:
+		return qrSide + lineHeight/2
+	case kindFeed:
+		return float64(blk.lines) * lineHeight
+	default:
+		return lineHeight
+	}
+}
+
+// qrJPEG merender QR grayscale untuk PDF. Kualitas tinggi supaya tepi modul
+// tidak kabur oleh kompresi.
+func qrJPEG(content string) ([]byte, int, error) {
+	qr, err := goqr.New(content, goqr.Medium)
+	if err != nil {
+		return nil, 0, err
+	}
+	src := qr.Image(512)
+	gray := image.NewGray(src.Bounds())
+	draw.Draw(gray, gray.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, gray, &jpeg.Options{Quality: 95}); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), gray.Bounds().Dx(), nil
+}
+
+// wrap memecah teks per kata supaya tiap baris muat di width kolom. Kata
+// yang lebih panjang dari satu baris dipotong paksa.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range words {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func align(text string, a Align, width int) string {
+	pad := width - utf8.RuneCountInString(text)
+	if pad <= 0 {
+		return text
+	}
+	switch a {
+	case AlignCenter:
+		return strings.Repeat(" ", pad/2) + text
+	case AlignRight:
+		return strings.Repeat(" ", pad) + text
+	default:
+		return text
+	}
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+// ascii mengganti karakter non-ASCII karena codepage printer thermal
+// berbeda-beda; huruf beraksen yang umum diturunkan ke huruf dasarnya.
+func ascii(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case strings.ContainsRune("àáâãäå", r):
+			b.WriteByte('a')
+		case strings.ContainsRune("èéêë", r):
+			b.WriteByte('e')
+		case strings.ContainsRune("ìíîï", r):
+			b.WriteByte('i')
+		case strings.ContainsRune("òóôõö", r):
+			b.WriteByte('o')
+		case strings.ContainsRune("ùúûü", r):
+			b.WriteByte('u')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
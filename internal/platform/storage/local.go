@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// localStorage menyimpan object sebagai file biasa di bawah satu root folder.
+// Cocok untuk development atau deployment single instance.
+type localStorage struct {
+	root string
+}
+
+// NewLocalStorage membuat driver filesystem dengan root folder tertentu.
+func NewLocalStorage(root string) (Storage, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &localStorage{root: abs}, nil
+}
+
+func (s *localStorage) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Tulis ke file sementara dulu lalu rename, supaya pembaca nggak
+	// pernah melihat file yang setengah jadi.
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *localStorage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, s.info(key, st), nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.info(key, st), nil
+}
+
+func (s *localStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := []ObjectInfo{}
+
+	// Mulai jalan dari folder terdalam yang pasti memuat prefix,
+	// supaya nggak perlu menyisir seluruh root.
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+	start := filepath.Join(s.root, filepath.FromSlash(strings.TrimSuffix(dir, "/")))
+	if rel, err := filepath.Rel(s.root, start); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, ErrInvalidKey
+	}
+
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		st, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, *s.info(key, st))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// SignedURL tidak tersedia untuk disk lokal karena folder storage tidak lagi
+// di-mount publik. File harus dialirkan lewat handler yang memverifikasi link.
+func (s *localStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", ErrSignedURLUnsupported
+}
+
+func (s *localStorage) info(key string, st fs.FileInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Key:         key,
+		Size:        st.Size(),
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		ModTime:     st.ModTime(),
+	}
+}
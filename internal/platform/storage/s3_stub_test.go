@@ -0,0 +1,346 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// s3Stub adalah server S3-compatible palsu (pengganti MinIO) untuk menguji
+// driver s3. Object disimpan di memori. Yang didukung hanya yang dipakai
+// s3Storage: PUT/GET/HEAD/DELETE object, ListObjectsV2, dan presigned GET.
+// Semua request wajib lolos verifikasi Signature V4.
+type s3Stub struct {
+	Bucket    string
+	Region    string // kosong = us-east-1
+	AccessKey string
+	SecretKey string
+	MaxKeys   int // batas isi satu halaman list, kecil supaya paginasi teruji; 0 = 1000
+
+	mu      sync.Mutex
+	objects map[string]stubObject
+	now     func() time.Time
+}
+
+type stubObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.objects == nil {
+		s.objects = map[string]stubObject{}
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	s.mu.Unlock()
+
+	bucket, key := s.route(r)
+	if bucket != s.Bucket {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket", "bucket "+bucket+" tidak ada")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	if code, msg := s.verify(r, body); code != "" {
+		writeS3Error(w, http.StatusForbidden, code, msg)
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		s.list(w, r)
+	case key == "":
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "operasi bucket tidak didukung stub")
+	case r.Method == http.MethodPut:
+		s.put(w, r, key, body)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		s.get(w, r, key)
+	case r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+// route memisahkan bucket dan key, mendukung path-style
+// (host/bucket/key) maupun virtual-host (bucket.host/key).
+func (s *s3Stub) route(r *http.Request) (bucket, key string) {
+	host := r.Host
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	if strings.HasPrefix(host, s.Bucket+".") {
+		return s.Bucket, p
+	}
+	bucket, key, _ = strings.Cut(p, "/")
+	return bucket, key
+}
+
+func (s *s3Stub) put(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	// S3 menolak PUT tanpa Content-Length, seperti yang sungguhan
+	if r.ContentLength < 0 {
+		writeS3Error(w, http.StatusLengthRequired, "MissingContentLength", "Content-Length wajib diisi")
+		return
+	}
+	if int64(len(body)) != r.ContentLength {
+		writeS3Error(w, http.StatusBadRequest, "IncompleteBody", "panjang body tidak sama dengan Content-Length")
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "binary/octet-stream"
+	}
+	sum := sha256.Sum256(body)
+
+	s.mu.Lock()
+	s.objects[key] = stubObject{data: body, contentType: contentType, modTime: s.now().UTC().Truncate(time.Second)}
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *s3Stub) get(w http.ResponseWriter, r *http.Request, key string) {
+	s.mu.Lock()
+	obj, ok := s.objects[key]
+	s.mu.Unlock()
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchKey", "object "+key+" tidak ada")
+		return
+	}
+
+	w.Header().Set("Content-Type", obj.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	w.Header().Set("Last-Modified", obj.modTime.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(obj.data)
+	}
+}
+
+type stubListResult struct {
+	XMLName               xml.Name          `xml:"ListBucketResult"`
+	Name                  string            `xml:"Name"`
+	Prefix                string            `xml:"Prefix"`
+	KeyCount              int               `xml:"KeyCount"`
+	MaxKeys               int               `xml:"MaxKeys"`
+	IsTruncated           bool              `xml:"IsTruncated"`
+	NextContinuationToken string            `xml:"NextContinuationToken,omitempty"`
+	Contents              []stubListContent `xml:"Contents"`
+}
+
+type stubListContent struct {
+	Key          string `xml:"Key"`
+	Size         int64  `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+// list adalah ListObjectsV2. Continuation token berisi key terakhir halaman
+// sebelumnya (di-encode base64, opaque bagi client seperti di S3).
+func (s *s3Stub) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	after := ""
+	if token := query.Get("continuation-token"); token != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "InvalidArgument", "continuation-token tidak valid")
+			return
+		}
+		after = string(raw)
+	}
+	maxKeys := s.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := stubListResult{Name: s.Bucket, Prefix: prefix, MaxKeys: maxKeys}
+	for _, key := range keys {
+		if len(result.Contents) == maxKeys {
+			result.IsTruncated = true
+			result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(result.Contents[maxKeys-1].Key))
+			break
+		}
+		obj := s.objects[key]
+		result.Contents = append(result.Contents, stubListContent{
+			Key:          key,
+			Size:         int64(len(obj.data)),
+			LastModified: obj.modTime.Format(time.RFC3339),
+		})
+	}
+	s.mu.Unlock()
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(result)
+}
+
+// verify memeriksa Signature V4 dari header Authorization atau dari query
+// string (presigned URL). Kembalian kosong berarti request sah.
+func (s *s3Stub) verify(r *http.Request, body []byte) (code, msg string) {
+	query := r.URL.Query()
+	presigned := query.Get("X-Amz-Signature") != ""
+
+	var credential, signedHeaders, signature, amzDate, payloadHash string
+	if presigned {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return "AccessDenied", "presigned URL hanya untuk GET"
+		}
+		if query.Get("X-Amz-Algorithm") != sigAlgorithm {
+			return "AuthorizationQueryParametersError", "algoritma tidak didukung"
+		}
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		amzDate = query.Get("X-Amz-Date")
+		payloadHash = unsignedPayload
+	} else {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, sigAlgorithm+" ") {
+			return "AccessDenied", "request tanpa tanda tangan"
+		}
+		for _, part := range strings.Split(strings.TrimPrefix(auth, sigAlgorithm+" "), ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch name {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signedHeaders = value
+			case "Signature":
+				signature = value
+			}
+		}
+		amzDate = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+		if payloadHash == "" {
+			return "InvalidRequest", "header X-Amz-Content-Sha256 wajib diisi"
+		}
+		if payloadHash != unsignedPayload {
+			sum := sha256.Sum256(body)
+			if payloadHash != hex.EncodeToString(sum[:]) {
+				return "XAmzContentSHA256Mismatch", "hash body tidak cocok"
+			}
+		}
+	}
+
+	region := s.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	signedAt, err := time.Parse(amzDateFormat, amzDate)
+	if err != nil {
+		return "AccessDenied", "X-Amz-Date tidak valid"
+	}
+	accessKey, scope, _ := strings.Cut(credential, "/")
+	if accessKey != s.AccessKey {
+		return "InvalidAccessKeyId", "access key tidak dikenal"
+	}
+	if scope != signedAt.Format("20060102")+"/"+region+"/s3/aws4_request" {
+		return "AuthorizationHeaderMalformed", "credential scope salah: " + scope
+	}
+
+	now := s.now()
+	if presigned {
+		expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || expires <= 0 || expires > 7*24*3600 {
+			return "AuthorizationQueryParametersError", "X-Amz-Expires tidak valid"
+		}
+		if now.After(signedAt.Add(time.Duration(expires) * time.Second)) {
+			return "AccessDenied", "presigned URL sudah kedaluwarsa"
+		}
+	} else if d := now.Sub(signedAt); d > 15*time.Minute || d < -15*time.Minute {
+		return "RequestTimeTooSkewed", "selisih waktu request terlalu jauh"
+	}
+
+	// Canonical request disusun ulang dari sisi server
+	var canonicalHeaders strings.Builder
+	names := strings.Split(signedHeaders, ";")
+	if !sort.StringsAreSorted(names) {
+		return "AuthorizationHeaderMalformed", "SignedHeaders harus urut"
+	}
+	for _, name := range names {
+		value := strings.TrimSpace(strings.Join(r.Header.Values(name), ","))
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+
+	unsignedQuery := url.Values{}
+	for k, v := range query {
+		if k != "X-Amz-Signature" {
+			unsignedQuery[k] = v
+		}
+	}
+
+	canonical := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		canonicalQuery(unsignedQuery),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	hashed := sha256.Sum256([]byte(canonical))
+	stringToSign := strings.Join([]string{sigAlgorithm, amzDate, scope, hex.EncodeToString(hashed[:])}, "\n")
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), signedAt.Format("20060102"))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	expected := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "SignatureDoesNotMatch", "tanda tangan tidak cocok"
+	}
+	return "", ""
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code, msg string) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	xml.NewEncoder(&b).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: msg})
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	w.Write(b.Bytes())
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newS3Test menjalankan stub di httptest lalu membuat driver s3 yang
+// mengarah ke s3.local. Semua koneksi dibelokkan ke listener stub, jadi
+// virtual-host (photobooth.s3.local) jalan tanpa DNS.
+func newS3Test(t *testing.T, pathStyle bool) (*s3Stub, S3Options, *atomic.Int64) {
+	t.Helper()
+
+	// clock menggeser jam stub supaya signed URL bisa kedaluwarsa tanpa sleep
+	clock := new(atomic.Int64)
+	stub := &s3Stub{
+		Bucket:    "photobooth",
+		AccessKey: "test",
+		SecretKey: "test-secret",
+		MaxKeys:   2, // kecil supaya List harus mengikuti continuation token
+		now:       func() time.Time { return time.Now().Add(time.Duration(clock.Load())) },
+	}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+	t.Cleanup(client.CloseIdleConnections)
+
+	return stub, S3Options{
+		Endpoint:   "s3.local:" + port,
+		Bucket:     stub.Bucket,
+		AccessKey:  stub.AccessKey,
+		SecretKey:  stub.SecretKey,
+		PathStyle:  pathStyle,
+		HTTPClient: client,
+	}, clock
+}
+
+func TestS3Storage(t *testing.T) {
+	for _, pathStyle := range []bool{true, false} {
+		name := "virtual-host"
+		if pathStyle {
+			name = "path-style"
+		}
+		t.Run(name, func(t *testing.T) { testS3Storage(t, pathStyle) })
+	}
+}
+
+func testS3Storage(t *testing.T, pathStyle bool) {
+	ctx := context.Background()
+	stub, opts, clock := newS3Test(t, pathStyle)
+	store, err := NewS3Storage(opts)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+
+	// Put dengan ukuran diketahui dan tidak diketahui (-1, dipakai chunk tus)
+	objects := map[string][]byte{
+		"tenant/a/photo 1.jpg":     []byte("jpeg-1"),
+		"tenant/a/photo+2.jpg":     []byte("jpeg-22"),
+		"tenant/a/ümlaut/3.jpg":    []byte("jpeg-333"),
+		"tenant/a/uploads/x.part":  bytes.Repeat([]byte("c"), 5000),
+		"tenant/b/bukan-prefix.jp": []byte("other"),
+	}
+	for key, data := range objects {
+		size := int64(len(data))
+		if strings.HasSuffix(key, ".part") {
+			size = -1
+		}
+		if err := store.Put(ctx, key, io.MultiReader(bytes.NewReader(data)), size, "image/jpeg"); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	for key, data := range objects {
+		rc, info, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get %s: %v", key, err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || !bytes.Equal(got, data) || info.ContentType != "image/jpeg" || info.Size != int64(len(data)) {
+			t.Fatalf("Get %s: isi atau metadata tidak cocok (size %d, type %q)", key, info.Size, info.ContentType)
+		}
+
+		stat, err := store.Stat(ctx, key)
+		if err != nil || stat.Size != int64(len(data)) || stat.ModTime.IsZero() {
+			t.Fatalf("Stat %s: %v %+v", key, err, stat)
+		}
+	}
+
+	listed, err := store.List(ctx, "tenant/a/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	keys := make([]string, 0, len(listed))
+	for _, obj := range listed {
+		keys = append(keys, obj.Key)
+	}
+	want := []string{"tenant/a/photo 1.jpg", "tenant/a/photo+2.jpg", "tenant/a/uploads/x.part", "tenant/a/ümlaut/3.jpg"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("List = %v, mau %v", keys, want)
+	}
+
+	// Signed URL dibuka tanpa header Authorization, seperti browser tamu
+	signed, err := store.SignedURL(ctx, "tenant/a/photo 1.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, _ := url.Parse(signed)
+	wantHost := "photobooth." + opts.Endpoint
+	if pathStyle {
+		wantHost = opts.Endpoint
+	}
+	if u.Host != wantHost {
+		t.Fatalf("host signed URL = %s, mau %s", u.Host, wantHost)
+	}
+	if status, body := fetch(t, opts.HTTPClient, signed); status != http.StatusOK || body != "jpeg-1" {
+		t.Fatalf("signed URL: HTTP %d %q", status, body)
+	}
+	if status, _ := fetch(t, opts.HTTPClient, strings.Replace(signed, "photo%201", "photo+2", 1)); status != http.StatusForbidden {
+		t.Fatalf("signed URL untuk key lain harus ditolak, dapat HTTP %d", status)
+	}
+	clock.Store(int64(2 * time.Minute))
+	if status, _ := fetch(t, opts.HTTPClient, signed); status != http.StatusForbidden {
+		t.Fatalf("signed URL kedaluwarsa harus ditolak, dapat HTTP %d", status)
+	}
+	clock.Store(0)
+
+	// Secret salah harus ditolak stub (HEAD tidak punya body error, cukup 403)
+	wrongOpts := opts
+	wrongOpts.SecretKey = "salah"
+	wrong, _ := NewS3Storage(wrongOpts)
+	if _, err := wrong.Stat(ctx, "tenant/a/photo 1.jpg"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Stat dengan secret salah: %v, mau 403", err)
+	}
+	if _, _, err := wrong.Get(ctx, "tenant/a/photo 1.jpg"); err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Fatalf("Get dengan secret salah: %v, mau SignatureDoesNotMatch", err)
+	}
+
+	for key := range objects {
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete %s: %v", key, err)
+		}
+		if _, err := store.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Stat setelah Delete %s: %v, mau ErrNotFound", key, err)
+		}
+	}
+	if err := store.Delete(ctx, "tenant/a/photo 1.jpg"); err != nil {
+		t.Fatalf("Delete object yang sudah hilang harus sukses: %v", err)
+	}
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if len(stub.objects) != 0 {
+		t.Fatalf("stub masih menyimpan %d object", len(stub.objects))
+	}
+}
+
+func fetch(t *testing.T, client *http.Client, rawURL string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		t.Fatalf("GET %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
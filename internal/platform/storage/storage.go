@@ -0,0 +1,73 @@
+// Package storage menyediakan abstraksi object storage untuk file foto,
+// supaya API bisa jalan di lebih dari satu instance tanpa bergantung ke disk lokal.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"photobooth-core/internal/platform/config"
+)
+
+var (
+	ErrNotFound             = errors.New("object tidak ditemukan di storage")
+	ErrInvalidKey           = errors.New("key storage tidak valid")
+	ErrSignedURLUnsupported = errors.New("driver storage tidak mendukung signed URL")
+)
+
+// ObjectInfo adalah metadata satu object di storage.
+type ObjectInfo struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// Storage adalah kontrak yang harus dipenuhi setiap driver.
+// Key selalu memakai "/" sebagai pemisah, tanpa slash di depan.
+type Storage interface {
+	// Put menulis object. size boleh -1 kalau panjang stream belum diketahui.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// New memilih driver berdasarkan STORAGE_DRIVER.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.StorageLocalPath)
+	case "s3":
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("driver storage tidak dikenal: %s", cfg.StorageDriver)
+	}
+}
+
+// cleanKey menolak key yang mencoba keluar dari root (path traversal).
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
@@ -9,16 +9,33 @@ import (
 
 // GetTenantID mengambil ID tenant dari context yang di-set oleh middleware
 func GetTenantID(c *gin.Context) (uuid.UUID, error) {
-	val, exists := c.Get("tenant_id")
+	return getUUID(c, "tenant_id")
+}
+
+// GetBoothID mengambil ID booth dari context (hanya ada untuk token device)
+func GetBoothID(c *gin.Context) (uuid.UUID, error) {
+	return getUUID(c, "booth_id")
+}
+
+// GetUserID mengambil ID user dari context (hanya ada untuk token user)
+func GetUserID(c *gin.Context) (uuid.UUID, error) {
+	return getUUID(c, "user_id")
+}
+
+// getUUID membaca key dari context. AuthMiddleware sudah menyimpan nilai
+// sebagai uuid.UUID, tapi format string tetap diterima untuk jaga-jaga.
+func getUUID(c *gin.Context, key string) (uuid.UUID, error) {
+	val, exists := c.Get(key)
 	if !exists {
-		return uuid.Nil, errors.New("tenant_id tidak ditemukan di context")
+		return uuid.Nil, errors.New(key + " tidak ditemukan di context")
 	}
 
-	// Konversi interface ke string, lalu string ke uuid.UUID
-	idStr, ok := val.(string)
-	if !ok {
-		return uuid.Nil, errors.New("format tenant_id tidak valid")
+	switch id := val.(type) {
+	case uuid.UUID:
+		return id, nil
+	case string:
+		return uuid.Parse(id)
+	default:
+		return uuid.Nil, errors.New("format " + key + " tidak valid")
 	}
-
-	return uuid.Parse(idStr)
 }
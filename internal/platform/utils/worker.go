@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RunEvery menjalankan fn secara berkala sampai ctx dibatalkan.
+// Error cuma di-log supaya satu kegagalan tidak menghentikan loop.
+func RunEvery(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				slog.Error("Background job gagal", "job", name, "error", err)
+			}
+		}
+	}
+}
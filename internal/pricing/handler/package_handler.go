@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	"photobooth-core/internal/pricing/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PackageHandler struct {
+	usecase usecase.PackageUsecase
+}
+
+func NewPackageHandler(u usecase.PackageUsecase) *PackageHandler {
+	return &PackageHandler{u}
+}
+
+// Create godoc
+// @Summary      Buat paket harga
+// @Description  Paket berisi harga, jumlah jepretan, jumlah cetak, dan apakah animasi termasuk. Paket digital_only tidak termasuk cetak. Pasang paket ke booth lewat /packages/{id}/assignments supaya muncul di booth.
+// @Tags         Packages
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.PackageRequest  true  "Isi paket"
+// @Success      201      {object}  response.Response{data=domain.PricingPackage}
+// @Failure      400      {object}  response.ErrorResponse
+// @Router       /api/v1/packages [post]
+func (h *PackageHandler) Create(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	pkg, err := h.usecase.Create(tenantID, req)
+	if err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Paket berhasil dibuat", pkg)
+}
+
+// List godoc
+// @Summary      Daftar paket harga milik tenant
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]domain.PricingPackage}
+// @Router       /api/v1/packages [get]
+func (h *PackageHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	packages, err := h.usecase.List(tenantID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil data paket", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data paket", packages)
+}
+
+// Get godoc
+// @Summary      Detail paket harga
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Package ID"
+// @Success      200  {object}  response.Response{data=domain.PricingPackage}
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/packages/{id} [get]
+func (h *PackageHandler) Get(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+
+	pkg, err := h.usecase.Get(tenantID, id)
+	if err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil data paket", pkg)
+}
+
+// Update godoc
+// @Summary      Ubah paket harga
+// @Description  Hanya berlaku untuk sesi baru. Sesi yang sudah dimulai menyimpan salinan paket dan tidak ikut berubah.
+// @Tags         Packages
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "Package ID"
+// @Param        request  body      domain.PackageRequest  true  "Isi paket"
+// @Success      200      {object}  response.Response{data=domain.PricingPackage}
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/packages/{id} [put]
+func (h *PackageHandler) Update(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	pkg, err := h.usecase.Update(tenantID, id, req)
+	if err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Paket berhasil diubah", pkg)
+}
+
+// Archive godoc
+// @Summary      Arsipkan paket harga
+// @Description  Paket dicabut dari semua booth. Sesi lama tetap menyimpan salinan paketnya.
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Package ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/packages/{id} [delete]
+func (h *PackageHandler) Archive(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Archive(tenantID, id); err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Paket berhasil diarsipkan", nil)
+}
+
+// Assign godoc
+// @Summary      Tawarkan paket di booth atau grup booth
+// @Description  Isi salah satu: booth_id atau booth_group.
+// @Tags         Packages
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                 true  "Package ID"
+// @Param        request  body      domain.CreatePackageAssignmentRequest  true  "Target"
+// @Success      201  {object}  response.Response
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/packages/{id}/assignments [post]
+func (h *PackageHandler) Assign(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreatePackageAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	assignment, err := h.usecase.Assign(tenantID, id, req)
+	if err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Paket berhasil dipasang", assignment)
+}
+
+// Assignments godoc
+// @Summary      Daftar pemasangan paket
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Package ID"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/packages/{id}/assignments [get]
+func (h *PackageHandler) Assignments(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.usecase.Assignments(tenantID, id)
+	if err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil pemasangan paket", assignments)
+}
+
+// Unassign godoc
+// @Summary      Lepas pemasangan paket
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id             path  string  true  "Package ID"
+// @Param        assignment_id  path  string  true  "Assignment ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/packages/{id}/assignments/{assignment_id} [delete]
+func (h *PackageHandler) Unassign(c *gin.Context) {
+	tenantID, id, ok := packageParams(c)
+	if !ok {
+		return
+	}
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Paket tidak ditemukan", nil)
+		return
+	}
+
+	if err := h.usecase.Unassign(tenantID, id, assignmentID); err != nil {
+		writePackageError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Pemasangan paket dilepas", nil)
+}
+
+// BoothPackages godoc
+// @Summary      Paket yang ditawarkan di booth ini
+// @Description  Dipanggil booth untuk layar pilih paket. Kirim id paket yang dipilih sebagai package_id saat memulai sesi.
+// @Tags         Packages
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]domain.PricingPackage}
+// @Router       /api/v1/booths/me/packages [get]
+func (h *PackageHandler) BoothPackages(c *gin.Context) {
+	tenantID, _ := utils.GetTenantID(c)
+	boothID, _ := utils.GetBoothID(c)
+
+	packages, err := h.usecase.ForBooth(tenantID, boothID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil paket booth", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil paket booth", packages)
+}
+
+func packageParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Paket tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+func writePackageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAssignment):
+		response.Error(c, http.StatusBadRequest, "Pemasangan paket tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrPackageNotFound):
+		response.Error(c, http.StatusNotFound, "Paket tidak ditemukan", nil)
+	default:
+		slog.Error("Gagal memproses paket", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses paket", err.Error())
+	}
+}
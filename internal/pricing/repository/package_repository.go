@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PackageRepository interface {
+	Create(pkg *domain.PricingPackage) error
+	FindByID(tenantID, id uuid.UUID) (*domain.PricingPackage, error)
+	FindByTenant(tenantID uuid.UUID) ([]domain.PricingPackage, error)
+	Update(pkg *domain.PricingPackage) error
+	Archive(tenantID, id uuid.UUID, at time.Time) error
+
+	CreateAssignment(assignment *domain.PackageAssignment) error
+	FindAssignments(tenantID, packageID uuid.UUID) ([]domain.PackageAssignment, error)
+	DeleteAssignment(tenantID, packageID, id uuid.UUID) error
+	FindForBooth(tenantID, boothID uuid.UUID, group string) ([]domain.PricingPackage, error)
+}
+
+type packageRepository struct {
+	db *gorm.DB
+}
+
+func NewPackageRepository(db *gorm.DB) PackageRepository {
+	return &packageRepository{db}
+}
+
+func (r *packageRepository) Create(pkg *domain.PricingPackage) error {
+	return r.db.Create(pkg).Error
+}
+
+func (r *packageRepository) FindByID(tenantID, id uuid.UUID) (*domain.PricingPackage, error) {
+	var pkg domain.PricingPackage
+	err := r.db.Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).First(&pkg).Error
+	return &pkg, err
+}
+
+func (r *packageRepository) FindByTenant(tenantID uuid.UUID) ([]domain.PricingPackage, error) {
+	packages := []domain.PricingPackage{}
+	err := r.db.Where("tenant_id = ? AND archived_at IS NULL", tenantID).
+		Order("price ASC, name ASC").
+		Find(&packages).Error
+	return packages, err
+}
+
+// Update menyimpan semua kolom, termasuk bool yang bernilai false.
+func (r *packageRepository) Update(pkg *domain.PricingPackage) error {
+	return r.db.Model(pkg).
+		Select("name", "price", "shots", "prints", "digital_only", "animation_included", "updated_at").
+		Updates(pkg).Error
+}
+
+// Archive menyembunyikan paket dan mencabutnya dari semua booth. Sesi lama
+// tidak terpengaruh karena menyimpan salinan paketnya sendiri.
+func (r *packageRepository) Archive(tenantID, id uuid.UUID, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.PricingPackage{}).
+			Where("tenant_id = ? AND id = ? AND archived_at IS NULL", tenantID, id).
+			Update("archived_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("package_id = ?", id).Delete(&domain.PackageAssignment{}).Error
+	})
+}
+
+func (r *packageRepository) CreateAssignment(assignment *domain.PackageAssignment) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *packageRepository) FindAssignments(tenantID, packageID uuid.UUID) ([]domain.PackageAssignment, error) {
+	assignments := []domain.PackageAssignment{}
+	err := r.db.Where("tenant_id = ? AND package_id = ?", tenantID, packageID).Order("created_at ASC").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *packageRepository) DeleteAssignment(tenantID, packageID, id uuid.UUID) error {
+	res := r.db.Where("tenant_id = ? AND package_id = ? AND id = ?", tenantID, packageID, id).Delete(&domain.PackageAssignment{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindForBooth mencari paket yang ditawarkan di booth ini, langsung atau
+// lewat grup.
+func (r *packageRepository) FindForBooth(tenantID, boothID uuid.UUID, group string) ([]domain.PricingPackage, error) {
+	assigned := r.db.Model(&domain.PackageAssignment{}).Select("package_id").
+		Where("tenant_id = ?", tenantID)
+	if group != "" {
+		assigned = assigned.Where("booth_id = ? OR booth_group = ?", boothID, group)
+	} else {
+		assigned = assigned.Where("booth_id = ?", boothID)
+	}
+
+	packages := []domain.PricingPackage{}
+	err := r.db.Where("tenant_id = ? AND archived_at IS NULL", tenantID).
+		Where("id IN (?)", assigned).
+		Order("price ASC, name ASC").
+		Find(&packages).Error
+	return packages, err
+}
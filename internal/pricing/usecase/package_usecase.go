@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/pricing/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPackageNotFound    = errors.New("paket tidak ditemukan")
+	ErrPackageUnavailable = errors.New("paket tidak ditawarkan di booth ini")
+	ErrInvalidAssignment  = errors.New("assignment harus menunjuk tepat satu booth atau satu grup booth")
+)
+
+type PackageUsecase interface {
+	Create(tenantID uuid.UUID, req domain.PackageRequest) (*domain.PricingPackage, error)
+	List(tenantID uuid.UUID) ([]domain.PricingPackage, error)
+	Get(tenantID, id uuid.UUID) (*domain.PricingPackage, error)
+	Update(tenantID, id uuid.UUID, req domain.PackageRequest) (*domain.PricingPackage, error)
+	Archive(tenantID, id uuid.UUID) error
+
+	Assign(tenantID, packageID uuid.UUID, req domain.CreatePackageAssignmentRequest) (*domain.PackageAssignment, error)
+	Assignments(tenantID, packageID uuid.UUID) ([]domain.PackageAssignment, error)
+	Unassign(tenantID, packageID, id uuid.UUID) error
+	ForBooth(tenantID, boothID uuid.UUID) ([]domain.PricingPackage, error)
+	ForSession(tenantID, boothID, packageID uuid.UUID) (*domain.PricingPackage, error)
+}
+
+type packageUsecase struct {
+	repo      repository.PackageRepository
+	boothRepo bRepo.BoothRepository
+}
+
+func NewPackageUsecase(repo repository.PackageRepository, boothRepo bRepo.BoothRepository) PackageUsecase {
+	return &packageUsecase{repo, boothRepo}
+}
+
+func (u *packageUsecase) Create(tenantID uuid.UUID, req domain.PackageRequest) (*domain.PricingPackage, error) {
+	now := time.Now()
+	pkg := &domain.PricingPackage{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		CreatedAt: now,
+	}
+	applyPackage(pkg, req, now)
+
+	if err := u.repo.Create(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+func (u *packageUsecase) List(tenantID uuid.UUID) ([]domain.PricingPackage, error) {
+	return u.repo.FindByTenant(tenantID)
+}
+
+func (u *packageUsecase) Get(tenantID, id uuid.UUID) (*domain.PricingPackage, error) {
+	pkg, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrPackageNotFound
+	}
+	return pkg, nil
+}
+
+// Update mengganti isi paket. Hanya berlaku untuk sesi berikutnya; sesi yang
+// sudah dibuat tetap memakai salinan paket saat sesi dimulai.
+func (u *packageUsecase) Update(tenantID, id uuid.UUID, req domain.PackageRequest) (*domain.PricingPackage, error) {
+	pkg, err := u.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	applyPackage(pkg, req, time.Now())
+
+	if err := u.repo.Update(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+func (u *packageUsecase) Archive(tenantID, id uuid.UUID) error {
+	err := u.repo.Archive(tenantID, id, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPackageNotFound
+	}
+	return err
+}
+
+// Assign menawarkan paket di satu booth atau satu grup booth.
+func (u *packageUsecase) Assign(tenantID, packageID uuid.UUID, req domain.CreatePackageAssignmentRequest) (*domain.PackageAssignment, error) {
+	if _, err := u.Get(tenantID, packageID); err != nil {
+		return nil, err
+	}
+	if (req.BoothID == nil) == (req.BoothGroup == "") {
+		return nil, ErrInvalidAssignment
+	}
+	if req.BoothID != nil {
+		if _, err := u.boothRepo.FindByID(tenantID, *req.BoothID); err != nil {
+			return nil, ErrInvalidAssignment
+		}
+	}
+
+	assignment := &domain.PackageAssignment{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		PackageID:  packageID,
+		BoothID:    req.BoothID,
+		BoothGroup: req.BoothGroup,
+		CreatedAt:  time.Now(),
+	}
+	if err := u.repo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (u *packageUsecase) Assignments(tenantID, packageID uuid.UUID) ([]domain.PackageAssignment, error) {
+	if _, err := u.Get(tenantID, packageID); err != nil {
+		return nil, err
+	}
+	return u.repo.FindAssignments(tenantID, packageID)
+}
+
+func (u *packageUsecase) Unassign(tenantID, packageID, id uuid.UUID) error {
+	err := u.repo.DeleteAssignment(tenantID, packageID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPackageNotFound
+	}
+	return err
+}
+
+// ForBooth dipakai booth untuk menampilkan pilihan paket di layar awal.
+func (u *packageUsecase) ForBooth(tenantID, boothID uuid.UUID) ([]domain.PricingPackage, error) {
+	booth, err := u.boothRepo.FindByID(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindForBooth(tenantID, boothID, booth.Group)
+}
+
+// ForSession mengambil paket yang dipilih tamu, dan memastikan paket itu
+// memang ditawarkan di booth yang memulai sesi.
+func (u *packageUsecase) ForSession(tenantID, boothID, packageID uuid.UUID) (*domain.PricingPackage, error) {
+	packages, err := u.ForBooth(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range packages {
+		if packages[i].ID == packageID {
+			return &packages[i], nil
+		}
+	}
+	return nil, ErrPackageUnavailable
+}
+
+func applyPackage(pkg *domain.PricingPackage, req domain.PackageRequest, now time.Time) {
+	pkg.Name = req.Name
+	pkg.Price = req.Price
+	pkg.Shots = req.Shots
+	pkg.Prints = req.Prints
+	pkg.DigitalOnly = req.DigitalOnly
+	pkg.AnimationIncluded = req.AnimationIncluded
+	if req.DigitalOnly {
+		pkg.Prints = 0
+	}
+	pkg.UpdatedAt = now
+}
@@ -0,0 +1,426 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/printing"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	"photobooth-core/internal/print/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrintHandler struct {
+	usecase usecase.PrintUsecase
+}
+
+func NewPrintHandler(u usecase.PrintUsecase) *PrintHandler {
+	return &PrintHandler{u}
+}
+
+// Sheet godoc
+// @Summary      Lembar cetak foto
+// @Description  Menaruh foto di kertas 4x6, 5x7, 6x8, atau dua strip 2x6 di kertas 4x6, pada DPI tertentu dengan bleed dan margin. Hasilnya JPEG atau PDF seukuran kertas.
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      image/jpeg
+// @Produce      application/pdf
+// @Param        id         path   string  true   "Photo ID"
+// @Param        layout     query  string  true   "4x6, 2x6, 5x7, 6x8"
+// @Param        dpi        query  int     false  "Default 300"
+// @Param        bleed_mm   query  number  false  "Bleed per sisi"
+// @Param        margin_mm  query  number  false  "Bingkai putih, 0 = full-bleed"
+// @Param        cut_line   query  bool    false  "Garis potong untuk 2x6"
+// @Param        format     query  string  false  "jpeg (default) atau pdf"
+// @Success      200
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/{id}/print-sheet [get]
+func (h *PrintHandler) Sheet(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.PrintSheetRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	file, err := h.usecase.Sheet(c.Request.Context(), tenantID, photoID, req)
+	if err != nil {
+		writePrintError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="`+file.Filename+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// Print godoc
+// @Summary      Cetak foto di printer booth
+// @Description  Lembar disusun seperti print-sheet (PDF) lalu masuk antrean printer booth (IPP/CUPS atau SumatraPDF), atau printer default server kalau booth belum diatur. Salinan dipotong dari jatah cetak sesi foto; melebihi jatah harus mengisi reprint (token staf atau PIN, plus alasan). Job dicetak berurutan per printer dan dicoba ulang dengan backoff kalau gagal.
+// @Tags         Print
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Photo ID"
+// @Param        request  body      domain.PrintPhotoRequest  true  "Layout dan jumlah salinan"
+// @Success      202  {object}  response.Response{data=domain.PrintJob}
+// @Failure      402  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Failure      429  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/{id}/print [post]
+func (h *PrintHandler) Print(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.PrintPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	job, err := h.usecase.Print(c.Request.Context(), boothID, tenantID, photoID, req)
+	if err != nil {
+		writePrintError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Foto masuk antrean cetak", job)
+}
+
+// Legacy godoc
+// @Summary      Cetak gambar base64 (deprecated)
+// @Description  Route lama untuk aplikasi booth versi awal. Gambar disimpan sebagai foto (divalidasi dan di-encode ulang) lalu dicetak seperti /photos/{id}/print dengan layout 4x6 kalau tidak diisi, memakai jatah sesi transaction_id atau otorisasi cetak ulang. Pakai /photos/{id}/print untuk aplikasi baru.
+// @Tags         Print
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  domain.LegacyPrintRequest  true  "Gambar base64"
+// @Success      200
+// @Failure      400
+// @Failure      402
+// @Failure      403
+// @Failure      409
+// @Failure      429
+// @Deprecated
+// @Router       /api/v1/print [post]
+func (h *PrintHandler) Legacy(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	var req domain.LegacyPrintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.usecase.PrintLegacy(c.Request.Context(), boothID, tenantID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrInvalidImage):
+			status = http.StatusBadRequest
+		case errors.Is(err, usecase.ErrQuotaExceeded), errors.Is(err, usecase.ErrSessionNotPaid):
+			status = http.StatusPaymentRequired
+		case errors.Is(err, usecase.ErrReprintDenied):
+			status = http.StatusForbidden
+		case errors.Is(err, usecase.ErrReprintLocked):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, usecase.ErrMediaMismatch):
+			status = http.StatusConflict
+		case errors.Is(err, usecase.ErrBoothNotFound), errors.Is(err, usecase.ErrSessionNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"status": "error", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Printing queued", "job_id": job.ID})
+}
+
+// ListJobs godoc
+// @Summary      Antrean cetak tenant
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Param        booth_id        query  string  false  "Filter booth"
+// @Param        transaction_id  query  string  false  "Filter sesi"
+// @Param        status          query  string  false  "queued, printing, done, failed, cancelled"
+// @Param        limit           query  int     false  "Jumlah data"
+// @Param        offset          query  int     false  "Offset data"
+// @Success      200  {object}  response.Response{data=[]domain.PrintJob}
+// @Router       /api/v1/print-jobs [get]
+func (h *PrintHandler) ListJobs(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var filter domain.PrintJobFilter
+	if filter.BoothID, err = optionalUUID(c.Query("booth_id")); err != nil {
+		response.Error(c, http.StatusBadRequest, "booth_id tidak valid", err.Error())
+		return
+	}
+	if filter.TransactionID, err = optionalUUID(c.Query("transaction_id")); err != nil {
+		response.Error(c, http.StatusBadRequest, "transaction_id tidak valid", err.Error())
+		return
+	}
+	filter.Status = domain.PrintJobStatus(c.Query("status"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+
+	jobs, err := h.usecase.ListJobs(tenantID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil antrean cetak", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil antrean cetak", jobs)
+}
+
+// GetJob godoc
+// @Summary      Detail job cetak
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Print job ID"
+// @Success      200  {object}  response.Response{data=domain.PrintJob}
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/print-jobs/{id} [get]
+func (h *PrintHandler) GetJob(c *gin.Context) {
+	h.jobAction(c, "Berhasil mengambil job cetak", h.usecase.GetJob)
+}
+
+// CancelJob godoc
+// @Summary      Batalkan job cetak
+// @Description  Hanya job yang masih antre. Job yang sudah dikirim ke printer dibatalkan dari printernya. Jatah cetak sesi dikembalikan.
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Print job ID"
+// @Success      200  {object}  response.Response{data=domain.PrintJob}
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /api/v1/print-jobs/{id}/cancel [post]
+func (h *PrintHandler) CancelJob(c *gin.Context) {
+	h.jobAction(c, "Job cetak dibatalkan", h.usecase.CancelJob)
+}
+
+// RequeueJob godoc
+// @Summary      Antrekan ulang job cetak
+// @Description  Job gagal atau batal masuk antrean lagi dengan jatah retry baru, memakai dokumen yang sama. Jatah sesi sudah dikembalikan saat job gagal/batal, jadi salinan dipotong lagi dari jatah sesi; kalau habis isi reprint.
+// @Tags         Print
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                          true   "Print job ID"
+// @Param        request  body      domain.RequeuePrintJobRequest  false  "Otorisasi cetak ulang (opsional)"
+// @Success      200  {object}  response.Response{data=domain.PrintJob}
+// @Failure      402  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /api/v1/print-jobs/{id}/requeue [post]
+func (h *PrintHandler) RequeueJob(c *gin.Context) {
+	var req domain.RequeuePrintJobRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Validation(c, err)
+			return
+		}
+	}
+
+	h.jobAction(c, "Job cetak masuk antrean lagi", func(tenantID, id uuid.UUID) (*domain.PrintJob, error) {
+		return h.usecase.RequeueJob(tenantID, id, req)
+	})
+}
+
+// Report godoc
+// @Summary      Laporan cetak per booth
+// @Description  Lembar yang sudah tercetak, dipisah antara yang dibayar dari jatah sesi (paid) dan cetak ulang gratis (reprint).
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Param        booth_id  query  string  false  "Filter booth"
+// @Param        from      query  string  false  "Mulai (RFC3339 atau YYYY-MM-DD)"
+// @Param        to        query  string  false  "Sampai, eksklusif (RFC3339 atau YYYY-MM-DD)"
+// @Success      200  {object}  response.Response{data=[]domain.PrintReport}
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/reports/prints [get]
+func (h *PrintHandler) Report(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var filter domain.PrintReportFilter
+	if filter.BoothID, err = optionalUUID(c.Query("booth_id")); err != nil {
+		response.Error(c, http.StatusBadRequest, "booth_id tidak valid", err.Error())
+		return
+	}
+	if filter.From, err = optionalTime(c.Query("from")); err != nil {
+		response.Error(c, http.StatusBadRequest, "from tidak valid", err.Error())
+		return
+	}
+	if filter.To, err = optionalTime(c.Query("to")); err != nil {
+		response.Error(c, http.StatusBadRequest, "to tidak valid", err.Error())
+		return
+	}
+
+	reports, err := h.usecase.Report(tenantID, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil laporan cetak", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil laporan cetak", reports)
+}
+
+func (h *PrintHandler) jobAction(c *gin.Context, message string, action func(tenantID, id uuid.UUID) (*domain.PrintJob, error)) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Job cetak tidak ditemukan", nil)
+		return
+	}
+
+	job, err := action(tenantID, id)
+	if err != nil {
+		writePrintError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, message, job)
+}
+
+// PrinterStatus godoc
+// @Summary      Status printer booth
+// @Description  Kondisi printer, media terpasang dan sisa consumable kalau printer melaporkannya (IPP).
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      502  {object}  response.ErrorResponse
+// @Failure      503  {object}  response.ErrorResponse
+// @Router       /api/v1/booths/me/printer [get]
+func (h *PrintHandler) PrinterStatus(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	status, err := h.usecase.PrinterStatus(c.Request.Context(), boothID, tenantID)
+	if err != nil {
+		writePrintError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil status printer", status)
+}
+
+// JobStatus godoc
+// @Summary      Status job cetak
+// @Tags         Print
+// @Security     BearerAuth
+// @Produce      json
+// @Param        job_id  path      string  true  "Job ID dari printer"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/booths/me/printer/jobs/{job_id} [get]
+func (h *PrintHandler) JobStatus(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	status, err := h.usecase.JobStatus(c.Request.Context(), boothID, tenantID, c.Param("job_id"))
+	if err != nil {
+		writePrintError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil status job cetak", status)
+}
+
+func writePrintError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrBoothNotFound):
+		response.Error(c, http.StatusNotFound, "Booth tidak ditemukan", nil)
+	case errors.Is(err, printing.ErrJobNotFound), errors.Is(err, usecase.ErrJobNotFound):
+		response.Error(c, http.StatusNotFound, "Job cetak tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrQuotaExceeded):
+		response.Error(c, http.StatusPaymentRequired, "Jatah cetak sesi sudah habis", err.Error())
+	case errors.Is(err, usecase.ErrSessionNotPaid):
+		response.Error(c, http.StatusPaymentRequired, "Sesi belum dibayar", err.Error())
+	case errors.Is(err, usecase.ErrReprintDenied):
+		response.Error(c, http.StatusForbidden, "Otorisasi cetak ulang ditolak", err.Error())
+	case errors.Is(err, usecase.ErrReprintLocked):
+		response.Error(c, http.StatusTooManyRequests, "PIN cetak ulang dikunci sementara", err.Error())
+	case errors.Is(err, usecase.ErrMediaMismatch):
+		response.Error(c, http.StatusConflict, "Ukuran kertas tidak cocok dengan printer booth", err.Error())
+	case errors.Is(err, usecase.ErrJobNotCancellable), errors.Is(err, usecase.ErrJobNotRequeueable):
+		response.Error(c, http.StatusConflict, "Status job cetak tidak mengizinkan aksi ini", err.Error())
+	case errors.Is(err, printing.ErrUnknownDriver), errors.Is(err, printing.ErrUnsupported):
+		response.Error(c, http.StatusServiceUnavailable, "Printer booth belum siap", err.Error())
+	case errors.Is(err, printing.ErrIPP):
+		response.Error(c, http.StatusBadGateway, "Printer menolak job", err.Error())
+	case errors.Is(err, usecase.ErrPhotoNotFound):
+		response.Error(c, http.StatusNotFound, "Foto tidak ditemukan", nil)
+	case errors.Is(err, printing.ErrUnknownLayout), errors.Is(err, printing.ErrInvalidOptions):
+		response.Error(c, http.StatusBadRequest, "Pengaturan cetak tidak valid", err.Error())
+	case errors.Is(err, usecase.ErrPhotoNotPrintable):
+		response.Error(c, http.StatusUnprocessableEntity, "Foto tidak bisa dicetak", err.Error())
+	default:
+		slog.Error("Gagal menyiapkan cetak", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal menyiapkan cetak", err.Error())
+	}
+}
+
+func optionalUUID(raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func optionalTime(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		if t, err = time.ParseInLocation(time.DateOnly, raw, time.Local); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"errors"
+	"photobooth-core/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrintJobRepository interface {
+	Create(job *domain.PrintJob) error
+	FindByID(tenantID, id uuid.UUID) (*domain.PrintJob, error)
+	FindByTenant(tenantID uuid.UUID, filter domain.PrintJobFilter) ([]domain.PrintJob, error)
+	FindDue(now time.Time, limit int) ([]domain.PrintJob, error)
+	Claim(id uuid.UUID, printerKey, owner string, now time.Time) (bool, error)
+	Heartbeat(id uuid.UUID, owner string, now time.Time) error
+	MarkDone(id uuid.UUID, owner, printerJobID string, at time.Time) error
+	MarkRetry(id uuid.UUID, owner, errMsg string, next time.Time) error
+	MarkFailed(id uuid.UUID, owner, errMsg string) (bool, error)
+	Cancel(tenantID, id uuid.UUID) (bool, error)
+	Requeue(job *domain.PrintJob, now time.Time) (bool, error)
+	FailStale(before time.Time, errMsg string) ([]domain.PrintJob, error)
+	Report(tenantID uuid.UUID, filter domain.PrintReportFilter) ([]domain.PrintReport, error)
+
+	CreateDenial(denial *domain.ReprintDenial) error
+	ReservePINAttempt(boothID uuid.UUID, now, windowStart time.Time) (int, error)
+	ResetPINAttempts(boothID uuid.UUID) error
+}
+
+type printJobRepository struct {
+	db *gorm.DB
+}
+
+func NewPrintJobRepository(db *gorm.DB) PrintJobRepository {
+	return &printJobRepository{db}
+}
+
+func (r *printJobRepository) Create(job *domain.PrintJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *printJobRepository) FindByID(tenantID, id uuid.UUID) (*domain.PrintJob, error) {
+	var job domain.PrintJob
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&job).Error
+	return &job, err
+}
+
+func (r *printJobRepository) FindByTenant(tenantID uuid.UUID, filter domain.PrintJobFilter) ([]domain.PrintJob, error) {
+	jobs := []domain.PrintJob{}
+	query := r.db.Where("tenant_id = ?", tenantID)
+
+	if filter.BoothID != nil {
+		query = query.Where("booth_id = ?", *filter.BoothID)
+	}
+	if filter.TransactionID != nil {
+		query = query.Where("transaction_id = ?", *filter.TransactionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	err := query.Order("created_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// FindDue mengambil job antre yang sudah waktunya dicoba, terlama dulu.
+func (r *printJobRepository) FindDue(now time.Time, limit int) ([]domain.PrintJob, error) {
+	var jobs []domain.PrintJob
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", domain.PrintJobQueued, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// Claim memindahkan job ke printing atas nama owner (instance API) hanya
+// kalau job masih queued dan printer-nya tidak sedang mencetak job lain di
+// instance mana pun. Klaim untuk printer yang sama diserialkan dengan
+// advisory lock; printerKey kosong berarti job tidak punya printer (gagal
+// disiapkan) dan tidak perlu antre printer.
+func (r *printJobRepository) Claim(id uuid.UUID, printerKey, owner string, now time.Time) (bool, error) {
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if printerKey != "" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", printerKey).Error; err != nil {
+				return err
+			}
+			var busy int64
+			err := tx.Model(&domain.PrintJob{}).
+				Where("status = ? AND printer_key = ?", domain.PrintJobPrinting, printerKey).
+				Count(&busy).Error
+			if err != nil || busy > 0 {
+				return err
+			}
+		}
+
+		var job domain.PrintJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id").
+			Where("id = ? AND status = ?", id, domain.PrintJobQueued).
+			Take(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&domain.PrintJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":       domain.PrintJobPrinting,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"printer_key":  printerKey,
+			"claimed_by":   owner,
+			"heartbeat_at": now,
+		})
+		claimed = res.RowsAffected == 1
+		return res.Error
+	})
+	return claimed, err
+}
+
+// Heartbeat menandai job masih dikerjakan owner, supaya instance lain tidak
+// menganggapnya tertinggal.
+func (r *printJobRepository) Heartbeat(id uuid.UUID, owner string, now time.Time) error {
+	return r.db.Model(&domain.PrintJob{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", id, domain.PrintJobPrinting, owner).
+		Update("heartbeat_at", now).Error
+}
+
+// MarkDone, MarkRetry, dan MarkFailed hanya berlaku untuk job yang masih
+// dipegang owner. Job yang sudah diambil alih FailStale tidak ditimpa.
+func (r *printJobRepository) MarkDone(id uuid.UUID, owner, printerJobID string, at time.Time) error {
+	return r.owned(id, owner).Updates(map[string]interface{}{
+		"status":         domain.PrintJobDone,
+		"printer_job_id": printerJobID,
+		"error":          "",
+		"completed_at":   at,
+	}).Error
+}
+
+func (r *printJobRepository) MarkRetry(id uuid.UUID, owner, errMsg string, next time.Time) error {
+	return r.owned(id, owner).Updates(map[string]interface{}{
+		"status":          domain.PrintJobQueued,
+		"error":           errMsg,
+		"next_attempt_at": next,
+	}).Error
+}
+
+func (r *printJobRepository) MarkFailed(id uuid.UUID, owner, errMsg string) (bool, error) {
+	res := r.owned(id, owner).Updates(map[string]interface{}{
+		"status": domain.PrintJobFailed,
+		"error":  errMsg,
+	})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *printJobRepository) owned(id uuid.UUID, owner string) *gorm.DB {
+	return r.db.Model(&domain.PrintJob{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", id, domain.PrintJobPrinting, owner)
+}
+
+// Cancel hanya berlaku untuk job yang belum dikirim ke printer.
+func (r *printJobRepository) Cancel(tenantID, id uuid.UUID) (bool, error) {
+	res := r.db.Model(&domain.PrintJob{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", tenantID, id, domain.PrintJobQueued).
+		Update("status", domain.PrintJobCancelled)
+	return res.RowsAffected == 1, res.Error
+}
+
+// Requeue mengembalikan job gagal/batal ke antrean dengan jatah retry baru,
+// sekaligus menyimpan jenis job dan otorisasi dari penagihan ulangnya.
+func (r *printJobRepository) Requeue(job *domain.PrintJob, now time.Time) (bool, error) {
+	res := r.db.Model(&domain.PrintJob{}).
+		Where("tenant_id = ? AND id = ? AND status IN ?", job.TenantID, job.ID,
+			[]domain.PrintJobStatus{domain.PrintJobFailed, domain.PrintJobCancelled}).
+		Updates(map[string]interface{}{
+			"status":          domain.PrintJobQueued,
+			"attempts":        0,
+			"error":           "",
+			"next_attempt_at": now,
+			"kind":            job.Kind,
+			"authorized_by":   job.AuthorizedBy,
+			"auth_method":     job.AuthMethod,
+			"reason":          job.Reason,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+// FailStale menandai gagal job printing yang heartbeat-nya berhenti sebelum
+// before (instance pencetaknya mati). Job yang masih dikerjakan instance lain
+// tidak tersentuh. Tidak di-retry otomatis karena bisa saja printer sudah
+// mencetaknya. Job yang diubah dikembalikan supaya jatahnya bisa dikembalikan.
+func (r *printJobRepository) FailStale(before time.Time, errMsg string) ([]domain.PrintJob, error) {
+	var jobs []domain.PrintJob
+	err := r.db.Model(&jobs).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)", domain.PrintJobPrinting, before).
+		Updates(map[string]interface{}{
+			"status": domain.PrintJobFailed,
+			"error":  errMsg,
+		}).Error
+	return jobs, err
+}
+
+// Report menjumlahkan lembar dari job yang sudah tercetak, per booth.
+func (r *printJobRepository) Report(tenantID uuid.UUID, filter domain.PrintReportFilter) ([]domain.PrintReport, error) {
+	query := r.db.Model(&domain.PrintJob{}).
+		Select("print_jobs.booth_id, COALESCE(booths.name, '') AS booth_name, COUNT(*) AS jobs, "+
+			"COALESCE(SUM(print_jobs.copies) FILTER (WHERE print_jobs.kind = ?), 0) AS paid_prints, "+
+			"COALESCE(SUM(print_jobs.copies) FILTER (WHERE print_jobs.kind = ?), 0) AS reprint_prints",
+			domain.PrintPaid, domain.PrintReprint).
+		Joins("LEFT JOIN booths ON booths.id = print_jobs.booth_id").
+		Where("print_jobs.tenant_id = ? AND print_jobs.status = ?", tenantID, domain.PrintJobDone)
+
+	if filter.BoothID != nil {
+		query = query.Where("print_jobs.booth_id = ?", *filter.BoothID)
+	}
+	if filter.From != nil {
+		query = query.Where("print_jobs.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("print_jobs.created_at < ?", *filter.To)
+	}
+
+	reports := []domain.PrintReport{}
+	err := query.Group("print_jobs.booth_id, booths.name").
+		Order("paid_prints DESC").
+		Scan(&reports).Error
+	return reports, err
+}
+
+func (r *printJobRepository) CreateDenial(denial *domain.ReprintDenial) error {
+	return r.db.Create(denial).Error
+}
+
+// ReservePINAttempt mencatat satu percobaan PIN di booth dan mengembalikan
+// jumlah percobaan di jendela berjalan, dalam satu statement atomik. Jendela
+// yang dimulai sebelum windowStart diulang dari satu.
+func (r *printJobRepository) ReservePINAttempt(boothID uuid.UUID, now, windowStart time.Time) (int, error) {
+	var attempts int
+	err := r.db.Raw(`INSERT INTO reprint_pin_counters (booth_id, attempts, window_started_at) VALUES (?, 1, ?)
+		ON CONFLICT (booth_id) DO UPDATE SET
+			attempts = CASE WHEN reprint_pin_counters.window_started_at < ? THEN 1 ELSE reprint_pin_counters.attempts + 1 END,
+			window_started_at = CASE WHEN reprint_pin_counters.window_started_at < ? THEN EXCLUDED.window_started_at ELSE reprint_pin_counters.window_started_at END
+		RETURNING attempts`, boothID, now, windowStart, windowStart).Scan(&attempts).Error
+	return attempts, err
+}
+
+// ResetPINAttempts dipanggil setelah PIN benar.
+func (r *printJobRepository) ResetPINAttempts(boothID uuid.UUID) error {
+	return r.db.Where("booth_id = ?", boothID).Delete(&domain.ReprintPINCounter{}).Error
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/printing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxPrintAttempts termasuk percobaan pertama.
+	maxPrintAttempts = 5
+	retryBaseDelay   = 15 * time.Second
+	retryMaxDelay    = 5 * time.Minute
+
+	queuePollInterval = 3 * time.Second
+	dispatchBatchSize = 50
+
+	// Job dianggap selesai kalau printer sudah melaporkan status akhir. Printer
+	// yang diam lebih dari jobWaitTimeout tidak di-retry supaya tidak dobel cetak.
+	jobPollInterval = 2 * time.Second
+	jobWaitTimeout  = 10 * time.Minute
+
+	// Instance yang mencetak memperbarui heartbeat job-nya. Job printing yang
+	// heartbeat-nya diam lebih dari heartbeatTimeout dianggap tertinggal
+	// (instance-nya mati) dan ditandai gagal oleh instance mana pun.
+	heartbeatInterval = 20 * time.Second
+	heartbeatTimeout  = 2 * time.Minute
+	staleSweepEvery   = time.Minute
+)
+
+// errPermanent menandai kegagalan yang tidak akan sembuh dengan retry.
+var errPermanent = errors.New("tidak dicoba ulang")
+
+// Start menjalankan dispatcher antrean cetak sampai ctx selesai. Beberapa
+// instance API boleh berjalan bersamaan: job diklaim di DB, dan job yang
+// ditinggal instance mati ditandai gagal secara berkala.
+func (u *printUsecase) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(queuePollInterval)
+		defer ticker.Stop()
+		var lastSweep time.Time
+		for {
+			if time.Since(lastSweep) >= staleSweepEvery {
+				u.failStale()
+				lastSweep = time.Now()
+			}
+			u.dispatch(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-u.wake:
+			}
+		}
+	}()
+}
+
+// failStale menandai gagal job yang heartbeat-nya berhenti, lalu
+// mengembalikan jatahnya seperti job yang gagal permanen.
+func (u *printUsecase) failStale() {
+	jobs, err := u.repo.FailStale(time.Now().Add(-heartbeatTimeout), "instance pencetak berhenti saat job sedang dicetak, cek hasil cetak sebelum antre ulang")
+	if err != nil {
+		slog.Error("Gagal membereskan job cetak yang tertinggal", "error", err)
+		return
+	}
+	if len(jobs) > 0 {
+		slog.Warn("Job cetak yang tertinggal ditandai gagal", "count", len(jobs))
+	}
+	for i := range jobs {
+		u.refund(&jobs[i])
+	}
+}
+
+// notify membangunkan dispatcher tanpa menunggu tick berikutnya.
+func (u *printUsecase) notify() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch membagi job yang sudah waktunya ke printer masing-masing. Printer
+// yang masih sibuk (di instance ini atau instance lain) dilewati, jadi tiap
+// printer mencetak satu per satu sesuai urutan antre.
+func (u *printUsecase) dispatch(ctx context.Context) {
+	jobs, err := u.repo.FindDue(time.Now(), dispatchBatchSize)
+	if err != nil {
+		slog.Error("Gagal mengambil antrean cetak", "error", err)
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		cfg, err := u.configFor(job.TenantID, job.BoothID)
+		if err != nil {
+			if ok, _ := u.repo.Claim(job.ID, "", u.owner, time.Now()); ok {
+				u.finish(job, "", fmt.Errorf("%w: %w", errPermanent, err))
+			}
+			continue
+		}
+
+		key := printerKey(cfg)
+		if !u.acquire(key) {
+			continue
+		}
+		ok, err := u.repo.Claim(job.ID, key, u.owner, time.Now())
+		if err != nil || !ok {
+			u.release(key)
+			continue
+		}
+		job.Attempts++
+		job.Driver = cfg.Driver
+
+		go func() {
+			defer u.notify()
+			defer u.release(key)
+			beat, stop := context.WithCancel(ctx)
+			defer stop()
+			go u.heartbeat(beat, job.ID)
+
+			printerJobID, err := u.run(ctx, job, cfg)
+			if ctx.Err() != nil {
+				// Server berhenti; heartbeat ikut berhenti dan failStale instance lain (atau berikutnya) yang membereskan job ini
+				return
+			}
+			u.finish(job, printerJobID, err)
+		}()
+	}
+}
+
+// heartbeat memperbarui heartbeat job sampai ctx selesai.
+func (u *printUsecase) heartbeat(ctx context.Context, id uuid.UUID) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.repo.Heartbeat(id, u.owner, time.Now()); err != nil {
+				slog.Warn("Gagal memperbarui heartbeat job cetak", "job_id", id, "error", err)
+			}
+		}
+	}
+}
+
+// run mengirim dokumen job ke printer lalu menunggu printer selesai.
+func (u *printUsecase) run(ctx context.Context, job *domain.PrintJob, cfg printing.Config) (string, error) {
+	printer, err := printing.New(cfg)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errPermanent, err)
+	}
+
+	body, _, err := u.storage.Get(ctx, job.StorageKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: dokumen cetak hilang: %w", errPermanent, err)
+	}
+	defer body.Close()
+
+	printerJobID, err := printer.Submit(ctx, printing.Job{
+		Name:        job.ID.String(),
+		Document:    body,
+		ContentType: job.ContentType,
+		Copies:      job.Copies,
+		Media:       printing.MediaFor(printing.Layout(job.Layout)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return printerJobID, u.wait(ctx, printer, printerJobID)
+}
+
+func (u *printUsecase) wait(ctx context.Context, printer printing.Printer, printerJobID string) error {
+	ctx, cancel := context.WithTimeout(ctx, jobWaitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := printer.JobStatus(ctx, printerJobID)
+		switch {
+		case errors.Is(err, printing.ErrJobNotFound):
+			// Printer sudah membuang riwayat job, artinya job sudah lewat antreannya
+			return nil
+		case err != nil:
+			slog.Warn("Gagal membaca status job di printer", "printer_job_id", printerJobID, "error", err)
+		case status.State == printing.JobCompleted:
+			return nil
+		case status.State.Done():
+			return fmt.Errorf("printer mengakhiri job dengan status %s (%s)", status.State, strings.Join(status.Reasons, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w: printer tidak melaporkan job selesai dalam %s", errPermanent, jobWaitTimeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// finish menyimpan hasil satu percobaan: selesai, antre lagi dengan backoff,
+// atau gagal kalau jatah percobaan habis. Job berbayar yang gagal permanen
+// mengembalikan jatah sesinya.
+func (u *printUsecase) finish(job *domain.PrintJob, printerJobID string, err error) {
+	var saveErr error
+	switch {
+	case err == nil:
+		saveErr = u.repo.MarkDone(job.ID, u.owner, printerJobID, time.Now())
+		if job.BoothID != nil {
+			// Satu salinan satu lembar; layout 2x6 pun dua strip di satu lembar 4x6
+			if err := u.printers.Consume(job.TenantID, *job.BoothID, job.Copies); err != nil {
+				slog.Error("Gagal mengurangi sisa media printer", "booth_id", job.BoothID, "error", err)
+			}
+		}
+	case errors.Is(err, errPermanent) || job.Attempts >= maxPrintAttempts:
+		slog.Error("Job cetak gagal", "job_id", job.ID, "attempts", job.Attempts, "error", err)
+		var failed bool
+		failed, saveErr = u.repo.MarkFailed(job.ID, u.owner, err.Error())
+		if failed {
+			u.refund(job)
+		}
+	default:
+		delay := retryDelay(job.Attempts)
+		slog.Warn("Job cetak gagal, dicoba lagi", "job_id", job.ID, "attempts", job.Attempts, "retry_in", delay, "error", err)
+		saveErr = u.repo.MarkRetry(job.ID, u.owner, err.Error(), time.Now().Add(delay))
+	}
+	if saveErr != nil {
+		slog.Error("Gagal menyimpan status job cetak", "job_id", job.ID, "error", saveErr)
+	}
+}
+
+// retryDelay menggandakan jeda tiap percobaan: 15s, 30s, 1m, 2m, ... maks 5m.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+func (u *printUsecase) acquire(key string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.busy[key] {
+		return false
+	}
+	u.busy[key] = true
+	return true
+}
+
+func (u *printUsecase) release(key string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.busy, key)
+}
+
+// printerKey mengenali printer fisik dari pengaturannya, supaya booth yang
+// berbagi printer (atau memakai default server) tetap antre satu jalur.
+func printerKey(cfg printing.Config) string {
+	if cfg.Driver == printing.DriverSumatra {
+		return cfg.Driver + "|" + cfg.Queue
+	}
+	return cfg.Driver + "|" + cfg.URI
+}
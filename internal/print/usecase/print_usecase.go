@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	mRepo "photobooth-core/internal/media/repository"
+	mUcase "photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/auth"
+	"photobooth-core/internal/platform/imaging"
+	"photobooth-core/internal/platform/printing"
+	"photobooth-core/internal/platform/storage"
+	"photobooth-core/internal/print/repository"
+	prUcase "photobooth-core/internal/printer/usecase"
+	trRepo "photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrPhotoNotFound     = errors.New("foto tidak ditemukan")
+	ErrPhotoNotPrintable = errors.New("foto animasi tidak bisa dicetak")
+	ErrBoothNotFound     = errors.New("booth tidak ditemukan")
+	ErrJobNotFound       = errors.New("job cetak tidak ditemukan")
+	ErrJobNotCancellable = errors.New("job cetak sudah dikirim ke printer")
+	ErrJobNotRequeueable = errors.New("hanya job gagal atau batal yang bisa diantrekan ulang")
+	ErrMediaMismatch     = errors.New("ukuran kertas tidak cocok dengan media printer booth")
+	ErrQuotaExceeded     = errors.New("jatah cetak sesi sudah habis, cetak ulang butuh otorisasi staf")
+	ErrReprintDenied     = errors.New("otorisasi cetak ulang tidak valid")
+	ErrReprintLocked     = errors.New("PIN cetak ulang terlalu sering salah, coba lagi nanti atau pakai login staf")
+	ErrSessionNotPaid    = errors.New("sesi foto belum dibayar")
+	ErrInvalidImage      = errors.New("gambar cetak tidak valid")
+	ErrSessionNotFound   = errors.New("sesi foto tidak ditemukan untuk booth ini")
+)
+
+// sheetJPEGQuality tinggi karena lembar ini langsung masuk printer.
+const sheetJPEGQuality = 95
+
+// PIN cetak ulang dikunci per booth setelah maxPINFailures kali salah dalam
+// pinLockWindow, supaya PIN pendek tidak bisa ditebak satu per satu.
+const (
+	maxPINFailures = 5
+	pinLockWindow  = 15 * time.Minute
+)
+
+// PrintFile adalah file siap cetak beserta content type-nya.
+type PrintFile struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+type PrintUsecase interface {
+	Sheet(ctx context.Context, tenantID, photoID uuid.UUID, req domain.PrintSheetRequest) (*PrintFile, error)
+	Print(ctx context.Context, boothID, tenantID, photoID uuid.UUID, req domain.PrintPhotoRequest) (*domain.PrintJob, error)
+	PrintLegacy(ctx context.Context, boothID, tenantID uuid.UUID, req domain.LegacyPrintRequest) (*domain.PrintJob, error)
+	ListJobs(tenantID uuid.UUID, filter domain.PrintJobFilter) ([]domain.PrintJob, error)
+	GetJob(tenantID, id uuid.UUID) (*domain.PrintJob, error)
+	CancelJob(tenantID, id uuid.UUID) (*domain.PrintJob, error)
+	RequeueJob(tenantID, id uuid.UUID, req domain.RequeuePrintJobRequest) (*domain.PrintJob, error)
+	Report(tenantID uuid.UUID, filter domain.PrintReportFilter) ([]domain.PrintReport, error)
+	PrinterStatus(ctx context.Context, boothID, tenantID uuid.UUID) (*printing.Status, error)
+	JobStatus(ctx context.Context, boothID, tenantID uuid.UUID, jobID string) (*printing.JobStatus, error)
+	Start(ctx context.Context)
+}
+
+type printUsecase struct {
+	repo       repository.PrintJobRepository
+	photoRepo  mRepo.PhotoRepository
+	media      mUcase.MediaUsecase
+	boothRepo  bRepo.BoothRepository
+	trxRepo    trRepo.TransactionRepository
+	tenantRepo domain.TenantRepository
+	printers   prUcase.PrinterUsecase
+	storage    storage.Storage
+	defaults   printing.Config
+
+	// owner menandai job yang diklaim instance ini di DB.
+	owner string
+
+	// busy berisi printer yang sedang mencetak di instance ini. Antar
+	// instance dijaga oleh Claim di DB.
+	mu   sync.Mutex
+	busy map[string]bool
+	wake chan struct{}
+}
+
+// NewPrintUsecase menerima printer default server; booth bisa menimpanya
+// lewat pengaturan printer masing-masing.
+func NewPrintUsecase(repo repository.PrintJobRepository, photoRepo mRepo.PhotoRepository, media mUcase.MediaUsecase, boothRepo bRepo.BoothRepository, trxRepo trRepo.TransactionRepository, tenantRepo domain.TenantRepository, printers prUcase.PrinterUsecase, store storage.Storage, defaults printing.Config) PrintUsecase {
+	return &printUsecase{
+		repo:       repo,
+		photoRepo:  photoRepo,
+		media:      media,
+		boothRepo:  boothRepo,
+		trxRepo:    trxRepo,
+		tenantRepo: tenantRepo,
+		printers:   printers,
+		storage:    store,
+		defaults:   defaults,
+		owner:      instanceID(),
+		busy:       map[string]bool{},
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Sheet menyusun foto ke lembar cetak sesuai ukuran media, hasilnya JPEG
+// atau PDF yang bisa langsung dikirim ke backend printer mana pun.
+func (u *printUsecase) Sheet(ctx context.Context, tenantID, photoID uuid.UUID, req domain.PrintSheetRequest) (*PrintFile, error) {
+	photo, err := u.findPrintable(tenantID, photoID)
+	if err != nil {
+		return nil, err
+	}
+	return u.sheet(ctx, photo, req)
+}
+
+func (u *printUsecase) findPrintable(tenantID, photoID uuid.UUID) (*domain.Photo, error) {
+	photo, err := u.photoRepo.FindByID(tenantID, photoID)
+	if err != nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.Kind == domain.PhotoAnimation {
+		return nil, ErrPhotoNotPrintable
+	}
+	return photo, nil
+}
+
+func (u *printUsecase) sheet(ctx context.Context, photo *domain.Photo, req domain.PrintSheetRequest) (*PrintFile, error) {
+	body, _, err := u.storage.Get(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal decode %s: %w", photo.StorageKey, err)
+	}
+
+	sheet, err := printing.Compose(img, printing.Options{
+		Layout:   printing.Layout(req.Layout),
+		DPI:      req.DPI,
+		BleedMM:  req.BleedMM,
+		MarginMM: req.MarginMM,
+		CutLine:  req.CutLine,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	file := &PrintFile{Filename: fmt.Sprintf("%s_%s", photo.ID, req.Layout)}
+	if req.Format == "pdf" {
+		err = sheet.PDF(&buf, sheetJPEGQuality)
+		file.ContentType, file.Filename = "application/pdf", file.Filename+".pdf"
+	} else {
+		err = sheet.JPEG(&buf, sheetJPEGQuality)
+		file.ContentType, file.Filename = "image/jpeg", file.Filename+".jpg"
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.Data = buf.Bytes()
+	return file, nil
+}
+
+// Print menyusun lembar cetak sebagai PDF lalu memasukkannya ke antrean
+// printer booth. Hasil cetaknya dipantau lewat job yang dikembalikan.
+func (u *printUsecase) Print(ctx context.Context, boothID, tenantID, photoID uuid.UUID, req domain.PrintPhotoRequest) (*domain.PrintJob, error) {
+	if _, err := u.boothRepo.FindByID(tenantID, boothID); err != nil {
+		return nil, ErrBoothNotFound
+	}
+	if printer, err := u.printers.Get(tenantID, boothID); err == nil && printer.MediaSize != "" &&
+		printing.MediaFor(printing.Layout(req.Layout)) != printing.MediaFor(printing.Layout(printer.MediaSize)) {
+		return nil, fmt.Errorf("%w: printer memakai %s", ErrMediaMismatch, printer.MediaSize)
+	}
+	photo, err := u.findPrintable(tenantID, photoID)
+	if err != nil {
+		return nil, err
+	}
+	// Booth hanya boleh mencetak fotonya sendiri, jatah yang dipotong pun
+	// jatah sesi booth ini
+	if photo.BoothID != boothID {
+		return nil, ErrPhotoNotFound
+	}
+
+	sheetReq := req.PrintSheetRequest
+	sheetReq.Format = "pdf"
+	file, err := u.sheet(ctx, photo, sheetReq)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := req.Copies
+	if copies == 0 {
+		copies = 1
+	}
+	job := &domain.PrintJob{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		BoothID:       &boothID,
+		TransactionID: photo.TransactionID,
+		PhotoID:       &photo.ID,
+		Layout:        req.Layout,
+		Copies:        copies,
+		ContentType:   file.ContentType,
+	}
+	job.StorageKey = fmt.Sprintf("prints/%s/%s.pdf", tenantID, job.ID)
+	return u.enqueue(ctx, job, file.Data, req.Reprint)
+}
+
+// PrintLegacy dipakai route /print lama. Gambar base64 disimpan dulu sebagai
+// Photo lewat pipeline media (divalidasi dan di-encode ulang), lalu dicetak
+// lewat Print, jadi cek media printer, layout, dan jatah sesi sama persis.
+func (u *printUsecase) PrintLegacy(ctx context.Context, boothID, tenantID uuid.UUID, req domain.LegacyPrintRequest) (*domain.PrintJob, error) {
+	if _, err := u.boothRepo.FindByID(tenantID, boothID); err != nil {
+		return nil, ErrBoothNotFound
+	}
+
+	photo, err := u.media.SavePhoto(ctx, boothID, tenantID, domain.SavePhotoRequest{
+		Image:         req.Image,
+		FrameName:     "legacy-print",
+		TransactionID: req.TransactionID,
+	})
+	if err != nil {
+		var rejected *imaging.RejectError
+		switch {
+		case errors.As(err, &rejected), errors.Is(err, mUcase.ErrFileTooLarge):
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+		case errors.Is(err, mUcase.ErrTransactionNotFound):
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	layout := req.Layout
+	if layout == "" {
+		layout = string(printing.Layout4x6)
+	}
+	return u.Print(ctx, boothID, tenantID, photo.ID, domain.PrintPhotoRequest{
+		PrintSheetRequest: domain.PrintSheetRequest{Layout: layout},
+		Copies:            1,
+		Reprint:           req.Reprint,
+	})
+}
+
+// enqueue memotong jatah cetak (atau memeriksa otorisasi cetak ulang), lalu
+// menyimpan dokumen dan job. Jatah dikembalikan kalau job gagal dibuat.
+func (u *printUsecase) enqueue(ctx context.Context, job *domain.PrintJob, data []byte, reprint *domain.ReprintAuthorization) (*domain.PrintJob, error) {
+	if err := u.charge(job, reprint); err != nil {
+		return nil, err
+	}
+
+	if err := u.storage.Put(ctx, job.StorageKey, bytes.NewReader(data), int64(len(data)), job.ContentType); err != nil {
+		u.refund(job)
+		return nil, fmt.Errorf("gagal menyimpan dokumen cetak: %w", err)
+	}
+
+	job.Status = domain.PrintJobQueued
+	job.NextAttemptAt = time.Now()
+	if err := u.repo.Create(job); err != nil {
+		u.refund(job)
+		_ = u.storage.Delete(ctx, job.StorageKey)
+		return nil, err
+	}
+	u.notify()
+	return job, nil
+}
+
+// charge menentukan jenis job. Tanpa otorisasi, salinan dipotong dari jatah
+// sesi secara atomik; foto tanpa sesi selalu butuh otorisasi.
+func (u *printUsecase) charge(job *domain.PrintJob, reprint *domain.ReprintAuthorization) error {
+	if reprint != nil {
+		return u.authorizeReprint(job, reprint)
+	}
+	if job.TransactionID == nil {
+		return ErrQuotaExceeded
+	}
+
+	ok, err := u.trxRepo.UsePrints(job.TenantID, *job.TransactionID, job.Copies)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if trx, err := u.trxRepo.FindByID(job.TenantID, *job.TransactionID); err == nil && !trx.Status.Paid() {
+			return ErrSessionNotPaid
+		}
+		return ErrQuotaExceeded
+	}
+	job.Kind = domain.PrintPaid
+	return nil
+}
+
+// refund mengembalikan jatah sesi yang dipotong job berbayar. Job cetak
+// ulang tidak memotong jatah, jadi tidak ada yang dikembalikan.
+func (u *printUsecase) refund(job *domain.PrintJob) {
+	if job.Kind != domain.PrintPaid || job.TransactionID == nil {
+		return
+	}
+	if err := u.trxRepo.RefundPrints(*job.TransactionID, job.Copies); err != nil {
+		slog.Error("Gagal mengembalikan jatah cetak", "transaction_id", job.TransactionID, "error", err)
+	}
+}
+
+// authorizeReprint menerima token login staf dari tenant yang sama, atau PIN
+// cetak ulang tenant. Alasannya ikut disimpan di job untuk laporan. Setiap
+// penolakan dicatat; PIN dikunci di booth yang terlalu sering salah.
+func (u *printUsecase) authorizeReprint(job *domain.PrintJob, reprint *domain.ReprintAuthorization) error {
+	switch {
+	case reprint.StaffToken != "":
+		tenantID, userID, err := auth.ParseUserToken(reprint.StaffToken)
+		if err != nil || tenantID != job.TenantID {
+			return u.deny(job, "staff_token")
+		}
+		job.AuthorizedBy = &userID
+		job.AuthMethod = "staff_token"
+	case reprint.PIN != "":
+		// Percobaan dihitung dulu baru PIN dicek, jadi percobaan bersamaan
+		// tidak bisa lolos batas. PIN yang benar mengosongkan hitungannya.
+		if job.BoothID != nil {
+			now := time.Now()
+			attempts, err := u.repo.ReservePINAttempt(*job.BoothID, now, now.Add(-pinLockWindow))
+			if err != nil {
+				return err
+			}
+			if attempts > maxPINFailures {
+				return ErrReprintLocked
+			}
+		}
+		tenant, err := u.tenantRepo.FindByID(job.TenantID)
+		if err != nil || tenant.ReprintPINHash == "" ||
+			bcrypt.CompareHashAndPassword([]byte(tenant.ReprintPINHash), []byte(reprint.PIN)) != nil {
+			return u.deny(job, "pin")
+		}
+		if job.BoothID != nil {
+			if err := u.repo.ResetPINAttempts(*job.BoothID); err != nil {
+				slog.Error("Gagal mengosongkan hitungan PIN", "booth_id", job.BoothID, "error", err)
+			}
+		}
+		job.AuthMethod = "pin"
+	default:
+		return ErrReprintDenied
+	}
+
+	job.Kind = domain.PrintReprint
+	job.Reason = reprint.Reason
+	return nil
+}
+
+// deny mencatat otorisasi yang ditolak lalu mengembalikan ErrReprintDenied.
+func (u *printUsecase) deny(job *domain.PrintJob, method string) error {
+	slog.Warn("Otorisasi cetak ulang ditolak", "tenant_id", job.TenantID, "booth_id", job.BoothID, "method", method)
+	if err := u.repo.CreateDenial(&domain.ReprintDenial{
+		ID:        uuid.New(),
+		TenantID:  job.TenantID,
+		BoothID:   job.BoothID,
+		JobID:     job.ID,
+		Method:    method,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		slog.Error("Gagal mencatat penolakan cetak ulang", "error", err)
+	}
+	return ErrReprintDenied
+}
+
+func (u *printUsecase) ListJobs(tenantID uuid.UUID, filter domain.PrintJobFilter) ([]domain.PrintJob, error) {
+	return u.repo.FindByTenant(tenantID, filter)
+}
+
+func (u *printUsecase) GetJob(tenantID, id uuid.UUID) (*domain.PrintJob, error) {
+	job, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// CancelJob hanya bisa untuk job yang masih antre; yang sudah di printer
+// harus dibatalkan dari printernya langsung. Jatah sesinya dikembalikan.
+func (u *printUsecase) CancelJob(tenantID, id uuid.UUID) (*domain.PrintJob, error) {
+	job, err := u.GetJob(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.repo.Cancel(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJobNotCancellable
+	}
+	u.refund(job)
+	return u.GetJob(tenantID, id)
+}
+
+// RequeueJob mengantrekan ulang job gagal/batal dengan jatah retry baru.
+// Dokumennya tetap file yang sama dengan saat job dibuat. Karena jatahnya
+// sudah dikembalikan, job ditagih lagi lewat jatah sesi atau otorisasi
+// cetak ulang seperti job baru.
+func (u *printUsecase) RequeueJob(tenantID, id uuid.UUID, req domain.RequeuePrintJobRequest) (*domain.PrintJob, error) {
+	job, err := u.GetJob(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.PrintJobFailed && job.Status != domain.PrintJobCancelled {
+		return nil, ErrJobNotRequeueable
+	}
+
+	job.AuthorizedBy, job.AuthMethod, job.Reason = nil, "", ""
+	if err := u.charge(job, req.Reprint); err != nil {
+		return nil, err
+	}
+	ok, err := u.repo.Requeue(job, time.Now())
+	if err != nil || !ok {
+		u.refund(job)
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrJobNotRequeueable
+	}
+	u.notify()
+	return u.GetJob(tenantID, id)
+}
+
+func (u *printUsecase) Report(tenantID uuid.UUID, filter domain.PrintReportFilter) ([]domain.PrintReport, error) {
+	return u.repo.Report(tenantID, filter)
+}
+
+func (u *printUsecase) PrinterStatus(ctx context.Context, boothID, tenantID uuid.UUID) (*printing.Status, error) {
+	printer, err := u.printerFor(tenantID, &boothID)
+	if err != nil {
+		return nil, err
+	}
+	return printer.Status(ctx)
+}
+
+func (u *printUsecase) JobStatus(ctx context.Context, boothID, tenantID uuid.UUID, jobID string) (*printing.JobStatus, error) {
+	printer, err := u.printerFor(tenantID, &boothID)
+	if err != nil {
+		return nil, err
+	}
+	return printer.JobStatus(ctx, jobID)
+}
+
+func (u *printUsecase) printerFor(tenantID uuid.UUID, boothID *uuid.UUID) (printing.Printer, error) {
+	cfg, err := u.configFor(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+	return printing.New(cfg)
+}
+
+// configFor mengambil printer yang terpasang di booth, atau default server
+// kalau booth belum punya printer atau job tidak punya booth (job legacy lama).
+func (u *printUsecase) configFor(tenantID uuid.UUID, boothID *uuid.UUID) (printing.Config, error) {
+	cfg := u.defaults
+	if boothID == nil {
+		return cfg, nil
+	}
+	if _, err := u.boothRepo.FindByID(tenantID, *boothID); err != nil {
+		return cfg, ErrBoothNotFound
+	}
+	if printer, err := u.printers.Get(tenantID, *boothID); err == nil {
+		cfg.Driver = printer.Driver
+		cfg.URI = printer.URI
+		cfg.Queue = printer.Queue
+	}
+	return cfg, nil
+}
+
+// instanceID mengenali proses API ini di kolom claimed_by.
+func instanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
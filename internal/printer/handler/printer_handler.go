@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	"photobooth-core/internal/printer/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PrinterHandler struct {
+	usecase usecase.PrinterUsecase
+}
+
+func NewPrinterHandler(u usecase.PrinterUsecase) *PrinterHandler {
+	return &PrinterHandler{u}
+}
+
+// Set godoc
+// @Summary      Pasang printer booth
+// @Description  Driver ipp (CUPS/printer jaringan, isi uri) atau sumatra (Windows, isi queue). Isi prints_per_roll supaya sisa media dihitung dan tenant diberi tahu saat kertas menipis.
+// @Tags         Printers
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Booth ID"
+// @Param        request  body      domain.UpdatePrinterRequest  true  "Printer"
+// @Success      200      {object}  response.Response
+// @Failure      400      {object}  response.ErrorResponse
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/booths/{id}/printer [put]
+func (h *PrinterHandler) Set(c *gin.Context) {
+	tenantID, boothID, ok := boothParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdatePrinterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	printer, err := h.usecase.Set(tenantID, boothID, req)
+	if err != nil {
+		writePrinterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Printer booth berhasil diatur", printer)
+}
+
+// Get godoc
+// @Summary      Printer booth dan sisa medianya
+// @Tags         Printers
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Booth ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/booths/{id}/printer [get]
+func (h *PrinterHandler) Get(c *gin.Context) {
+	tenantID, boothID, ok := boothParams(c)
+	if !ok {
+		return
+	}
+
+	printer, err := h.usecase.Get(tenantID, boothID)
+	if err != nil {
+		writePrinterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil printer booth", printer)
+}
+
+// Remove godoc
+// @Summary      Lepas printer booth
+// @Description  Booth kembali memakai printer default server.
+// @Tags         Printers
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Booth ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/booths/{id}/printer [delete]
+func (h *PrinterHandler) Remove(c *gin.Context) {
+	tenantID, boothID, ok := boothParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Remove(tenantID, boothID); err != nil {
+		writePrinterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Printer booth dilepas", nil)
+}
+
+// Reload godoc
+// @Summary      Ganti roll printer
+// @Description  Sisa media diisi ulang ke prints_per_roll, atau ke remaining kalau diisi. Flag media menipis ikut mati.
+// @Tags         Printers
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Booth ID"
+// @Param        request  body      domain.ReloadPrinterRequest  false  "Sisa media"
+// @Success      200      {object}  response.Response
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/booths/{id}/printer/reload [post]
+func (h *PrinterHandler) Reload(c *gin.Context) {
+	tenantID, boothID, ok := boothParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ReloadPrinterRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Validation(c, err)
+			return
+		}
+	}
+
+	printer, err := h.usecase.Reload(tenantID, boothID, req)
+	if err != nil {
+		writePrinterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Media printer diisi ulang", printer)
+}
+
+// Report godoc
+// @Summary      Laporan status printer dari booth
+// @Description  Aplikasi booth mengirim status printer lokal. remaining (kalau ada) menimpa hitungan server; reason media-low/media-empty menyalakan flag media menipis.
+// @Tags         Printers
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.PrinterReport  true  "Status printer"
+// @Success      200      {object}  response.Response
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/booths/me/printer/status [post]
+func (h *PrinterHandler) Report(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	var req domain.PrinterReport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	printer, err := h.usecase.Report(tenantID, boothID, req)
+	if err != nil {
+		writePrinterError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Status printer tersimpan", printer)
+}
+
+func boothParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	boothID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Booth tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, boothID, true
+}
+
+func writePrinterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrBoothNotFound):
+		response.Error(c, http.StatusNotFound, "Booth tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrPrinterNotFound):
+		response.Error(c, http.StatusNotFound, "Booth belum punya printer", nil)
+	case errors.Is(err, usecase.ErrInvalidPrinter):
+		response.Error(c, http.StatusBadRequest, "Pengaturan printer tidak valid", err.Error())
+	default:
+		slog.Error("Gagal memproses printer booth", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses printer booth", err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrinterRepository interface {
+	Save(printer *domain.Printer) error
+	FindByBooth(tenantID, boothID uuid.UUID) (*domain.Printer, error)
+	Delete(tenantID, boothID uuid.UUID) error
+	Consume(id uuid.UUID, sheets int) (*domain.Printer, error)
+	SetRemaining(id uuid.UUID, remaining int) (*domain.Printer, error)
+	UpdateReport(id uuid.UUID, state string, reasons []string, at time.Time) error
+	SetLowMedia(id uuid.UUID, low bool) (bool, error)
+}
+
+type printerRepository struct {
+	db *gorm.DB
+}
+
+func NewPrinterRepository(db *gorm.DB) PrinterRepository {
+	return &printerRepository{db}
+}
+
+func (r *printerRepository) Save(printer *domain.Printer) error {
+	return r.db.Save(printer).Error
+}
+
+func (r *printerRepository) FindByBooth(tenantID, boothID uuid.UUID) (*domain.Printer, error) {
+	var printer domain.Printer
+	err := r.db.Where("tenant_id = ? AND booth_id = ?", tenantID, boothID).First(&printer).Error
+	return &printer, err
+}
+
+func (r *printerRepository) Delete(tenantID, boothID uuid.UUID) error {
+	res := r.db.Where("tenant_id = ? AND booth_id = ?", tenantID, boothID).Delete(&domain.Printer{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Consume mengurangi sisa media secara atomik, jadi job yang selesai
+// bersamaan di beberapa worker tidak saling menimpa hitungan.
+func (r *printerRepository) Consume(id uuid.UUID, sheets int) (*domain.Printer, error) {
+	var printer domain.Printer
+	err := r.db.Model(&printer).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Update("remaining", gorm.Expr("GREATEST(remaining - ?, 0)", sheets)).Error
+	return &printer, err
+}
+
+func (r *printerRepository) SetRemaining(id uuid.UUID, remaining int) (*domain.Printer, error) {
+	var printer domain.Printer
+	err := r.db.Model(&printer).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Update("remaining", remaining).Error
+	return &printer, err
+}
+
+func (r *printerRepository) UpdateReport(id uuid.UUID, state string, reasons []string, at time.Time) error {
+	return r.db.Model(&domain.Printer{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"state":         state,
+		"state_reasons": strings.Join(reasons, ","),
+		"reported_at":   at,
+	}).Error
+}
+
+// SetLowMedia mengembalikan true hanya kalau flag benar-benar berubah, supaya
+// notifikasi media menipis dikirim sekali per roll.
+func (r *printerRepository) SetLowMedia(id uuid.UUID, low bool) (bool, error) {
+	res := r.db.Model(&domain.Printer{}).
+		Where("id = ? AND low_media <> ?", id, low).
+		Update("low_media", low)
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	nUcase "photobooth-core/internal/notification/usecase"
+	"photobooth-core/internal/printer/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBoothNotFound   = errors.New("booth tidak ditemukan")
+	ErrPrinterNotFound = errors.New("booth belum punya printer")
+	ErrInvalidPrinter  = errors.New("pengaturan printer tidak valid")
+)
+
+type PrinterUsecase interface {
+	Set(tenantID, boothID uuid.UUID, req domain.UpdatePrinterRequest) (*domain.Printer, error)
+	Get(tenantID, boothID uuid.UUID) (*domain.Printer, error)
+	Remove(tenantID, boothID uuid.UUID) error
+	Reload(tenantID, boothID uuid.UUID, req domain.ReloadPrinterRequest) (*domain.Printer, error)
+	Report(tenantID, boothID uuid.UUID, req domain.PrinterReport) (*domain.Printer, error)
+	Consume(tenantID, boothID uuid.UUID, sheets int) error
+}
+
+type printerUsecase struct {
+	repo          repository.PrinterRepository
+	boothRepo     bRepo.BoothRepository
+	notifications nUcase.NotificationUsecase
+}
+
+func NewPrinterUsecase(repo repository.PrinterRepository, boothRepo bRepo.BoothRepository, notifications nUcase.NotificationUsecase) PrinterUsecase {
+	return &printerUsecase{repo, boothRepo, notifications}
+}
+
+// Set memasang atau mengganti printer booth. Printer baru dianggap memakai
+// roll penuh; mengganti pengaturan printer yang sudah ada tidak mengubah sisa media.
+func (u *printerUsecase) Set(tenantID, boothID uuid.UUID, req domain.UpdatePrinterRequest) (*domain.Printer, error) {
+	if _, err := u.boothRepo.FindByID(tenantID, boothID); err != nil {
+		return nil, ErrBoothNotFound
+	}
+	if req.LowMediaThreshold > 0 && req.PrintsPerRoll > 0 && req.LowMediaThreshold >= req.PrintsPerRoll {
+		return nil, fmt.Errorf("%w: batas media menipis harus di bawah jumlah cetak per roll", ErrInvalidPrinter)
+	}
+
+	printer, err := u.repo.FindByBooth(tenantID, boothID)
+	if err != nil {
+		printer = &domain.Printer{
+			ID:        uuid.New(),
+			TenantID:  tenantID,
+			BoothID:   boothID,
+			Remaining: req.PrintsPerRoll,
+		}
+	}
+	printer.Name = req.Name
+	printer.Driver = req.Driver
+	printer.URI, printer.Queue = "", ""
+	switch req.Driver {
+	case "ipp":
+		printer.URI = req.URI
+	case "sumatra":
+		printer.Queue = req.Queue
+	}
+	printer.MediaSize = req.MediaSize
+	printer.PrintsPerRoll = req.PrintsPerRoll
+	printer.LowMediaThreshold = req.LowMediaThreshold
+	if printer.Remaining > printer.PrintsPerRoll {
+		printer.Remaining = printer.PrintsPerRoll
+	}
+
+	if err := u.repo.Save(printer); err != nil {
+		return nil, err
+	}
+	return printer, u.checkMedia(printer, nil)
+}
+
+func (u *printerUsecase) Get(tenantID, boothID uuid.UUID) (*domain.Printer, error) {
+	printer, err := u.repo.FindByBooth(tenantID, boothID)
+	if err != nil {
+		return nil, ErrPrinterNotFound
+	}
+	return printer, nil
+}
+
+// Remove melepas printer booth; booth kembali memakai printer default server.
+func (u *printerUsecase) Remove(tenantID, boothID uuid.UUID) error {
+	if err := u.repo.Delete(tenantID, boothID); err != nil {
+		return ErrPrinterNotFound
+	}
+	return nil
+}
+
+// Reload dipanggil setelah ganti roll atau kertas.
+func (u *printerUsecase) Reload(tenantID, boothID uuid.UUID, req domain.ReloadPrinterRequest) (*domain.Printer, error) {
+	printer, err := u.Get(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := printer.PrintsPerRoll
+	if req.Remaining != nil {
+		remaining = *req.Remaining
+	}
+
+	printer, err = u.repo.SetRemaining(printer.ID, remaining)
+	if err != nil {
+		return nil, err
+	}
+	return printer, u.checkMedia(printer, nil)
+}
+
+// Report menyimpan status printer dari aplikasi booth. Sisa media dari booth
+// (misalnya dibaca dari driver DNP) lebih akurat daripada hitungan server,
+// jadi langsung menimpa Remaining.
+func (u *printerUsecase) Report(tenantID, boothID uuid.UUID, req domain.PrinterReport) (*domain.Printer, error) {
+	printer, err := u.Get(tenantID, boothID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := u.repo.UpdateReport(printer.ID, req.State, req.Reasons, now); err != nil {
+		return nil, err
+	}
+	printer.State = req.State
+	printer.ReportedAt = &now
+
+	remaining := req.Remaining
+	if remaining == nil && slices.Contains(req.Reasons, "media-empty") {
+		empty := 0
+		remaining = &empty
+	}
+	if remaining != nil {
+		if printer, err = u.repo.SetRemaining(printer.ID, *remaining); err != nil {
+			return nil, err
+		}
+	}
+	return printer, u.checkMedia(printer, req.Reasons)
+}
+
+// Consume mengurangi sisa media setelah job cetak selesai. Booth tanpa
+// printer terdaftar atau tanpa prints-per-roll tidak dihitung.
+func (u *printerUsecase) Consume(tenantID, boothID uuid.UUID, sheets int) error {
+	printer, err := u.repo.FindByBooth(tenantID, boothID)
+	if err != nil || !printer.Tracked() {
+		return nil
+	}
+	printer, err = u.repo.Consume(printer.ID, sheets)
+	if err != nil {
+		return err
+	}
+	return u.checkMedia(printer, nil)
+}
+
+// checkMedia menyalakan flag media menipis (sekali per roll, lalu memberi
+// tahu tenant) atau mematikannya lagi setelah roll diganti.
+func (u *printerUsecase) checkMedia(printer *domain.Printer, reasons []string) error {
+	low := printer.Tracked() && printer.Remaining < printer.Threshold()
+	if slices.Contains(reasons, "media-low") || slices.Contains(reasons, "media-empty") {
+		low = true
+	}
+
+	changed, err := u.repo.SetLowMedia(printer.ID, low)
+	if err != nil {
+		return err
+	}
+	printer.LowMedia = low
+	if !changed || !low {
+		return nil
+	}
+
+	boothName := printer.BoothID.String()
+	if booth, err := u.boothRepo.FindByID(printer.TenantID, printer.BoothID); err == nil {
+		boothName = booth.Name
+	}
+	message := fmt.Sprintf("Printer %s di booth %s hampir kehabisan media.", printer.Name, boothName)
+	if printer.Tracked() {
+		message = fmt.Sprintf("Printer %s di booth %s tinggal %d lembar dari %d. Siapkan roll pengganti.", printer.Name, boothName, printer.Remaining, printer.PrintsPerRoll)
+	}
+	return u.notifications.Notify(printer.TenantID, &printer.BoothID, domain.NotificationPrinterLowMedia, "Media printer menipis", message)
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/qrcode"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	"photobooth-core/internal/shortlink/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShortLinkHandler struct {
+	usecase usecase.ShortLinkUsecase
+}
+
+func NewShortLinkHandler(u usecase.ShortLinkUsecase) *ShortLinkHandler {
+	return &ShortLinkHandler{u}
+}
+
+// Redirect godoc
+// @Summary      Buka short link dari QR
+// @Description  Mencatat scan lalu redirect ke link bertanda tangan terbaru (foto, galeri sesi, atau galeri acara).
+// @Tags         Short Links
+// @Param        code  path  string  true  "Kode short link"
+// @Success      302
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /s/{code} [get]
+func (h *ShortLinkHandler) Redirect(c *gin.Context) {
+	target, err := h.usecase.Resolve(c.Request.Context(), c.Param("code"), c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, usecase.ErrTargetNotFound) {
+			response.Error(c, http.StatusNotFound, "Link tidak ditemukan", nil)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Gagal membuka link", err.Error())
+		return
+	}
+
+	// Jangan di-cache: URL tujuan berganti setiap kali tanda tangannya diperbarui
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target)
+}
+
+// Create godoc
+// @Summary      Buat short link
+// @Description  Foto dan sesi selalu punya satu kode yang sama; link acara dibuat baru untuk tiap rentang waktu.
+// @Tags         Short Links
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.CreateShortLinkRequest  true  "Target link"
+// @Success      201      {object}  response.Response
+// @Failure      404      {object}  response.ErrorResponse
+// @Router       /api/v1/shortlinks [post]
+func (h *ShortLinkHandler) Create(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.CreateShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	link, err := h.usecase.CreateLink(tenantID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrTargetNotFound):
+			response.Error(c, http.StatusNotFound, "Target tidak ditemukan", err.Error())
+		case errors.Is(err, usecase.ErrInvalidEventWindow):
+			response.Error(c, http.StatusBadRequest, "Rentang waktu acara tidak valid", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Gagal membuat short link", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Short link siap dipakai", link)
+}
+
+// List godoc
+// @Summary      Daftar short link beserta jumlah scan
+// @Tags         Short Links
+// @Security     BearerAuth
+// @Produce      json
+// @Param        limit   query  int  false  "Jumlah data"
+// @Param        offset  query  int  false  "Offset data"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/shortlinks [get]
+func (h *ShortLinkHandler) List(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	links, err := h.usecase.ListLinks(tenantID, limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil short link", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil short link", links)
+}
+
+// Stats godoc
+// @Summary      Statistik scan per booth atau per frame
+// @Tags         Short Links
+// @Security     BearerAuth
+// @Produce      json
+// @Param        group_by  query  string  false  "booth (default) atau frame"
+// @Success      200  {object}  response.Response
+// @Router       /api/v1/shortlinks/stats [get]
+func (h *ShortLinkHandler) Stats(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", "booth")
+	if groupBy != "booth" && groupBy != "frame" {
+		response.Error(c, http.StatusBadRequest, "group_by harus booth atau frame", nil)
+		return
+	}
+
+	stats, err := h.usecase.Stats(tenantID, groupBy)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal mengambil statistik", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil statistik scan", stats)
+}
+
+// LinkQR godoc
+// @Summary      QR code untuk short link
+// @Tags         Short Links
+// @Security     BearerAuth
+// @Produce      image/png
+// @Produce      image/svg+xml
+// @Param        code    path   string  true   "Kode short link"
+// @Param        format  query  string  false  "png atau svg"
+// @Param        size    query  int     false  "Ukuran pixel (128-2048)"
+// @Param        level   query  string  false  "Koreksi error: L, M, Q, H"
+// @Param        logo    query  bool    false  "Tempel logo tenant di tengah"
+// @Success      200
+// @Router       /api/v1/shortlinks/{code}/qr [get]
+func (h *ShortLinkHandler) LinkQR(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.QRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	data, contentType, err := h.usecase.LinkQR(c.Request.Context(), tenantID, c.Param("code"), req)
+	writeQR(c, data, contentType, err)
+}
+
+// PhotoQR godoc
+// @Summary      QR code untuk link download foto
+// @Tags         Media
+// @Security     BearerAuth
+// @Produce      image/png
+// @Produce      image/svg+xml
+// @Param        id      path   string  true   "Photo ID"
+// @Param        format  query  string  false  "png atau svg"
+// @Param        size    query  int     false  "Ukuran pixel (128-2048)"
+// @Param        level   query  string  false  "Koreksi error: L, M, Q, H"
+// @Param        logo    query  bool    false  "Tempel logo tenant di tengah"
+// @Success      200
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/photos/{id}/qr [get]
+func (h *ShortLinkHandler) PhotoQR(c *gin.Context) {
+	h.serveTargetQR(c, h.usecase.PhotoQR)
+}
+
+// SessionQR godoc
+// @Summary      QR code untuk galeri satu sesi
+// @Tags         Media
+// @Security     BearerAuth
+// @Produce      image/png
+// @Produce      image/svg+xml
+// @Param        id      path   string  true   "Transaction ID"
+// @Param        format  query  string  false  "png atau svg"
+// @Param        size    query  int     false  "Ukuran pixel (128-2048)"
+// @Param        level   query  string  false  "Koreksi error: L, M, Q, H"
+// @Param        logo    query  bool    false  "Tempel logo tenant di tengah"
+// @Success      200
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/qr [get]
+func (h *ShortLinkHandler) SessionQR(c *gin.Context) {
+	h.serveTargetQR(c, h.usecase.SessionQR)
+}
+
+type qrGenerator func(ctx context.Context, tenantID, id uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error)
+
+func (h *ShortLinkHandler) serveTargetQR(c *gin.Context, generate qrGenerator) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "ID tidak valid", err.Error())
+		return
+	}
+
+	var req domain.QRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	data, contentType, err := generate(c.Request.Context(), tenantID, id, req)
+	writeQR(c, data, contentType, err)
+}
+
+func writeQR(c *gin.Context, data []byte, contentType string, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, qrcode.ErrInvalidOptions):
+			response.Error(c, http.StatusBadRequest, "Opsi QR code tidak valid", err.Error())
+		case errors.Is(err, usecase.ErrTargetNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+			response.Error(c, http.StatusNotFound, "Data tidak ditemukan", nil)
+		default:
+			response.Error(c, http.StatusInternalServerError, "Gagal membuat QR code", err.Error())
+		}
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
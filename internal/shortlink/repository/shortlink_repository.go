@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShortLinkRepository interface {
+	Create(link *domain.ShortLink) error
+	FindByCode(code string) (*domain.ShortLink, error)
+	FindByTarget(tenantID uuid.UUID, targetType domain.ShortLinkTarget, targetID uuid.UUID) (*domain.ShortLink, error)
+	FindByTenant(tenantID uuid.UUID, limit, offset int) ([]domain.ShortLink, error)
+	RecordScan(linkID uuid.UUID, userAgent string, at time.Time) error
+	Stats(tenantID uuid.UUID, groupBy string) ([]domain.ShortLinkStat, error)
+}
+
+type shortLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewShortLinkRepository(db *gorm.DB) ShortLinkRepository {
+	return &shortLinkRepository{db}
+}
+
+func (r *shortLinkRepository) Create(link *domain.ShortLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *shortLinkRepository) FindByCode(code string) (*domain.ShortLink, error) {
+	var link domain.ShortLink
+	err := r.db.Where("code = ?", code).First(&link).Error
+	return &link, err
+}
+
+func (r *shortLinkRepository) FindByTarget(tenantID uuid.UUID, targetType domain.ShortLinkTarget, targetID uuid.UUID) (*domain.ShortLink, error) {
+	var link domain.ShortLink
+	err := r.db.Where("tenant_id = ? AND target_type = ? AND target_id = ?", tenantID, targetType, targetID).
+		Order("created_at ASC").First(&link).Error
+	return &link, err
+}
+
+func (r *shortLinkRepository) FindByTenant(tenantID uuid.UUID, limit, offset int) ([]domain.ShortLink, error) {
+	links := []domain.ShortLink{}
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("scan_count DESC, created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&links).Error
+	return links, err
+}
+
+// RecordScan menaikkan counter secara atomic dan menyimpan jejak scan-nya.
+func (r *shortLinkRepository) RecordScan(linkID uuid.UUID, userAgent string, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.ShortLink{}).Where("id = ?", linkID).Updates(map[string]interface{}{
+			"scan_count":       gorm.Expr("scan_count + 1"),
+			"first_scanned_at": gorm.Expr("COALESCE(first_scanned_at, ?)", at),
+			"last_scanned_at":  at,
+			"last_user_agent":  userAgent,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&domain.ShortLinkScan{
+			ID:          uuid.New(),
+			ShortLinkID: linkID,
+			UserAgent:   userAgent,
+			ScannedAt:   at,
+		}).Error
+	})
+}
+
+// Stats mengelompokkan scan per booth atau per frame, diurutkan dari yang paling banyak.
+func (r *shortLinkRepository) Stats(tenantID uuid.UUID, groupBy string) ([]domain.ShortLinkStat, error) {
+	column := "booth_id::text"
+	if groupBy == "frame" {
+		column = "frame_name"
+	}
+
+	stats := []domain.ShortLinkStat{}
+	err := r.db.Model(&domain.ShortLink{}).
+		Select("COALESCE("+column+", '') AS key, COUNT(*) AS links, "+
+			"COUNT(*) FILTER (WHERE scan_count > 0) AS scanned_links, COALESCE(SUM(scan_count), 0) AS scans").
+		Where("tenant_id = ?", tenantID).
+		Group(column).
+		Order("scans DESC").
+		Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"image"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	_ "image/png"
+
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	mUcase "photobooth-core/internal/media/usecase"
+	"photobooth-core/internal/platform/qrcode"
+	"photobooth-core/internal/platform/storage"
+	"photobooth-core/internal/shortlink/repository"
+	trRepo "photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	codeLength   = 7
+	codeAlphabet = "23456789abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ" // tanpa 0/O/1/l/I biar gampang dibaca
+)
+
+var (
+	ErrTargetNotFound     = errors.New("target short link tidak ditemukan")
+	ErrInvalidEventWindow = errors.New("link acara butuh starts_at dan ends_at yang valid")
+)
+
+type ShortLinkUsecase interface {
+	CreateLink(tenantID uuid.UUID, req domain.CreateShortLinkRequest) (*domain.ShortLink, error)
+	ListLinks(tenantID uuid.UUID, limit, offset int) ([]domain.ShortLink, error)
+	Stats(tenantID uuid.UUID, groupBy string) ([]domain.ShortLinkStat, error)
+	Resolve(ctx context.Context, code, userAgent string) (string, error)
+	LinkQR(ctx context.Context, tenantID uuid.UUID, code string, req domain.QRCodeRequest) ([]byte, string, error)
+	PhotoQR(ctx context.Context, tenantID, photoID uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error)
+	SessionQR(ctx context.Context, tenantID, trxID uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error)
+}
+
+type shortLinkUsecase struct {
+	repo       repository.ShortLinkRepository
+	media      mUcase.MediaUsecase
+	trxRepo    trRepo.TransactionRepository
+	boothRepo  bRepo.BoothRepository
+	tenantRepo domain.TenantRepository
+	storage    storage.Storage
+	baseURL    string
+}
+
+func NewShortLinkUsecase(repo repository.ShortLinkRepository, media mUcase.MediaUsecase, trxRepo trRepo.TransactionRepository,
+	boothRepo bRepo.BoothRepository, tenantRepo domain.TenantRepository, store storage.Storage, baseURL string) ShortLinkUsecase {
+	return &shortLinkUsecase{repo, media, trxRepo, boothRepo, tenantRepo, store, strings.TrimRight(baseURL, "/")}
+}
+
+// CreateLink membuat short link baru. Untuk foto dan sesi, link yang sudah
+// ada dipakai ulang supaya satu target cuma punya satu kode (dan satu statistik).
+func (u *shortLinkUsecase) CreateLink(tenantID uuid.UUID, req domain.CreateShortLinkRequest) (*domain.ShortLink, error) {
+	link := &domain.ShortLink{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+	}
+
+	switch req.TargetType {
+	case domain.LinkTargetPhoto:
+		photo, err := u.media.GetPhoto(tenantID, req.TargetID)
+		if err != nil {
+			return nil, ErrTargetNotFound
+		}
+		link.BoothID = &photo.BoothID
+		link.FrameName = photo.FrameName
+
+	case domain.LinkTargetSession:
+		trx, err := u.trxRepo.FindByID(tenantID, req.TargetID)
+		if err != nil {
+			return nil, ErrTargetNotFound
+		}
+		link.BoothID = &trx.BoothID
+
+	case domain.LinkTargetEvent:
+		if req.StartsAt == nil || req.EndsAt == nil || !req.EndsAt.After(*req.StartsAt) {
+			return nil, ErrInvalidEventWindow
+		}
+		booth, err := u.boothRepo.FindByID(tenantID, req.TargetID)
+		if err != nil {
+			return nil, ErrTargetNotFound
+		}
+		link.BoothID = &booth.ID
+		link.EventStartsAt = req.StartsAt
+		link.EventEndsAt = req.EndsAt
+
+	default:
+		return nil, ErrTargetNotFound
+	}
+
+	if req.TargetType != domain.LinkTargetEvent {
+		existing, err := u.repo.FindByTarget(tenantID, req.TargetType, req.TargetID)
+		if err == nil {
+			u.attachURL(existing)
+			return existing, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	code, err := u.newCode()
+	if err != nil {
+		return nil, err
+	}
+	link.Code = code
+	link.CreatedAt = time.Now()
+	link.UpdatedAt = time.Now()
+
+	if err := u.repo.Create(link); err != nil {
+		return nil, err
+	}
+
+	u.attachURL(link)
+	return link, nil
+}
+
+func (u *shortLinkUsecase) ListLinks(tenantID uuid.UUID, limit, offset int) ([]domain.ShortLink, error) {
+	links, err := u.repo.FindByTenant(tenantID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		u.attachURL(&links[i])
+	}
+	return links, nil
+}
+
+func (u *shortLinkUsecase) Stats(tenantID uuid.UUID, groupBy string) ([]domain.ShortLinkStat, error) {
+	return u.repo.Stats(tenantID, groupBy)
+}
+
+// Resolve mencatat scan lalu mengembalikan URL bertanda tangan terbaru untuk target.
+func (u *shortLinkUsecase) Resolve(ctx context.Context, code, userAgent string) (string, error) {
+	link, err := u.repo.FindByCode(code)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := u.targetURL(link)
+	if err != nil {
+		return "", err
+	}
+
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	// Gagal mencatat analitik jangan sampai bikin tamu gagal unduh foto
+	if err := u.repo.RecordScan(link.ID, userAgent, time.Now()); err != nil {
+		slog.Error("Gagal mencatat scan short link", "code", code, "error", err)
+	}
+
+	return target, nil
+}
+
+func (u *shortLinkUsecase) LinkQR(ctx context.Context, tenantID uuid.UUID, code string, req domain.QRCodeRequest) ([]byte, string, error) {
+	link, err := u.repo.FindByCode(code)
+	if err != nil || link.TenantID != tenantID {
+		return nil, "", ErrTargetNotFound
+	}
+	u.attachURL(link)
+	return u.renderQR(ctx, tenantID, link.ShortURL, req)
+}
+
+func (u *shortLinkUsecase) PhotoQR(ctx context.Context, tenantID, photoID uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error) {
+	return u.targetQR(ctx, tenantID, domain.LinkTargetPhoto, photoID, req)
+}
+
+func (u *shortLinkUsecase) SessionQR(ctx context.Context, tenantID, trxID uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error) {
+	return u.targetQR(ctx, tenantID, domain.LinkTargetSession, trxID, req)
+}
+
+// targetQR memastikan target punya short link lalu merender QR dari URL pendeknya,
+// karena QR dari URL bertanda tangan yang panjang terlalu rapat untuk dicetak.
+func (u *shortLinkUsecase) targetQR(ctx context.Context, tenantID uuid.UUID, targetType domain.ShortLinkTarget, targetID uuid.UUID, req domain.QRCodeRequest) ([]byte, string, error) {
+	link, err := u.CreateLink(tenantID, domain.CreateShortLinkRequest{TargetType: targetType, TargetID: targetID})
+	if err != nil {
+		return nil, "", err
+	}
+	return u.renderQR(ctx, tenantID, link.ShortURL, req)
+}
+
+func (u *shortLinkUsecase) targetURL(link *domain.ShortLink) (string, error) {
+	switch link.TargetType {
+	case domain.LinkTargetPhoto:
+		photo, err := u.media.GetPhoto(link.TenantID, link.TargetID)
+		if err != nil {
+			return "", err
+		}
+		return photo.DownloadURL, nil
+	case domain.LinkTargetSession:
+		return u.media.SessionURL(link.TenantID, link.TargetID)
+	case domain.LinkTargetEvent:
+		if link.EventStartsAt == nil || link.EventEndsAt == nil {
+			return "", ErrInvalidEventWindow
+		}
+		return u.media.EventGalleryURL(link.TenantID, link.TargetID, *link.EventStartsAt, *link.EventEndsAt)
+	default:
+		return "", ErrTargetNotFound
+	}
+}
+
+func (u *shortLinkUsecase) renderQR(ctx context.Context, tenantID uuid.UUID, content string, req domain.QRCodeRequest) ([]byte, string, error) {
+	opts := qrcode.Options{Format: req.Format, Size: req.Size, Level: req.Level}
+
+	if req.Logo {
+		logo, err := u.tenantLogo(ctx, tenantID)
+		if err != nil {
+			return nil, "", err
+		}
+		opts.Logo = logo
+	}
+
+	return qrcode.Generate(content, opts)
+}
+
+// tenantLogo membaca logo tenant dari storage. Tenant tanpa logo
+// tetap dapat QR polos, bukan error.
+func (u *shortLinkUsecase) tenantLogo(ctx context.Context, tenantID uuid.UUID) (image.Image, error) {
+	tenant, err := u.tenantRepo.FindByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.LogoKey == "" {
+		return nil, nil
+	}
+
+	body, _, err := u.storage.Get(ctx, tenant.LogoKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	logo, _, err := image.Decode(body)
+	return logo, err
+}
+
+func (u *shortLinkUsecase) attachURL(link *domain.ShortLink) {
+	link.ShortURL = u.baseURL + "/s/" + link.Code
+}
+
+// newCode membuat kode acak dan memastikan belum dipakai.
+func (u *shortLinkUsecase) newCode() (string, error) {
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for attempt := 0; attempt < 5; attempt++ {
+		var b strings.Builder
+		for i := 0; i < codeLength; i++ {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(codeAlphabet[n.Int64()])
+		}
+
+		code := b.String()
+		if _, err := u.repo.FindByCode(code); errors.Is(err, gorm.ErrRecordNotFound) {
+			return code, nil
+		}
+	}
+	return "", errors.New("gagal membuat kode short link unik")
+}
@@ -2,10 +2,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"photobooth-core/internal/domain"
 	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -53,3 +55,78 @@ func (h *TenantHandler) Register(c *gin.Context) {
 		"admin":  user,
 	})
 }
+
+// UploadLogo godoc
+// @Summary      Upload logo tenant
+// @Description  Logo dipakai di tengah QR code foto dan galeri. Format PNG/JPEG, maksimal 1024x1024.
+// @Tags         Tenants
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        logo  formData  file  true  "File logo"
+// @Success      200   {object}  response.Response
+// @Failure      400   {object}  response.ErrorResponse
+// @Router       /api/v1/tenants/logo [put]
+func (h *TenantHandler) UploadLogo(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	file, err := c.FormFile("logo")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "File logo wajib diisi", err.Error())
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Gagal membaca file logo", err.Error())
+		return
+	}
+	defer src.Close()
+
+	tenant, err := h.tenantUsecase.UploadLogo(c.Request.Context(), tenantID, src)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLogo) {
+			response.Error(c, http.StatusBadRequest, "Logo tidak valid", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Gagal menyimpan logo", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logo tenant berhasil disimpan", tenant)
+}
+
+// SetReprintPIN godoc
+// @Summary      Atur PIN cetak ulang
+// @Description  PIN (6-8 digit) dimasukkan staf di booth untuk mencetak melebihi jatah sesi. Booth yang salah PIN 5 kali dalam 15 menit dikunci sementara. Hanya user dashboard yang bisa mengubahnya.
+// @Tags         Tenants
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.SetReprintPINRequest  true  "PIN baru"
+// @Success      200      {object}  response.Response
+// @Failure      400      {object}  response.ErrorResponse
+// @Router       /api/v1/tenants/reprint-pin [put]
+func (h *TenantHandler) SetReprintPIN(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.SetReprintPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	if err := h.tenantUsecase.SetReprintPIN(tenantID, req.PIN); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Gagal menyimpan PIN", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "PIN cetak ulang berhasil disimpan", nil)
+}
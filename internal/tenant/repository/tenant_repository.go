@@ -4,6 +4,7 @@ package repository
 import (
 	"photobooth-core/internal/domain"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -23,3 +24,20 @@ func NewTenantRepository(db *gorm.DB) domain.TenantRepository {
 func (r *tenantRepository) Create(tenant *domain.Tenant) error {
 	return r.db.Create(tenant).Error
 }
+
+// FindByID mengambil satu Tenant berdasarkan ID.
+func (r *tenantRepository) FindByID(id uuid.UUID) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	err := r.db.Where("id = ?", id).First(&tenant).Error
+	return &tenant, err
+}
+
+// UpdateLogo menyimpan key logo tenant di storage.
+func (r *tenantRepository) UpdateLogo(id uuid.UUID, logoKey string) error {
+	return r.db.Model(&domain.Tenant{}).Where("id = ?", id).Update("logo_key", logoKey).Error
+}
+
+// UpdateReprintPIN menyimpan hash PIN cetak ulang tenant.
+func (r *tenantRepository) UpdateReprintPIN(id uuid.UUID, pinHash string) error {
+	return r.db.Model(&domain.Tenant{}).Where("id = ?", id).Update("reprint_pin_hash", pinHash).Error
+}
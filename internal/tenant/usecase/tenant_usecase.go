@@ -1,8 +1,17 @@
 package usecase
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
 	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/storage"
+
+	_ "image/jpeg"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -13,14 +22,19 @@ type tenantUsecase struct {
 	tenantRepo domain.TenantRepository
 	userRepo   domain.UserRepository
 	db         *gorm.DB // Butuh instance DB untuk transaksi
+	storage    storage.Storage
 }
 
+// maxLogoSide membatasi dimensi logo, logo cuma dipakai kecil di tengah QR.
+const maxLogoSide = 1024
+
 // NewTenantUsecase sekarang menerima dua repository.
-func NewTenantUsecase(tr domain.TenantRepository, ur domain.UserRepository, db *gorm.DB) domain.TenantUsecase {
+func NewTenantUsecase(tr domain.TenantRepository, ur domain.UserRepository, db *gorm.DB, store storage.Storage) domain.TenantUsecase {
 	return &tenantUsecase{
 		tenantRepo: tr,
 		userRepo:   ur,
 		db:         db,
+		storage:    store,
 	}
 }
 
@@ -68,3 +82,45 @@ func (u *tenantUsecase) RegisterTenant(req domain.RegisterTenantRequest) (*domai
 
 	return newTenant, newUser, nil
 }
+
+// UploadLogo menyimpan logo tenant sebagai PNG di storage.
+// Gambar selalu di-decode ulang supaya yang tersimpan pasti gambar valid.
+func (u *tenantUsecase) UploadLogo(ctx context.Context, tenantID uuid.UUID, file io.Reader) (*domain.Tenant, error) {
+	data, err := io.ReadAll(io.LimitReader(file, 2<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width > maxLogoSide || cfg.Height > maxLogoSide {
+		return nil, domain.ErrInvalidLogo
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrInvalidLogo
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("tenants/%s/logo.png", tenantID)
+	if err := u.storage.Put(ctx, key, &buf, int64(buf.Len()), "image/png"); err != nil {
+		return nil, err
+	}
+	if err := u.tenantRepo.UpdateLogo(tenantID, key); err != nil {
+		return nil, err
+	}
+
+	return u.tenantRepo.FindByID(tenantID)
+}
+
+// SetReprintPIN menyimpan PIN staf dalam bentuk hash, sama seperti password.
+func (u *tenantUsecase) SetReprintPIN(tenantID uuid.UUID, pin string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("gagal memproses PIN")
+	}
+	return u.tenantRepo.UpdateReprintPIN(tenantID, string(hash))
+}
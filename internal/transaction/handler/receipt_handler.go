@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/receipt"
+	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
+	"photobooth-core/internal/transaction/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReceiptHandler struct {
+	usecase usecase.ReceiptUsecase
+}
+
+func NewReceiptHandler(u usecase.ReceiptUsecase) *ReceiptHandler {
+	return &ReceiptHandler{u}
+}
+
+// Receipt godoc
+// @Summary      Struk sesi foto
+// @Description  escpos (default) adalah byte stream siap kirim ke printer thermal booth, sudah termasuk QR galeri sesi dan potong kertas. text dan pdf untuk dilampirkan di email. Hanya untuk sesi yang sudah dibayar.
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Produce      application/octet-stream
+// @Produce      text/plain
+// @Produce      application/pdf
+// @Param        id      path   string  true   "Transaction ID"
+// @Param        format  query  string  false  "escpos, text, atau pdf"
+// @Success      200
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/receipt [get]
+func (h *ReceiptHandler) Receipt(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+		return
+	}
+
+	var req domain.ReceiptRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	file, err := h.usecase.Render(tenantID, trxID, req.Format)
+	if err != nil {
+		writeReceiptError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="`+file.Filename+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// GetTemplate godoc
+// @Summary      Template struk tenant
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response{data=domain.ReceiptTemplate}
+// @Router       /api/v1/receipt-template [get]
+func (h *ReceiptHandler) GetTemplate(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	tmpl, err := h.usecase.GetTemplate(tenantID)
+	if err != nil {
+		writeReceiptError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil template struk", tmpl)
+}
+
+// UpdateTemplate godoc
+// @Summary      Atur template struk tenant
+// @Description  Judul kosong berarti nama tenant. Header dan footer boleh berisi baris baru. tax_rate 0 berarti baris pajak tidak dicetak.
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.UpdateReceiptTemplateRequest  true  "Template struk"
+// @Success      200      {object}  response.Response{data=domain.ReceiptTemplate}
+// @Failure      400      {object}  response.ErrorResponse
+// @Router       /api/v1/receipt-template [put]
+func (h *ReceiptHandler) UpdateTemplate(c *gin.Context) {
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	var req domain.UpdateReceiptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	tmpl, err := h.usecase.UpdateTemplate(tenantID, req)
+	if err != nil {
+		writeReceiptError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Template struk berhasil disimpan", tmpl)
+}
+
+func writeReceiptError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrTransactionNotFound):
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrTransactionNotPaid):
+		response.Error(c, http.StatusConflict, "Sesi belum dibayar", err.Error())
+	case errors.Is(err, usecase.ErrInvalidTimezone), errors.Is(err, receipt.ErrInvalidPaper):
+		response.Error(c, http.StatusBadRequest, "Template struk tidak valid", err.Error())
+	default:
+		slog.Error("Gagal memproses struk", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses struk", err.Error())
+	}
+}
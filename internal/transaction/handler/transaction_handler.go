@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
 	"photobooth-core/internal/domain"
 	"photobooth-core/internal/platform/response"
+	"photobooth-core/internal/platform/utils"
 	"photobooth-core/internal/transaction/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -30,11 +33,174 @@ func (h *TransactionHandler) StartSession(c *gin.Context) {
 	}
 
 	// 2. Eksekusi Usecase
-	res, err := h.usecase.CreateSession(bID.(uuid.UUID), tID.(uuid.UUID), req)
+	res, created, err := h.usecase.CreateSession(bID.(uuid.UUID), tID.(uuid.UUID), req)
 	if err != nil {
+		if errors.Is(err, usecase.ErrReferenceConflict) {
+			response.Error(c, http.StatusConflict, "Reference number sudah dipakai", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrSessionActive) {
+			response.Error(c, http.StatusConflict, "Booth masih punya sesi aktif", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrPackageUnavailable) {
+			response.Error(c, http.StatusUnprocessableEntity, "Paket tidak tersedia di booth ini", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Gagal memulai sesi", err.Error())
 		return
 	}
 
+	// 3. Retry dengan reference_no yang sama: sesi lama dikembalikan apa adanya
+	if !created {
+		response.Success(c, http.StatusOK, "Sesi foto sudah tercatat", res)
+		return
+	}
+
 	response.Success(c, http.StatusCreated, "Sesi foto berhasil dicatat", res)
 }
+
+// ActiveSession godoc
+// @Summary      Sesi aktif booth ini
+// @Description  Dipanggil booth saat reconnect untuk melanjutkan sesi yang belum berakhir. data bernilai null kalau tidak ada sesi aktif.
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  response.Response{data=domain.Transaction}
+// @Router       /api/v1/transactions/active [get]
+func (h *TransactionHandler) ActiveSession(c *gin.Context) {
+	boothID, _ := utils.GetBoothID(c)
+	tenantID, _ := utils.GetTenantID(c)
+
+	trx, err := h.usecase.ActiveSession(tenantID, boothID)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+	if trx == nil {
+		response.Success(c, http.StatusOK, "Tidak ada sesi aktif", nil)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil sesi aktif", trx)
+}
+
+// GetSession godoc
+// @Summary      Detail sesi foto beserta statusnya
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  response.Response{data=domain.Transaction}
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id} [get]
+func (h *TransactionHandler) GetSession(c *gin.Context) {
+	tenantID, trxID, actor, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+
+	trx, err := h.usecase.GetSession(tenantID, trxID, actor)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil sesi", trx)
+}
+
+// Transition godoc
+// @Summary      Pindahkan status sesi foto
+// @Description  Alur: created → awaiting_payment → paid → shooting → rendering → printed → completed. Sesi gratis boleh created → paid; sesi berbayar pindah ke paid lewat webhook payment gateway (atau staff untuk pembayaran tunai), sesi tanpa cetak boleh rendering → completed. failed bisa dari status mana saja yang belum berakhir; cancelled hanya sebelum mulai foto. Sesi yang tertahan terlalu lama di satu status ditandai failed oleh server beserta alasannya.
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Transaction ID"
+// @Param        request  body      domain.TransitionRequest  true  "Status tujuan"
+// @Success      200      {object}  response.Response{data=domain.Transaction}
+// @Failure      402      {object}  response.ErrorResponse
+// @Failure      404      {object}  response.ErrorResponse
+// @Failure      409      {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/status [post]
+func (h *TransactionHandler) Transition(c *gin.Context) {
+	tenantID, trxID, actor, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Validation(c, err)
+		return
+	}
+
+	trx, err := h.usecase.Transition(tenantID, trxID, req, actor)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Status sesi diperbarui", trx)
+}
+
+// History godoc
+// @Summary      Riwayat status sesi foto
+// @Tags         Transactions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  response.Response{data=[]domain.TransactionEvent}
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /api/v1/transactions/{id}/history [get]
+func (h *TransactionHandler) History(c *gin.Context) {
+	tenantID, trxID, actor, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.usecase.History(tenantID, trxID, actor)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Berhasil mengambil riwayat sesi", events)
+}
+
+// sessionParams membaca tenant, ID sesi, dan pelaku (booth untuk token
+// device, user untuk token dashboard).
+func sessionParams(c *gin.Context) (uuid.UUID, uuid.UUID, domain.TransitionActor, bool) {
+	var actor domain.TransitionActor
+	tenantID, err := utils.GetTenantID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return uuid.Nil, uuid.Nil, actor, false
+	}
+	trxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+		return uuid.Nil, uuid.Nil, actor, false
+	}
+
+	if boothID, err := utils.GetBoothID(c); err == nil {
+		actor.BoothID = &boothID
+	} else if userID, err := utils.GetUserID(c); err == nil {
+		actor.UserID = &userID
+	}
+	return tenantID, trxID, actor, true
+}
+
+func writeTransactionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrTransactionNotFound):
+		response.Error(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+	case errors.Is(err, usecase.ErrInvalidTransition):
+		response.Error(c, http.StatusConflict, "Perpindahan status sesi tidak diizinkan", err.Error())
+	case errors.Is(err, usecase.ErrPaymentRequired):
+		response.Error(c, http.StatusPaymentRequired, "Sesi belum dibayar", err.Error())
+	default:
+		slog.Error("Gagal memproses sesi", "error", err)
+		response.Error(c, http.StatusInternalServerError, "Gagal memproses sesi", err.Error())
+	}
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"photobooth-core/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReceiptTemplateRepository interface {
+	FindByTenant(tenantID uuid.UUID) (*domain.ReceiptTemplate, error)
+	Save(tmpl *domain.ReceiptTemplate) error
+}
+
+type receiptTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptTemplateRepository(db *gorm.DB) ReceiptTemplateRepository {
+	return &receiptTemplateRepository{db}
+}
+
+func (r *receiptTemplateRepository) FindByTenant(tenantID uuid.UUID) (*domain.ReceiptTemplate, error) {
+	var tmpl domain.ReceiptTemplate
+	err := r.db.Where("tenant_id = ?", tenantID).First(&tmpl).Error
+	return &tmpl, err
+}
+
+func (r *receiptTemplateRepository) Save(tmpl *domain.ReceiptTemplate) error {
+	return r.db.Save(tmpl).Error
+}
@@ -2,12 +2,22 @@ package repository
 
 import (
 	"photobooth-core/internal/domain"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type TransactionRepository interface {
-	Save(trx *domain.Transaction) error
+	Save(trx *domain.Transaction, event *domain.TransactionEvent) error
+	FindByID(tenantID, id uuid.UUID) (*domain.Transaction, error)
+	FindByReference(tenantID uuid.UUID, referenceNo string) (*domain.Transaction, error)
+	FindActive(tenantID, boothID uuid.UUID) (*domain.Transaction, error)
+	FindStale(statuses []domain.TransactionStatus, before time.Time, limit int) ([]domain.Transaction, error)
+	Transition(id uuid.UUID, from, to domain.TransactionStatus, at time.Time, event *domain.TransactionEvent) (bool, error)
+	Events(transactionID uuid.UUID) ([]domain.TransactionEvent, error)
+	UsePrints(tenantID, id uuid.UUID, n int) (bool, error)
+	RefundPrints(id uuid.UUID, n int) error
 }
 
 type transactionRepository struct {
@@ -18,6 +28,86 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 	return &transactionRepository{db}
 }
 
-func (r *transactionRepository) Save(trx *domain.Transaction) error {
-	return r.db.Create(trx).Error
+// Save menyimpan sesi baru bersama event pembuatannya.
+func (r *transactionRepository) Save(trx *domain.Transaction, event *domain.TransactionEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(trx).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+func (r *transactionRepository) FindByID(tenantID, id uuid.UUID) (*domain.Transaction, error) {
+	var trx domain.Transaction
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&trx).Error
+	return &trx, err
+}
+
+// FindByReference mencari sesi lewat reference_no, yang unik per tenant.
+func (r *transactionRepository) FindByReference(tenantID uuid.UUID, referenceNo string) (*domain.Transaction, error) {
+	var trx domain.Transaction
+	err := r.db.Where("tenant_id = ? AND reference_no = ?", tenantID, referenceNo).First(&trx).Error
+	return &trx, err
+}
+
+func (r *transactionRepository) FindActive(tenantID, boothID uuid.UUID) (*domain.Transaction, error) {
+	var trx domain.Transaction
+	err := r.db.Where("tenant_id = ? AND booth_id = ? AND status IN ?", tenantID, boothID, domain.ActiveStatuses).
+		First(&trx).Error
+	return &trx, err
+}
+
+// FindStale mengambil sesi di salah satu statuses yang tidak berubah sejak before.
+func (r *transactionRepository) FindStale(statuses []domain.TransactionStatus, before time.Time, limit int) ([]domain.Transaction, error) {
+	var trxs []domain.Transaction
+	err := r.db.Where("status IN ? AND updated_at < ?", statuses, before).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&trxs).Error
+	return trxs, err
+}
+
+// Transition memindahkan status hanya kalau sesi masih di status from, jadi
+// dua perpindahan bersamaan tidak saling menimpa. Waktu masuk status dan
+// riwayatnya ditulis di transaksi DB yang sama. Mengembalikan false kalau
+// status sesi sudah berubah.
+func (r *transactionRepository) Transition(id uuid.UUID, from, to domain.TransactionStatus, at time.Time, event *domain.TransactionEvent) (bool, error) {
+	moved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Transaction{}).
+			Where("id = ? AND status = ?", id, from).
+			Updates(map[string]interface{}{
+				"status":             to,
+				to.TimestampColumn(): at,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		moved = true
+		return tx.Create(event).Error
+	})
+	return moved, err
+}
+
+func (r *transactionRepository) Events(transactionID uuid.UUID) ([]domain.TransactionEvent, error) {
+	events := []domain.TransactionEvent{}
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&events).Error
+	return events, err
+}
+
+// UsePrints memakai n lembar dari jatah cetak sesi dalam satu UPDATE, jadi
+// dua permintaan cetak bersamaan tidak bisa melewati quota. Mengembalikan
+// false kalau sisa jatah tidak cukup atau sesi belum dibayar.
+func (r *transactionRepository) UsePrints(tenantID, id uuid.UUID, n int) (bool, error) {
+	res := r.db.Model(&domain.Transaction{}).
+		Where("tenant_id = ? AND id = ? AND status IN ? AND prints_used + ? <= print_quota", tenantID, id, domain.PaidStatuses, n).
+		Update("prints_used", gorm.Expr("prints_used + ?", n))
+	return res.RowsAffected == 1, res.Error
+}
+
+// RefundPrints mengembalikan jatah yang terpakai oleh job yang batal dibuat.
+func (r *transactionRepository) RefundPrints(id uuid.UUID, n int) error {
+	return r.db.Model(&domain.Transaction{}).Where("id = ?", id).
+		Update("prints_used", gorm.Expr("GREATEST(prints_used - ?, 0)", n)).Error
 }
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	bRepo "photobooth-core/internal/booth/repository"
+	"photobooth-core/internal/domain"
+	"photobooth-core/internal/platform/receipt"
+	sUcase "photobooth-core/internal/shortlink/usecase"
+	"photobooth-core/internal/transaction/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTransactionNotPaid = errors.New("struk hanya untuk sesi yang sudah dibayar")
+	ErrInvalidTimezone    = errors.New("zona waktu tidak dikenal")
+)
+
+// ReceiptFile adalah struk yang sudah dirender beserta content type-nya.
+type ReceiptFile struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+type ReceiptUsecase interface {
+	GetTemplate(tenantID uuid.UUID) (*domain.ReceiptTemplate, error)
+	UpdateTemplate(tenantID uuid.UUID, req domain.UpdateReceiptTemplateRequest) (*domain.ReceiptTemplate, error)
+	Render(tenantID, trxID uuid.UUID, format string) (*ReceiptFile, error)
+}
+
+type receiptUsecase struct {
+	repo       repository.ReceiptTemplateRepository
+	trxRepo    repository.TransactionRepository
+	boothRepo  bRepo.BoothRepository
+	tenantRepo domain.TenantRepository
+	links      sUcase.ShortLinkUsecase
+}
+
+func NewReceiptUsecase(repo repository.ReceiptTemplateRepository, trxRepo repository.TransactionRepository, boothRepo bRepo.BoothRepository,
+	tenantRepo domain.TenantRepository, links sUcase.ShortLinkUsecase) ReceiptUsecase {
+	return &receiptUsecase{repo, trxRepo, boothRepo, tenantRepo, links}
+}
+
+func (u *receiptUsecase) GetTemplate(tenantID uuid.UUID) (*domain.ReceiptTemplate, error) {
+	tmpl, err := u.repo.FindByTenant(tenantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.DefaultReceiptTemplate(tenantID), nil
+	}
+	return tmpl, err
+}
+
+func (u *receiptUsecase) UpdateTemplate(tenantID uuid.UUID, req domain.UpdateReceiptTemplateRequest) (*domain.ReceiptTemplate, error) {
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	tmpl := &domain.ReceiptTemplate{
+		TenantID:     tenantID,
+		Title:        req.Title,
+		Header:       req.Header,
+		Footer:       req.Footer,
+		PaperWidth:   req.PaperWidth,
+		TaxLabel:     req.TaxLabel,
+		TaxRate:      req.TaxRate,
+		TaxInclusive: req.TaxInclusive,
+		ShowQR:       req.ShowQR,
+		QRCaption:    req.QRCaption,
+		Timezone:     req.Timezone,
+		UpdatedAt:    time.Now(),
+	}
+	if err := u.repo.Save(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Render menyusun struk sesi sesuai template tenant lalu merendernya ke
+// escpos (default, untuk printer thermal booth), text, atau pdf.
+func (u *receiptUsecase) Render(tenantID, trxID uuid.UUID, format string) (*ReceiptFile, error) {
+	trx, err := u.trxRepo.FindByID(tenantID, trxID)
+	if err != nil {
+		return nil, ErrTransactionNotFound
+	}
+	if !trx.Status.Paid() {
+		return nil, ErrTransactionNotPaid
+	}
+
+	tmpl, err := u.GetTemplate(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := u.tenantRepo.FindByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	booth, err := u.boothRepo.FindByID(tenantID, trx.BoothID)
+	if err != nil {
+		return nil, err
+	}
+
+	var galleryURL string
+	if tmpl.ShowQR {
+		link, err := u.links.CreateLink(tenantID, domain.CreateShortLinkRequest{
+			TargetType: domain.LinkTargetSession,
+			TargetID:   trx.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		galleryURL = link.ShortURL
+	}
+
+	rcpt, err := buildReceipt(tmpl, tenant, booth, trx, galleryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	file := &ReceiptFile{}
+	switch format {
+	case "text":
+		err = rcpt.PlainText(&buf)
+		file.ContentType, file.Filename = "text/plain; charset=utf-8", trx.ReferenceNo+".txt"
+	case "pdf":
+		err = rcpt.PDF(&buf)
+		file.ContentType, file.Filename = "application/pdf", trx.ReferenceNo+".pdf"
+	default:
+		err = rcpt.EscPos(&buf)
+		file.ContentType, file.Filename = "application/octet-stream", trx.ReferenceNo+".bin"
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.Data = buf.Bytes()
+	return file, nil
+}
+
+func buildReceipt(tmpl *domain.ReceiptTemplate, tenant *domain.Tenant, booth *domain.Booth, trx *domain.Transaction, galleryURL string) (*receipt.Receipt, error) {
+	rcpt, err := receipt.New(tmpl.PaperWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := time.Local
+	if tmpl.Timezone != "" {
+		if l, err := time.LoadLocation(tmpl.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	title := tmpl.Title
+	if title == "" {
+		title = tenant.Name
+	}
+	rcpt.Title(title)
+	if tmpl.Header != "" {
+		rcpt.Text(tmpl.Header, receipt.AlignCenter, false)
+	}
+	rcpt.Rule()
+
+	rcpt.Row("Booth", booth.Name, false)
+	rcpt.Row("No. Ref", trx.ReferenceNo, false)
+	rcpt.Row("Tanggal", trx.CreatedAt.In(loc).Format("02/01/2006 15:04"), false)
+	rcpt.Rule()
+
+	subtotal, tax, total := splitTax(trx.Amount, tmpl.TaxRate, tmpl.TaxInclusive)
+	rcpt.Row(itemLabel(trx), rupiah(subtotal), false)
+	if tmpl.TaxRate > 0 {
+		label := tmpl.TaxLabel
+		if label == "" {
+			label = "Pajak"
+		}
+		label += " " + strconv.FormatFloat(tmpl.TaxRate, 'f', -1, 64) + "%"
+		if tmpl.TaxInclusive {
+			label += " (termasuk)"
+		}
+		rcpt.Row(label, rupiah(tax), false)
+	}
+	rcpt.Rule()
+	rcpt.Row("TOTAL", rupiah(total), true)
+	rcpt.Rule()
+
+	if galleryURL != "" {
+		rcpt.Feed(1)
+		if tmpl.QRCaption != "" {
+			rcpt.Text(tmpl.QRCaption, receipt.AlignCenter, false)
+		}
+		rcpt.QR(galleryURL)
+	}
+	if tmpl.Footer != "" {
+		rcpt.Feed(1)
+		rcpt.Text(tmpl.Footer, receipt.AlignCenter, false)
+	}
+	return rcpt, nil
+}
+
+// itemLabel adalah baris item struk dari salinan paket sesi, jadi struk
+// cetak ulang tetap sama walau paketnya sudah diubah.
+func itemLabel(trx *domain.Transaction) string {
+	name := trx.PackageName
+	if name == "" {
+		name = "Sesi foto"
+	}
+	if trx.DigitalOnly {
+		return name + " (digital)"
+	}
+	return fmt.Sprintf("%s (%d cetak)", name, trx.PrintQuota)
+}
+
+// splitTax mengembalikan baris harga, pajak, dan total dalam rupiah bulat.
+// Harga inklusif: total tetap, pajak diambil dari dalamnya. Harga eksklusif:
+// pajak ditambahkan di atas harga.
+func splitTax(amount, rate float64, inclusive bool) (subtotal, tax, total float64) {
+	amount = math.Round(amount)
+	if rate <= 0 {
+		return amount, 0, amount
+	}
+	if inclusive {
+		tax = math.Round(amount * rate / (100 + rate))
+		return amount, tax, amount
+	}
+	tax = math.Round(amount * rate / 100)
+	return amount, tax, amount + tax
+}
+
+// rupiah memformat nominal seperti "Rp 35.000".
+func rupiah(v float64) string {
+	digits := strconv.FormatInt(int64(math.Abs(math.Round(v))), 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	if v < 0 {
+		return "-Rp " + b.String()
+	}
+	return "Rp " + b.String()
+}
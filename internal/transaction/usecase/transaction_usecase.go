@@ -1,40 +1,230 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
 	"photobooth-core/internal/domain"
+	pkgUcase "photobooth-core/internal/pricing/usecase"
 	"photobooth-core/internal/transaction/repository"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrTransactionNotFound = errors.New("transaksi tidak ditemukan")
+	ErrInvalidTransition   = errors.New("perpindahan status sesi tidak diizinkan")
+	ErrReferenceConflict   = errors.New("reference_no sudah dipakai untuk sesi dengan data berbeda")
+	ErrSessionActive       = errors.New("booth masih punya sesi yang belum selesai")
+	ErrPaymentRequired     = errors.New("sesi berbayar harus dilunasi lewat payment gateway")
+	ErrPackageUnavailable  = pkgUcase.ErrPackageUnavailable
+)
+
+// expireBatchSize membatasi jumlah sesi yang ditutup per putaran sweeper.
+const expireBatchSize = 100
+
 type TransactionUsecase interface {
-	CreateSession(boothID, tenantID uuid.UUID, req domain.StartSessionRequest) (*domain.Transaction, error)
+	CreateSession(boothID, tenantID uuid.UUID, req domain.StartSessionRequest) (*domain.Transaction, bool, error)
+	GetSession(tenantID, id uuid.UUID, actor domain.TransitionActor) (*domain.Transaction, error)
+	Transition(tenantID, id uuid.UUID, req domain.TransitionRequest, actor domain.TransitionActor) (*domain.Transaction, error)
+	History(tenantID, id uuid.UUID, actor domain.TransitionActor) ([]domain.TransactionEvent, error)
+	ActiveSession(tenantID, boothID uuid.UUID) (*domain.Transaction, error)
+	FailStale(unpaidTimeout, paidTimeout time.Duration) (int, error)
 }
 
 type transactionUsecase struct {
-	repo repository.TransactionRepository
+	repo     repository.TransactionRepository
+	packages pkgUcase.PackageUsecase
 }
 
-func NewTransactionUsecase(repo repository.TransactionRepository) TransactionUsecase {
-	return &transactionUsecase{repo}
+func NewTransactionUsecase(repo repository.TransactionRepository, packages pkgUcase.PackageUsecase) TransactionUsecase {
+	return &transactionUsecase{repo, packages}
 }
 
-func (u *transactionUsecase) CreateSession(boothID, tenantID uuid.UUID, req domain.StartSessionRequest) (*domain.Transaction, error) {
+// CreateSession mencatat sesi baru di status created. Booth lalu memajukan
+// statusnya lewat Transition (bayar, foto, render, cetak, selesai).
+//
+// Harga, jumlah jepretan, dan kuota cetak diambil dari paket yang dipilih
+// (harus ditawarkan di booth ini) lalu disalin ke sesi.
+//
+// Retry dengan reference_no yang sama dan paket yang sama mengembalikan sesi
+// yang sudah ada dengan created = false, jadi booth aman mengulang request
+// setelah timeout. Booth yang masih punya sesi aktif ditolak dengan
+// ErrSessionActive; unique index parsial di DB menjaga kasus bersamaan.
+func (u *transactionUsecase) CreateSession(boothID, tenantID uuid.UUID, req domain.StartSessionRequest) (*domain.Transaction, bool, error) {
+	if existing, err := u.repo.FindByReference(tenantID, req.ReferenceNo); err == nil {
+		return sameSession(existing, boothID, req.PackageID)
+	}
+	if _, err := u.repo.FindActive(tenantID, boothID); err == nil {
+		return nil, false, ErrSessionActive
+	}
+
+	pkg, err := u.packages.ForSession(tenantID, boothID, req.PackageID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
 	trx := &domain.Transaction{
+		ID:                uuid.New(),
+		BoothID:           boothID,
+		TenantID:          tenantID,
+		ReferenceNo:       req.ReferenceNo,
+		Amount:            pkg.Price,
+		Status:            domain.TransCreated,
+		PrintQuota:        pkg.Prints,
+		PackageID:         &pkg.ID,
+		PackageName:       pkg.Name,
+		Shots:             pkg.Shots,
+		DigitalOnly:       pkg.DigitalOnly,
+		AnimationIncluded: pkg.AnimationIncluded,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	event := &domain.TransactionEvent{
 		ID:            uuid.New(),
-		BoothID:       boothID,
-		TenantID:      tenantID,
-		ReferenceNo:   req.ReferenceNo,
-		Amount:        req.Amount,
-		PaymentStatus: "completed",
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		TransactionID: trx.ID,
+		ToStatus:      domain.TransCreated,
+		Reason:        "paket " + pkg.Name,
+		BoothID:       &boothID,
+		CreatedAt:     now,
 	}
 
-	if err := u.repo.Save(trx); err != nil {
-		return nil, err
+	if err := u.repo.Save(trx, event); err != nil {
+		// Request bersamaan bisa lolos cek di atas dan kalah di unique constraint
+		if existing, findErr := u.repo.FindByReference(tenantID, req.ReferenceNo); findErr == nil {
+			return sameSession(existing, boothID, req.PackageID)
+		}
+		if _, findErr := u.repo.FindActive(tenantID, boothID); findErr == nil {
+			return nil, false, ErrSessionActive
+		}
+		return nil, false, err
+	}
+
+	return trx, true, nil
+}
+
+// sameSession memastikan sesi dengan reference_no yang sama memang retry
+// dari booth yang sama, bukan sesi lain di tenant ini yang kebetulan memakai
+// nomor itu. Tenant lain boleh memakai nomor yang sama.
+func sameSession(trx *domain.Transaction, boothID, packageID uuid.UUID) (*domain.Transaction, bool, error) {
+	if trx.BoothID != boothID || trx.PackageID == nil || *trx.PackageID != packageID {
+		return nil, false, ErrReferenceConflict
 	}
+	return trx, false, nil
+}
 
+// GetSession mengambil sesi milik tenant. Token device hanya bisa melihat
+// sesi dari booth-nya sendiri.
+func (u *transactionUsecase) GetSession(tenantID, id uuid.UUID, actor domain.TransitionActor) (*domain.Transaction, error) {
+	trx, err := u.repo.FindByID(tenantID, id)
+	if err != nil {
+		return nil, ErrTransactionNotFound
+	}
+	if actor.BoothID != nil && trx.BoothID != *actor.BoothID {
+		return nil, ErrTransactionNotFound
+	}
 	return trx, nil
 }
+
+func (u *transactionUsecase) Transition(tenantID, id uuid.UUID, req domain.TransitionRequest, actor domain.TransitionActor) (*domain.Transaction, error) {
+	trx, err := u.GetSession(tenantID, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	// Booth tidak boleh menandai sesi berbayar lunas sendiri; status paid
+	// datang dari webhook gateway, atau dari staff untuk pembayaran tunai
+	if actor.BoothID != nil && req.Status == domain.TransPaid && trx.Amount > 0 {
+		return nil, ErrPaymentRequired
+	}
+	if err := u.move(trx, req.Status, req.Reason, actor); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(tenantID, trx.ID)
+}
+
+// move memindahkan status sesi dan mencatat riwayatnya.
+func (u *transactionUsecase) move(trx *domain.Transaction, to domain.TransactionStatus, reason string, actor domain.TransitionActor) error {
+	if !trx.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s ke %s", ErrInvalidTransition, trx.Status, to)
+	}
+
+	now := time.Now()
+	moved, err := u.repo.Transition(trx.ID, trx.Status, to, now, &domain.TransactionEvent{
+		ID:            uuid.New(),
+		TransactionID: trx.ID,
+		FromStatus:    trx.Status,
+		ToStatus:      to,
+		Reason:        reason,
+		BoothID:       actor.BoothID,
+		UserID:        actor.UserID,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return err
+	}
+	if !moved {
+		// Ada perpindahan lain yang masuk duluan
+		return fmt.Errorf("%w: status sesi sudah berubah, muat ulang dulu", ErrInvalidTransition)
+	}
+	return nil
+}
+
+func (u *transactionUsecase) History(tenantID, id uuid.UUID, actor domain.TransitionActor) ([]domain.TransactionEvent, error) {
+	trx, err := u.GetSession(tenantID, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.Events(trx.ID)
+}
+
+// ActiveSession mengembalikan sesi booth yang belum berakhir, atau nil kalau
+// tidak ada. Dipakai booth untuk melanjutkan sesi setelah reconnect.
+func (u *transactionUsecase) ActiveSession(tenantID, boothID uuid.UUID) (*domain.Transaction, error) {
+	trx, err := u.repo.FindActive(tenantID, boothID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return trx, err
+}
+
+// FailStale menandai failed sesi aktif yang statusnya tidak berubah terlalu
+// lama, supaya booth-nya bisa memulai sesi baru. Sesi yang belum dibayar
+// memakai unpaidTimeout; sesi yang sudah dibayar (sedang foto, render, atau
+// cetak) memakai paidTimeout yang lebih panjang. Alasannya dicatat di riwayat
+// sesi, jadi sesi berbayar yang gagal bisa ditelusuri staf.
+func (u *transactionUsecase) FailStale(unpaidTimeout, paidTimeout time.Duration) (int, error) {
+	paidActive := slices.DeleteFunc(slices.Clone(domain.ActiveStatuses), func(s domain.TransactionStatus) bool {
+		return slices.Contains(domain.UnpaidActiveStatuses, s)
+	})
+
+	failed := 0
+	for _, sweep := range []struct {
+		statuses []domain.TransactionStatus
+		timeout  time.Duration
+	}{
+		{domain.UnpaidActiveStatuses, unpaidTimeout},
+		{paidActive, paidTimeout},
+	} {
+		trxs, err := u.repo.FindStale(sweep.statuses, time.Now().Add(-sweep.timeout), expireBatchSize)
+		if err != nil {
+			return failed, err
+		}
+
+		for i := range trxs {
+			reason := fmt.Sprintf("tertahan di status %s lebih dari %s", trxs[i].Status, sweep.timeout)
+			err := u.move(&trxs[i], domain.TransFailed, reason, domain.TransitionActor{})
+			if errors.Is(err, ErrInvalidTransition) {
+				// Booth memajukan sesi ini di saat yang sama
+				continue
+			}
+			if err != nil {
+				return failed, err
+			}
+			failed++
+		}
+	}
+	return failed, nil
+}